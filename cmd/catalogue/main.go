@@ -0,0 +1,15 @@
+package main
+
+import (
+	"os"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/trustwallet/assets-manager/internal/services/catalogue"
+)
+
+func main() {
+	if err := catalogue.Run(os.Args[1:], os.Stdout); err != nil {
+		log.WithError(err).Fatal("catalogue command failed")
+	}
+}
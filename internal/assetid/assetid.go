@@ -0,0 +1,216 @@
+// Package assetid validates token IDs by the address formats of the chains of their asset types.
+// It is shared by the validation API and the PR checks of the consumer.
+package assetid
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/btcsuite/btcutil/base58"
+	"github.com/btcsuite/btcutil/bech32"
+
+	str "github.com/trustwallet/assets-go-libs/strings"
+	"github.com/trustwallet/assets-manager/internal/findings"
+	"github.com/trustwallet/go-primitives/address"
+	"github.com/trustwallet/go-primitives/coin"
+	"github.com/trustwallet/go-primitives/types"
+)
+
+var (
+	ErrEmptyID   = errors.New("token id cannot be empty")
+	ErrInvalidID = errors.New("invalid token id")
+)
+
+const (
+	tronAddressVersion = 0x41
+	tronAddressLength  = 20
+	solanaKeyLength    = 32
+	wavesAssetLength   = 32
+)
+
+// nolint:gochecknoglobals // compiled once
+var (
+	regexHex40   = regexp.MustCompile(`^0x[0-9a-fA-F]{40}$`)
+	regexTRC10   = regexp.MustCompile(`^[0-9]{1,10}$`)
+	regexBEP2    = regexp.MustCompile(`^[A-Z0-9]{2,8}-[0-9A-F]{3}$`)
+	regexBEP8    = regexp.MustCompile(`^[A-Z0-9]{2,8}-[0-9A-F]{3}M$`)
+	regexDenom   = regexp.MustCompile(`^(ibc/[0-9A-F]{64}|[a-z][a-z0-9/:._-]{2,127})$`)
+	regexESDT    = regexp.MustCompile(`^[A-Z0-9]{3,10}-[0-9a-f]{6}$`)
+	regexStellar = regexp.MustCompile(`^[A-Za-z0-9]{1,12}-G[A-Z2-7]{55}$`)
+)
+
+type validator func(id string, tokenType types.TokenType) error
+
+// validators of token IDs by chain ID, EVM chains are validated by validateEVM.
+// nolint:gochecknoglobals // lookup table
+var validators = map[uint]validator{
+	coin.TRON:     validateTron,
+	coin.SOLANA:   validateSolana,
+	coin.BINANCE:  validateBinance,
+	coin.TERRA:    validateCosmos("terra"),
+	coin.KAVA:     validateCosmos("kava"),
+	coin.WAVES:    validateWaves,
+	coin.ELROND:   validateElrond,
+	coin.STELLAR:  validateStellar,
+	coin.VECHAIN:  validateHex,
+	coin.ARBITRUM: validateEVM(coin.Arbitrum()),
+	coin.CELO:     validateEVM(coin.Celo()),
+}
+
+// Validate checks a token ID by the format of the chain of its asset type.
+// IDs of unknown types and chains without a known format are only checked to be not empty.
+func Validate(id, tokenType string) error {
+	if id == "" {
+		return ErrEmptyID
+	}
+
+	// Unknown types are reported by the type check.
+	chain, err := types.GetChainFromAssetType(tokenType)
+	if err != nil {
+		return nil
+	}
+
+	if coin.IsEVM(chain.ID) {
+		return validateEVM(chain)(id, types.TokenType(tokenType))
+	}
+
+	if validate, ok := validators[chain.ID]; ok {
+		return validate(id, types.TokenType(tokenType))
+	}
+
+	return nil
+}
+
+// validateEVM checks an address is in the EIP-55 checksum format, Wanchain uses it with the reversed case.
+func validateEVM(chain coin.Coin) validator {
+	return func(id string, _ types.TokenType) error {
+		if err := validateHex(id, ""); err != nil {
+			return err
+		}
+
+		checksum, err := address.EIP55Checksum(id)
+		if err != nil {
+			return fmt.Errorf("failed to get checksum for %s: %w", id, err)
+		}
+
+		if chain.ID == coin.WANCHAIN {
+			checksum = strings.ReplaceAll(str.ReverseCase(checksum), "X", "x")
+		}
+
+		if checksum != id {
+			return findings.WithFix(fmt.Errorf("id is not in checksum format, should be %s (not %s). "+
+				"Please rename it. You may need to rename to a temp name first, "+
+				"then to the checksum format, because lowercase-uppercase-only renames "+
+				"are often ignored by the Git client or the filesystem", checksum, id),
+				fmt.Sprintf("rename the asset to %s", checksum))
+		}
+
+		return nil
+	}
+}
+
+func validateHex(id string, _ types.TokenType) error {
+	if !regexHex40.MatchString(id) {
+		return fmt.Errorf("%w: %s is not a 0x-prefixed 20-byte hex address", ErrInvalidID, id)
+	}
+
+	return nil
+}
+
+// validateTron checks a TRC10 token number or a TRC20 base58check address.
+func validateTron(id string, tokenType types.TokenType) error {
+	if tokenType == types.TRC10 {
+		if !regexTRC10.MatchString(id) {
+			return fmt.Errorf("%w: %s is not a TRC10 token number", ErrInvalidID, id)
+		}
+
+		return nil
+	}
+
+	payload, version, err := base58.CheckDecode(id)
+	if err != nil || version != tronAddressVersion || len(payload) != tronAddressLength {
+		return fmt.Errorf("%w: %s is not a base58check Tron address starting with T", ErrInvalidID, id)
+	}
+
+	return nil
+}
+
+// validateSolana checks a base58 mint address of 32 bytes.
+func validateSolana(id string, _ types.TokenType) error {
+	if len(base58.Decode(id)) != solanaKeyLength {
+		return fmt.Errorf("%w: %s is not a base58 Solana mint address", ErrInvalidID, id)
+	}
+
+	return nil
+}
+
+// validateBinance checks a BEP2 symbol, e.g. BUSD-BD1, or a BEP8 mini token symbol, e.g. MINI-123M.
+func validateBinance(id string, tokenType types.TokenType) error {
+	if tokenType == types.BEP8 {
+		if !regexBEP8.MatchString(id) {
+			return fmt.Errorf("%w: %s is not a BEP8 symbol, e.g. MINI-123M", ErrInvalidID, id)
+		}
+
+		return nil
+	}
+
+	if !regexBEP2.MatchString(id) {
+		return fmt.Errorf("%w: %s is not a BEP2 symbol, e.g. BUSD-BD1", ErrInvalidID, id)
+	}
+
+	return nil
+}
+
+// validateCosmos checks a bech32 contract address of a Cosmos-family chain or a native denom.
+func validateCosmos(hrp string) validator {
+	return func(id string, _ types.TokenType) error {
+		if !strings.HasPrefix(id, hrp+"1") {
+			if !regexDenom.MatchString(id) {
+				return fmt.Errorf("%w: %s is not a %s1 address or a denom", ErrInvalidID, id, hrp)
+			}
+
+			return nil
+		}
+
+		decodedHRP, data, err := bech32.Decode(id)
+		if err != nil || decodedHRP != hrp {
+			return fmt.Errorf("%w: %s is not a bech32 %s1 address", ErrInvalidID, id, hrp)
+		}
+
+		if decoded, convErr := bech32.ConvertBits(data, 5, 8, false); convErr != nil ||
+			(len(decoded) != 20 && len(decoded) != 32) {
+			return fmt.Errorf("%w: %s is not a 20 or 32-byte %s1 address", ErrInvalidID, id, hrp)
+		}
+
+		return nil
+	}
+}
+
+// validateWaves checks a base58 asset ID of 32 bytes.
+func validateWaves(id string, _ types.TokenType) error {
+	if len(base58.Decode(id)) != wavesAssetLength {
+		return fmt.Errorf("%w: %s is not a base58 Waves asset ID", ErrInvalidID, id)
+	}
+
+	return nil
+}
+
+// validateElrond checks an ESDT identifier, e.g. RIDE-7d18e9.
+func validateElrond(id string, _ types.TokenType) error {
+	if !regexESDT.MatchString(id) {
+		return fmt.Errorf("%w: %s is not an ESDT identifier, e.g. RIDE-7d18e9", ErrInvalidID, id)
+	}
+
+	return nil
+}
+
+// validateStellar checks an asset of a code and an issuer account, e.g. USDC-GA5Z...
+func validateStellar(id string, _ types.TokenType) error {
+	if !regexStellar.MatchString(id) {
+		return fmt.Errorf("%w: %s is not an asset code and issuer, e.g. USDC-GA5Z...", ErrInvalidID, id)
+	}
+
+	return nil
+}
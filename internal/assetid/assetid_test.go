@@ -0,0 +1,87 @@
+package assetid
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/btcsuite/btcutil/bech32"
+
+	"github.com/trustwallet/assets-manager/internal/findings"
+)
+
+func encodeBech32(t *testing.T, hrp string, size int) string {
+	t.Helper()
+
+	data, err := bech32.ConvertBits(make([]byte, size), 8, 5, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	encoded, err := bech32.Encode(hrp, data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return encoded
+}
+
+func Test_Validate(t *testing.T) {
+	invalid := errors.New("")
+
+	tests := []struct {
+		name      string
+		id        string
+		tokenType string
+		wantErr   error
+		wantFix   bool
+	}{
+		{name: "Empty", id: "", tokenType: "ERC20", wantErr: ErrEmptyID},
+		{name: "Unknown type", id: "anything", tokenType: "UNKNOWN"},
+		{name: "ERC20", id: "0xdAC17F958D2ee523a2206206994597C13D831ec7", tokenType: "ERC20"},
+		{name: "ERC20 not checksum", id: "0xdac17f958d2ee523a2206206994597c13d831ec7", tokenType: "ERC20",
+			wantErr: invalid, wantFix: true},
+		{name: "ERC20 not hex", id: "0xdAC17F958D2ee523a2206206994597C13D831ecZ", tokenType: "ERC20", wantErr: ErrInvalidID},
+		{name: "Arbitrum", id: "0xdAC17F958D2ee523a2206206994597C13D831ec7", tokenType: "ARBITRUM"},
+		{name: "TRC20", id: "TR7NHqjeKQxGTCi8q8ZY4pL8otSzgjLj6t", tokenType: "TRC20"},
+		{name: "TRC20 bad checksum", id: "TR7NHqjeKQxGTCi8q8ZY4pL8otSzgjLj6T", tokenType: "TRC20", wantErr: ErrInvalidID},
+		{name: "TRC10", id: "1002000", tokenType: "TRC10"},
+		{name: "TRC10 address", id: "TR7NHqjeKQxGTCi8q8ZY4pL8otSzgjLj6t", tokenType: "TRC10", wantErr: ErrInvalidID},
+		{name: "SPL", id: "EPjFWdd5AufqSSqeM2qN1xzybapC8G4wEGGkZwyTDt1v", tokenType: "SPL"},
+		{name: "SPL not base58", id: "0xdAC17F958D2ee523a2206206994597C13D831ec7", tokenType: "SPL", wantErr: ErrInvalidID},
+		{name: "BEP2", id: "BUSD-BD1", tokenType: "BEP2"},
+		{name: "BEP2 lowercase", id: "busd-bd1", tokenType: "BEP2", wantErr: ErrInvalidID},
+		{name: "BEP8", id: "MINI-123M", tokenType: "BEP8"},
+		{name: "BEP8 as BEP2", id: "MINI-123M", tokenType: "BEP2", wantErr: ErrInvalidID},
+		{name: "CW20", id: encodeBech32(t, "terra", 20), tokenType: "CW20"},
+		{name: "CW20 wrong length", id: encodeBech32(t, "terra", 16), tokenType: "CW20", wantErr: ErrInvalidID},
+		{name: "CW20 bad checksum", id: encodeBech32(t, "terra", 20)[:43] + "q", tokenType: "CW20", wantErr: ErrInvalidID},
+		{name: "Terra denom", id: "uusd", tokenType: "TERRA"},
+		{name: "Terra bad denom", id: "UUSD", tokenType: "TERRA", wantErr: ErrInvalidID},
+		{name: "Kava denom", id: "hard", tokenType: "KAVA"},
+		{name: "Waves", id: "DG2xFkPdDwKUoBkzGAhQtLpSGzfXLiCYPEzeKH2Ad24p", tokenType: "WAVES"},
+		{name: "Waves short", id: "DG2xFkPdDwKUoBkzGAhQtLp", tokenType: "WAVES", wantErr: ErrInvalidID},
+		{name: "ESDT", id: "RIDE-7d18e9", tokenType: "ESDT"},
+		{name: "ESDT no nonce", id: "RIDE", tokenType: "ESDT", wantErr: ErrInvalidID},
+		{name: "Stellar", id: "USDC-GA5ZSEJYB37JRC5AVCIA5MOP4RHTM335X2KGX3IHOJAPP5RE34K4KZVN", tokenType: "STELLAR"},
+		{name: "Stellar no issuer", id: "USDC", tokenType: "STELLAR", wantErr: ErrInvalidID},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Validate(tt.id, tt.tokenType)
+
+			switch {
+			case tt.wantErr == nil && err != nil:
+				t.Errorf("Validate() error = %v, want nil", err)
+			case tt.wantErr != nil && err == nil:
+				t.Errorf("Validate() error = nil, want %v", tt.wantErr)
+			case tt.wantErr != nil && tt.wantErr != invalid && !errors.Is(err, tt.wantErr):
+				t.Errorf("Validate() error = %v, want %v", err, tt.wantErr)
+			}
+
+			if tt.wantFix && findings.FixOf(err) == "" {
+				t.Errorf("Validate() error = %v, want a fix", err)
+			}
+		})
+	}
+}
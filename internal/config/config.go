@@ -26,9 +26,17 @@ type Configuration struct {
 	} `mapstructure:"rabbitmq"`
 
 	Consumer struct {
-		Workers int `mapstructure:"workers"`
+		Workers           int           `mapstructure:"workers"`
+		MaxAttempts       int           `mapstructure:"max_attempts"`
+		RetryDelay        time.Duration `mapstructure:"retry_delay"`
+		RetryPollInterval time.Duration `mapstructure:"retry_poll_interval"`
 	} `mapstructure:"consumer"`
 
+	Storage struct {
+		Driver string `mapstructure:"driver"`
+		Path   string `mapstructure:"path"`
+	} `mapstructure:"storage"`
+
 	Metrics struct {
 		Path        string `mapstructure:"path"`
 		PushGateway struct {
@@ -61,41 +69,81 @@ type Configuration struct {
 		BaseURL          string `mapstructure:"base_url"`
 		ClientID         string `mapstructure:"client_id"`
 		ClientSecret     string `mapstructure:"client_secret"`
+
+		Pagination struct {
+			PerPage  int `mapstructure:"per_page"`
+			MaxPages int `mapstructure:"max_pages"`
+		} `mapstructure:"pagination"`
+
+		RateLimit struct {
+			SlowBelow    float64       `mapstructure:"slow_below"`
+			SkipBelow    float64       `mapstructure:"skip_below"`
+			SlowInterval time.Duration `mapstructure:"slow_interval"`
+			MaxRetries   int           `mapstructure:"max_retries"`
+			Backoff      time.Duration `mapstructure:"backoff"`
+			MaxBackoff   time.Duration `mapstructure:"max_backoff"`
+		} `mapstructure:"rate_limit"`
+
+		Cache struct {
+			Driver     string        `mapstructure:"driver"`
+			MaxEntries int           `mapstructure:"max_entries"`
+			TTL        time.Duration `mapstructure:"ttl"`
+		} `mapstructure:"cache"`
+
+		CheckRun struct {
+			Enabled bool   `mapstructure:"enabled"`
+			Name    string `mapstructure:"name"`
+		} `mapstructure:"check_run"`
+
+		// Repos are repositories served by the bot. If empty, only RepoOwner/RepoName is served.
+		Repos []Repo `mapstructure:"repos"`
 	} `mapstructure:"github"`
 
 	Payment struct {
-		Options []struct {
-			Amount float64 `mapstructure:"amount"`
-			Symbol string  `mapstructure:"symbol"`
-			Token  string  `mapstructure:"token"`
-		} `mapstructure:"options"`
+		Options []PaymentOption `mapstructure:"options"`
+
+		Chains []PaymentChain `mapstructure:"chains"`
 
-		Address          string  `mapstructure:"address"`
 		SeedPhrase       string  `mapstructure:"seed_phrase"`
 		TolerancePercent float64 `mapstructure:"tolerance_percent"`
+
+		Ledger struct {
+			History      time.Duration `mapstructure:"history"`
+			SyncWindow   time.Duration `mapstructure:"sync_window"`
+			SyncInterval time.Duration `mapstructure:"sync_interval"`
+		} `mapstructure:"ledger"`
+
+		Refund struct {
+			DryRun             bool    `mapstructure:"dry_run"`
+			OverpaymentPercent float64 `mapstructure:"overpayment_percent"`
+		} `mapstructure:"refund"`
+
+		Reconciliation struct {
+			Interval       time.Duration `mapstructure:"interval"`
+			TimeWindow     time.Duration `mapstructure:"time_window"`
+			MinScore       float64       `mapstructure:"min_score"`
+			MaxSuggestions int           `mapstructure:"max_suggestions"`
+		} `mapstructure:"reconciliation"`
+
+		Burn struct {
+			Interval    time.Duration `mapstructure:"interval"`
+			MaxAttempts int           `mapstructure:"max_attempts"`
+			Backoff     time.Duration `mapstructure:"backoff"`
+			MaxBackoff  time.Duration `mapstructure:"max_backoff"`
+
+			Batch struct {
+				Enabled   bool          `mapstructure:"enabled"`
+				Interval  time.Duration `mapstructure:"interval"`
+				Threshold float64       `mapstructure:"threshold"`
+			} `mapstructure:"batch"`
+		} `mapstructure:"burn"`
 	} `mapstructure:"payment"`
 
-	Message struct {
-		Initial       string `mapstructure:"initial"`
-		NotReceived   string `mapstructure:"not_received"`
-		Received      string `mapstructure:"received"`
-		ReviewCreated string `mapstructure:"review_created"`
-		Reviewed      string `mapstructure:"reviewed"`
-		Reminder      string `mapstructure:"reminder"`
-		ClosingOldPR  string `mapstructure:"closing_old_pr"`
-		Burned        string `mapstructure:"burned"`
-	} `mapstructure:"message"`
+	Message Message `mapstructure:"message"`
 
-	Label struct {
-		Requested string `mapstructure:"requested"`
-		Paid      string `mapstructure:"paid"`
-	} `mapstructure:"label"`
+	Label Label `mapstructure:"label"`
 
-	UserAccess struct {
-		DeleteCommentsFromExternal bool   `mapstructure:"delete_comments_from_external"`
-		Collaborators              string `mapstructure:"collaborators"`
-		Moderators                 string `mapstructure:"moderators"`
-	} `mapstructure:"user_access"`
+	UserAccess UserAccess `mapstructure:"user_access"`
 
 	Timeout struct {
 		MaxAgeClose     time.Duration `mapstructure:"max_age_close"`
@@ -103,6 +151,10 @@ type Configuration struct {
 		BackgroundCheck time.Duration `mapstructure:"background_check"`
 	} `mapstructure:"timeout"`
 
+	Summary struct {
+		MaxHistory int `mapstructure:"max_history"`
+	} `mapstructure:"summary"`
+
 	Limitation struct {
 		PrFilesNumMax int `mapstructure:"pr_files_num_max"`
 	} `mapstructure:"limitation"`
@@ -115,6 +167,36 @@ type Configuration struct {
 			TagsMinRequired      int `mapstructure:"tags_min_required"`
 			HoldersMinRequired   int `mapstructure:"holders_min_required"`
 		} `mapstructure:"asset"`
+
+		External struct {
+			CacheTTL  time.Duration      `mapstructure:"cache_ttl"`
+			Timeout   time.Duration      `mapstructure:"timeout"`
+			Quorum    int                `mapstructure:"quorum"`
+			Providers []MetadataProvider `mapstructure:"providers"`
+		} `mapstructure:"external"`
+
+		Logo struct {
+			MaxFileSize  int64         `mapstructure:"max_file_size"`
+			FetchTimeout time.Duration `mapstructure:"fetch_timeout"`
+		} `mapstructure:"logo"`
+
+		Batch struct {
+			Workers     int   `mapstructure:"workers"`
+			MaxAssets   int   `mapstructure:"max_assets"`
+			MaxBodySize int64 `mapstructure:"max_body_size"`
+		} `mapstructure:"batch"`
+
+		Severity struct {
+			Rules  map[string]string            `mapstructure:"rules"`
+			Chains map[string]map[string]string `mapstructure:"chains"`
+		} `mapstructure:"severity"`
+
+		Impersonation struct {
+			Index        string  `mapstructure:"index"`
+			Threshold    float64 `mapstructure:"threshold"`
+			LogoDistance int     `mapstructure:"logo_distance"`
+			MaxMatches   int     `mapstructure:"max_matches"`
+		} `mapstructure:"impersonation"`
 	} `mapstructure:"validation"`
 
 	Tags []struct {
@@ -124,6 +206,63 @@ type Configuration struct {
 	} `mapstructure:"tags"`
 }
 
+type (
+	PaymentOption struct {
+		Amount float64 `mapstructure:"amount"`
+		Symbol string  `mapstructure:"symbol"`
+		Token  string  `mapstructure:"token"`
+		Chain  string  `mapstructure:"chain"`
+	}
+
+	PaymentChain struct {
+		Name     string `mapstructure:"name"`
+		Type     string `mapstructure:"type"`
+		Coin     uint   `mapstructure:"coin"`
+		Address  string `mapstructure:"address"`
+		API      string `mapstructure:"api"`
+		APIKey   string `mapstructure:"api_key"`
+		RPC      string `mapstructure:"rpc"`
+		Explorer string `mapstructure:"explorer"`
+	}
+
+	Message struct {
+		Initial       string `mapstructure:"initial"`
+		NotReceived   string `mapstructure:"not_received"`
+		Received      string `mapstructure:"received"`
+		ReviewCreated string `mapstructure:"review_created"`
+		Reviewed      string `mapstructure:"reviewed"`
+		Reminder      string `mapstructure:"reminder"`
+		ClosingOldPR  string `mapstructure:"closing_old_pr"`
+		Burned        string `mapstructure:"burned"`
+		BurnedBatch   string `mapstructure:"burned_batch"`
+		BurnFailed    string `mapstructure:"burn_failed"`
+		Overpaid      string `mapstructure:"overpaid"`
+		PaidClosedPR  string `mapstructure:"paid_closed_pr"`
+		Refunded      string `mapstructure:"refunded"`
+		RefundDryRun  string `mapstructure:"refund_dry_run"`
+		MatchSuggest  string `mapstructure:"match_suggest"`
+	}
+
+	Label struct {
+		Requested     string `mapstructure:"requested"`
+		Paid          string `mapstructure:"paid"`
+		Impersonation string `mapstructure:"impersonation"`
+	}
+
+	MetadataProvider struct {
+		Name  string   `mapstructure:"name"`
+		Kind  string   `mapstructure:"kind"`
+		URL   string   `mapstructure:"url"`
+		Types []string `mapstructure:"types"`
+	}
+
+	UserAccess struct {
+		DeleteCommentsFromExternal bool   `mapstructure:"delete_comments_from_external"`
+		Collaborators              string `mapstructure:"collaborators"`
+		Moderators                 string `mapstructure:"moderators"`
+	}
+)
+
 // Default is a configuration instance.
 var Default = Configuration{} // nolint:gochecknoglobals // config must be global
 
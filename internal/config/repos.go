@@ -0,0 +1,97 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// Repo is a repository served by the bot. Settings left empty are taken from the top level sections,
+// except user_access.delete_comments_from_external, which is global.
+type Repo struct {
+	Owner          string          `mapstructure:"owner"`
+	Name           string          `mapstructure:"name"`
+	PaymentOptions []PaymentOption `mapstructure:"payment_options"`
+	Label          Label           `mapstructure:"label"`
+	Message        Message         `mapstructure:"message"`
+	UserAccess     UserAccess      `mapstructure:"user_access"`
+}
+
+// FullName returns a repository name with its owner, e.g. trustwallet/assets.
+func (r *Repo) FullName() string {
+	return r.Owner + "/" + r.Name
+}
+
+// Repos returns repositories served by the bot with defaults applied. If no repositories are listed,
+// github.repo_owner/repo_name is served with the top level settings.
+func (c *Configuration) Repos() []Repo {
+	repos := c.Github.Repos
+	if len(repos) == 0 {
+		repos = []Repo{{Owner: c.Github.RepoOwner, Name: c.Github.RepoName}}
+	}
+
+	resolved := make([]Repo, len(repos))
+
+	for i, repo := range repos {
+		if len(repo.PaymentOptions) == 0 {
+			repo.PaymentOptions = c.Payment.Options
+		}
+
+		setDefaults(&repo.Label, c.Label)
+		setDefaults(&repo.Message, c.Message)
+		setDefaults(&repo.UserAccess, c.UserAccess)
+		repo.UserAccess.DeleteCommentsFromExternal = c.UserAccess.DeleteCommentsFromExternal
+
+		resolved[i] = repo
+	}
+
+	return resolved
+}
+
+// GetRepo returns a served repository by owner and name, which are case-insensitive as on Github.
+func (c *Configuration) GetRepo(owner, name string) (Repo, bool) {
+	for _, repo := range c.Repos() {
+		if strings.EqualFold(repo.Owner, owner) && strings.EqualFold(repo.Name, name) {
+			return repo, true
+		}
+	}
+
+	return Repo{}, false
+}
+
+// CheckPaymentChains returns an error if repositories share a payment chain. Payments are matched
+// to pull requests by memo, which is a PR number unique per repository only.
+func (c *Configuration) CheckPaymentChains() error {
+	owners := make(map[string]string)
+
+	for _, repo := range c.Repos() {
+		for _, option := range repo.PaymentOptions {
+			owner, ok := owners[option.Chain]
+			if ok && owner != repo.FullName() {
+				return fmt.Errorf("payment chain '%s' is used by both %s and %s", option.Chain, owner, repo.FullName())
+			}
+
+			owners[option.Chain] = repo.FullName()
+		}
+	}
+
+	return nil
+}
+
+// IsLegacyRepo reports if a repository is the one served before multiple repositories were supported.
+// Its data is stored without a repository prefix.
+func (c *Configuration) IsLegacyRepo(repo *Repo) bool {
+	return strings.EqualFold(repo.Owner, c.Github.RepoOwner) && strings.EqualFold(repo.Name, c.Github.RepoName)
+}
+
+// setDefaults sets empty string fields of a struct pointed by dst to the same fields of defaults.
+func setDefaults(dst, defaults interface{}) {
+	d := reflect.ValueOf(dst).Elem()
+	def := reflect.ValueOf(defaults)
+
+	for i := 0; i < d.NumField(); i++ {
+		if f := d.Field(i); f.Kind() == reflect.String && f.String() == "" {
+			f.SetString(def.Field(i).String())
+		}
+	}
+}
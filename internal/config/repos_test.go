@@ -0,0 +1,83 @@
+package config
+
+import "testing"
+
+func TestConfiguration_Repos(t *testing.T) {
+	var c Configuration
+
+	c.Github.RepoOwner = "trustwallet"
+	c.Github.RepoName = "assets"
+	c.Payment.Options = []PaymentOption{{Amount: 700, Symbol: "TWT", Token: "TWT-8C2", Chain: "binance"}}
+	c.Label = Label{Requested: "Payment Status: Requested", Paid: "Payment Status: Paid"}
+	c.Message.Initial = "Please pay"
+	c.Message.Reminder = "Kind reminder"
+	c.UserAccess = UserAccess{DeleteCommentsFromExternal: true, Collaborators: "alice", Moderators: "bob"}
+
+	repos := c.Repos()
+	if len(repos) != 1 || repos[0].FullName() != "trustwallet/assets" || repos[0].Label != c.Label {
+		t.Fatalf("Repos() without repos = %+v, want the legacy repo with top level settings", repos)
+	}
+
+	c.Github.Repos = []Repo{
+		{Owner: "trustwallet", Name: "assets"},
+		{
+			Owner:          "staging",
+			Name:           "assets",
+			PaymentOptions: []PaymentOption{{Amount: 1, Symbol: "BNB", Token: "BNB", Chain: "binance"}},
+			Label:          Label{Paid: "Paid"},
+			Message:        Message{Initial: "Staging"},
+			UserAccess:     UserAccess{Moderators: "carol"},
+		},
+	}
+
+	staging, ok := c.GetRepo("Staging", "Assets")
+	if !ok {
+		t.Fatal("GetRepo() did not find a repo in a different case")
+	}
+
+	want := Repo{
+		Owner:          "staging",
+		Name:           "assets",
+		PaymentOptions: []PaymentOption{{Amount: 1, Symbol: "BNB", Token: "BNB", Chain: "binance"}},
+		Label:          Label{Requested: "Payment Status: Requested", Paid: "Paid"},
+		UserAccess:     UserAccess{DeleteCommentsFromExternal: true, Collaborators: "alice", Moderators: "carol"},
+	}
+	want.Message = c.Message
+	want.Message.Initial = "Staging"
+
+	if staging.Label != want.Label || staging.Message != want.Message || staging.UserAccess != want.UserAccess ||
+		len(staging.PaymentOptions) != 1 || staging.PaymentOptions[0] != want.PaymentOptions[0] {
+		t.Errorf("GetRepo() = %+v, want %+v", staging, want)
+	}
+
+	if c.IsLegacyRepo(&staging) || !c.IsLegacyRepo(&c.Repos()[0]) {
+		t.Error("IsLegacyRepo() should only match github.repo_owner/repo_name")
+	}
+
+	if _, ok = c.GetRepo("unknown", "assets"); ok {
+		t.Error("GetRepo() found an unknown repo")
+	}
+}
+
+func TestConfiguration_CheckPaymentChains(t *testing.T) {
+	var c Configuration
+
+	c.Payment.Options = []PaymentOption{
+		{Symbol: "TWT", Token: "TWT-8C2", Chain: "binance"},
+		{Symbol: "BNB", Token: "BNB", Chain: "binance"},
+	}
+	c.Github.Repos = []Repo{
+		{Owner: "trustwallet", Name: "assets"},
+		{Owner: "staging", Name: "assets", PaymentOptions: []PaymentOption{{Symbol: "BNB", Token: "BNB", Chain: "smartchain"}}},
+	}
+
+	if err := c.CheckPaymentChains(); err != nil {
+		t.Errorf("CheckPaymentChains() of repos paid on different chains error = %v", err)
+	}
+
+	c.Github.Repos[1].PaymentOptions = nil
+
+	if err := c.CheckPaymentChains(); err == nil {
+		t.Error("CheckPaymentChains() of repos paid on the same chain error = nil")
+	}
+}
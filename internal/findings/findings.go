@@ -0,0 +1,137 @@
+// Package findings is the result model of asset checks shared by the API and the consumer.
+// Checks report findings, which are rendered as API responses, Github markdown and check run annotations.
+package findings
+
+import (
+	"errors"
+)
+
+type Severity string
+
+const (
+	SeverityError   Severity = "error"
+	SeverityWarning Severity = "warning"
+	SeverityInfo    Severity = "info"
+)
+
+// Rule IDs of the checks.
+const (
+	RuleAssetID               = "asset_id"
+	RuleAssetType             = "asset_type"
+	RuleAssetDecimals         = "asset_decimals"
+	RuleAssetDescription      = "asset_description"
+	RuleAssetWebsite          = "asset_website"
+	RuleAssetExplorer         = "asset_explorer"
+	RuleAssetStatus           = "asset_status"
+	RuleAssetLinks            = "asset_links"
+	RuleAssetTags             = "asset_tags"
+	RuleAssetHolders          = "asset_holders"
+	RuleAssetHoldersUnchecked = "asset_holders_unchecked"
+	RuleAssetRequiredKeys     = "asset_required_keys"
+	RuleAssetInfo             = "asset_info"
+	RuleLogo                  = "logo"
+	RuleLogoFormat            = "logo_format"
+	RuleLogoDimensions        = "logo_dimensions"
+	RuleLogoSize              = "logo_size"
+	RulePRFiles               = "pr_files"
+	RulePRFileNotAllowed      = "pr_file_not_allowed"
+	RulePRFileDeleted         = "pr_file_deleted"
+	RuleValidatorsList        = "validators_list"
+	RuleValidatorsIncomplete  = "validators_incomplete"
+	RuleAssetDuplicate        = "asset_duplicate"
+	RuleAssetSymbolClash      = "asset_symbol_clash"
+	RuleAssetDenylisted       = "asset_denylisted"
+	RuleTokenlist             = "tokenlist"
+	RuleAllowlist             = "allowlist"
+	RuleAssetImpersonation    = "asset_impersonation"
+	RuleAssetSymbol           = "asset_symbol"
+	RuleAssetSupply           = "asset_supply"
+	RuleAssetMetadataConflict = "asset_metadata_conflict"
+)
+
+// Finding is a problem found by a check.
+type Finding struct {
+	Rule     string   `json:"rule"`
+	Severity Severity `json:"severity"`
+	// Field is a field of info.json the finding is about, if any.
+	Field   string `json:"field,omitempty"`
+	Message string `json:"message"`
+	// Fix is a suggested fix, if known.
+	Fix string `json:"fix,omitempty"`
+	// Path and Line locate the finding in a repository file, if known.
+	Path string `json:"path,omitempty"`
+	Line int    `json:"line,omitempty"`
+}
+
+// New returns a finding of a rule failed with an error. A fix of the error set by WithFix is suggested.
+func New(rule string, severity Severity, field string, err error) Finding {
+	return Finding{
+		Rule:     rule,
+		Severity: severity,
+		Field:    field,
+		Message:  err.Error(),
+		Fix:      FixOf(err),
+	}
+}
+
+// At returns the finding located at a line of a repository file.
+func (f Finding) At(path string, line int) Finding {
+	f.Path = path
+	f.Line = line
+
+	return f
+}
+
+type fixError struct {
+	err error
+	fix string
+}
+
+func (e *fixError) Error() string {
+	return e.err.Error()
+}
+
+func (e *fixError) Unwrap() error {
+	return e.err
+}
+
+// WithFix annotates an error of a check with a suggested fix.
+func WithFix(err error, fix string) error {
+	return &fixError{err: err, fix: fix}
+}
+
+// FixOf returns a fix suggested for an error, if any.
+func FixOf(err error) string {
+	var fe *fixError
+	if errors.As(err, &fe) {
+		return fe.fix
+	}
+
+	return ""
+}
+
+// HasErrors reports whether any of the findings is an error.
+func HasErrors(list []Finding) bool {
+	for _, f := range list {
+		if f.Severity == SeverityError {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Unique returns findings without repeats, e.g. the same finding of a check of the consumer and the API.
+func Unique(list []Finding) []Finding {
+	seen := make(map[Finding]bool, len(list))
+	unique := make([]Finding, 0, len(list))
+
+	for _, f := range list {
+		if !seen[f] {
+			seen[f] = true
+			unique = append(unique, f)
+		}
+	}
+
+	return unique
+}
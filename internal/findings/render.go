@@ -0,0 +1,90 @@
+package findings
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/google/go-github/v38/github"
+)
+
+// Annotation levels of Github check runs.
+const (
+	annotationFailure = "failure"
+	annotationWarning = "warning"
+	annotationNotice  = "notice"
+)
+
+// Markdown renders findings as Github markdown, one per line.
+func Markdown(list []Finding) string {
+	var b strings.Builder
+
+	for _, f := range list {
+		b.WriteString(icon(f.Severity) + " " + f.Message)
+
+		if f.Fix != "" {
+			fmt.Fprintf(&b, " (fix: %s)", f.Fix)
+		}
+
+		b.WriteString("\n")
+	}
+
+	return b.String()
+}
+
+// Annotations renders findings located in files as check run annotations.
+func Annotations(list []Finding) []*github.CheckRunAnnotation {
+	annotations := make([]*github.CheckRunAnnotation, 0, len(list))
+
+	for _, f := range list {
+		if f.Path == "" {
+			continue
+		}
+
+		line := f.Line
+		if line < 1 {
+			line = 1
+		}
+
+		message := f.Message
+		if f.Fix != "" {
+			message += "\nFix: " + f.Fix
+		}
+
+		annotations = append(annotations, &github.CheckRunAnnotation{
+			Path:            github.String(f.Path),
+			StartLine:       github.Int(line),
+			EndLine:         github.Int(line),
+			AnnotationLevel: github.String(annotationLevel(f.Severity)),
+			Title:           github.String(f.Rule),
+			Message:         github.String(message),
+		})
+	}
+
+	return annotations
+}
+
+func icon(severity Severity) string {
+	switch severity {
+	case SeverityError:
+		return "❌"
+	case SeverityWarning:
+		return "⚠️"
+	case SeverityInfo:
+		return "ℹ️"
+	default:
+		return "❌"
+	}
+}
+
+func annotationLevel(severity Severity) string {
+	switch severity {
+	case SeverityError:
+		return annotationFailure
+	case SeverityWarning:
+		return annotationWarning
+	case SeverityInfo:
+		return annotationNotice
+	default:
+		return annotationFailure
+	}
+}
@@ -0,0 +1,76 @@
+package findings
+
+import (
+	"errors"
+	"testing"
+)
+
+func Test_New(t *testing.T) {
+	err := WithFix(errors.New("decimals value is incorrect: expected 18 instead of 8"), "set decimals to 18")
+
+	got := New(RuleAssetDecimals, SeverityError, "decimals", err)
+	want := Finding{
+		Rule:     RuleAssetDecimals,
+		Severity: SeverityError,
+		Field:    "decimals",
+		Message:  "decimals value is incorrect: expected 18 instead of 8",
+		Fix:      "set decimals to 18",
+	}
+
+	if got != want {
+		t.Errorf("New() = %+v, want %+v", got, want)
+	}
+}
+
+func Test_Markdown(t *testing.T) {
+	tests := []struct {
+		name string
+		list []Finding
+		want string
+	}{
+		{name: "Empty", list: nil, want: ""},
+		{
+			name: "Severities and fixes",
+			list: []Finding{
+				{Severity: SeverityError, Message: "explorer field incorrect", Fix: "set explorer to https://a"},
+				{Severity: SeverityWarning, Message: "number of holders not checked"},
+				{Severity: SeverityInfo, Message: "logo is optimized"},
+			},
+			want: "❌ explorer field incorrect (fix: set explorer to https://a)\n" +
+				"⚠️ number of holders not checked\n" +
+				"ℹ️ logo is optimized\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Markdown(tt.list); got != tt.want {
+				t.Errorf("Markdown() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_Annotations(t *testing.T) {
+	list := []Finding{
+		{Rule: RulePRFiles, Severity: SeverityError, Message: "too many files"},
+		Finding{Rule: RuleAssetDecimals, Severity: SeverityError, Message: "bad decimals", Fix: "set decimals to 18"}.
+			At("blockchains/ethereum/assets/0x0/info.json", 5),
+		Finding{Rule: RuleAssetHolders, Severity: SeverityWarning, Message: "holders not checked"}.
+			At("blockchains/ethereum/assets/0x0/info.json", 0),
+	}
+
+	got := Annotations(list)
+	if len(got) != 2 {
+		t.Fatalf("Annotations() = %d annotations, want 2", len(got))
+	}
+
+	if got[0].GetStartLine() != 5 || got[0].GetAnnotationLevel() != "failure" ||
+		got[0].GetMessage() != "bad decimals\nFix: set decimals to 18" || got[0].GetTitle() != RuleAssetDecimals {
+		t.Errorf("Annotations()[0] = %+v", got[0])
+	}
+
+	if got[1].GetStartLine() != 1 || got[1].GetAnnotationLevel() != "warning" {
+		t.Errorf("Annotations()[1] = %+v", got[1])
+	}
+}
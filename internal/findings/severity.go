@@ -0,0 +1,66 @@
+package findings
+
+import (
+	log "github.com/sirupsen/logrus"
+
+	"github.com/trustwallet/assets-manager/internal/config"
+)
+
+// SeverityOff disables a rule by a severity override.
+const SeverityOff Severity = "off"
+
+// Overrides returns severities of the rules overridden in config for a chain, "" for the rules ones only.
+func Overrides(chain string) map[string]Severity {
+	overrides := make(map[string]Severity)
+
+	add := func(rules map[string]string) {
+		for rule, value := range rules {
+			severity := Severity(value)
+
+			switch severity {
+			case SeverityError, SeverityWarning, SeverityInfo, SeverityOff:
+				overrides[rule] = severity
+			default:
+				log.WithFields(log.Fields{"rule": rule, "severity": value}).Warn("Unknown severity override ignored")
+			}
+		}
+	}
+
+	add(config.Default.Validation.Severity.Rules)
+
+	if chain != "" {
+		add(config.Default.Validation.Severity.Chains[chain])
+	}
+
+	return overrides
+}
+
+// Override sets severities of the findings of the rules overridden, findings of the rules turned off are removed.
+func Override(list []Finding, overrides map[string]Severity) []Finding {
+	result := make([]Finding, 0, len(list))
+
+	for _, f := range list {
+		if severity, ok := overrides[f.Rule]; ok {
+			if severity == SeverityOff {
+				continue
+			}
+
+			f.Severity = severity
+		}
+
+		result = append(result, f)
+	}
+
+	return result
+}
+
+// HasWarnings reports whether any of the findings is a warning.
+func HasWarnings(list []Finding) bool {
+	for _, f := range list {
+		if f.Severity == SeverityWarning {
+			return true
+		}
+	}
+
+	return false
+}
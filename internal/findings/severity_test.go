@@ -0,0 +1,65 @@
+package findings
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/trustwallet/assets-manager/internal/config"
+)
+
+func Test_Overrides(t *testing.T) {
+	config.Default.Validation.Severity.Rules = map[string]string{
+		RuleAssetHoldersUnchecked: "info",
+		RuleAssetLinks:            "sometimes",
+	}
+	config.Default.Validation.Severity.Chains = map[string]map[string]string{
+		"smartchain": {RuleAssetHolders: "warning", RuleAssetHoldersUnchecked: "off"},
+	}
+
+	defer func() {
+		config.Default.Validation.Severity.Rules = nil
+		config.Default.Validation.Severity.Chains = nil
+	}()
+
+	tests := []struct {
+		chain string
+		want  map[string]Severity
+	}{
+		{chain: "", want: map[string]Severity{RuleAssetHoldersUnchecked: SeverityInfo}},
+		{chain: "ethereum", want: map[string]Severity{RuleAssetHoldersUnchecked: SeverityInfo}},
+		{
+			chain: "smartchain",
+			want:  map[string]Severity{RuleAssetHoldersUnchecked: SeverityOff, RuleAssetHolders: SeverityWarning},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.chain, func(t *testing.T) {
+			if got := Overrides(tt.chain); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Overrides() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_Override(t *testing.T) {
+	list := []Finding{
+		{Rule: RuleAssetHolders, Severity: SeverityError},
+		{Rule: RuleAssetHoldersUnchecked, Severity: SeverityWarning},
+		{Rule: RuleAssetDecimals, Severity: SeverityError},
+	}
+
+	got := Override(list, map[string]Severity{RuleAssetHolders: SeverityWarning, RuleAssetHoldersUnchecked: SeverityOff})
+	want := []Finding{
+		{Rule: RuleAssetHolders, Severity: SeverityWarning},
+		{Rule: RuleAssetDecimals, Severity: SeverityError},
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Override() = %v, want %v", got, want)
+	}
+
+	if list[0].Severity != SeverityError {
+		t.Error("Override() modified the findings passed")
+	}
+}
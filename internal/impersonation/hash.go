@@ -0,0 +1,85 @@
+package impersonation
+
+import (
+	"image"
+	"math"
+	"math/bits"
+	"sort"
+
+	"golang.org/x/image/draw"
+)
+
+const (
+	// Logos are hashed by low frequencies of the DCT of a sample, 8x8 of 32x32.
+	hashSide   = 8
+	sampleSide = 32
+	hashBits   = hashSide * hashSide
+)
+
+// nolint:gochecknoglobals // computed once
+var dctCos = func() [hashSide][sampleSide]float64 {
+	var table [hashSide][sampleSide]float64
+
+	for u := 0; u < hashSide; u++ {
+		for x := 0; x < sampleSide; x++ {
+			table[u][x] = math.Cos(float64(2*x+1) * float64(u) * math.Pi / (2 * sampleSide))
+		}
+	}
+
+	return table
+}()
+
+// Hash returns a perceptual hash of a logo. Similar images have hashes at a small Hamming distance,
+// regardless of their size, compression and slight color changes. Transparency is blended on white.
+func Hash(img image.Image) uint64 {
+	sample := image.NewNRGBA(image.Rect(0, 0, sampleSide, sampleSide))
+	draw.ApproxBiLinear.Scale(sample, sample.Bounds(), img, img.Bounds(), draw.Src, nil)
+
+	var luma [sampleSide][sampleSide]float64
+
+	for y := 0; y < sampleSide; y++ {
+		for x := 0; x < sampleSide; x++ {
+			p := sample.Pix[y*sample.Stride+x*4 : y*sample.Stride+x*4+4]
+			a := float64(p[3]) / 255
+			l := 0.299*float64(p[0]) + 0.587*float64(p[1]) + 0.114*float64(p[2])
+			luma[y][x] = l*a + 255*(1-a)
+		}
+	}
+
+	var coefs [hashBits]float64
+
+	for v := 0; v < hashSide; v++ {
+		for u := 0; u < hashSide; u++ {
+			var sum float64
+
+			for y := 0; y < sampleSide; y++ {
+				for x := 0; x < sampleSide; x++ {
+					sum += luma[y][x] * dctCos[u][x] * dctCos[v][y]
+				}
+			}
+
+			coefs[v*hashSide+u] = sum
+		}
+	}
+
+	// The DC term is the average brightness, it is left out of the median.
+	sorted := make([]float64, hashBits-1)
+	copy(sorted, coefs[1:])
+	sort.Float64s(sorted)
+	median := (sorted[len(sorted)/2-1] + sorted[len(sorted)/2]) / 2
+
+	var hash uint64
+
+	for i, c := range coefs {
+		if c > median {
+			hash |= 1 << uint(i)
+		}
+	}
+
+	return hash
+}
+
+// Distance returns the Hamming distance of two hashes, 0 for the same images and up to 64.
+func Distance(a, b uint64) int {
+	return bits.OnesCount64(a ^ b)
+}
@@ -0,0 +1,159 @@
+package impersonation
+
+import (
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"golang.org/x/image/draw"
+)
+
+// newLogo returns a logo of a circle, its color and position vary by seed.
+func newLogo(size, seed int) *image.NRGBA {
+	img := image.NewNRGBA(image.Rect(0, 0, size, size))
+	fill := color.NRGBA{R: uint8(seed * 70), G: uint8(255 - seed*50), B: uint8(seed * 30), A: 255}
+	cx, cy, r := size*(2+seed%3)/6, size*(3-seed%2)/6, size/3
+
+	for y := 0; y < size; y++ {
+		for x := 0; x < size; x++ {
+			if (x-cx)*(x-cx)+(y-cy)*(y-cy) <= r*r {
+				img.Set(x, y, fill)
+			}
+		}
+	}
+
+	return img
+}
+
+func Test_Hash(t *testing.T) {
+	logo := newLogo(256, 1)
+
+	resized := image.NewNRGBA(image.Rect(0, 0, 128, 128))
+	draw.CatmullRom.Scale(resized, resized.Bounds(), logo, logo.Bounds(), draw.Src, nil)
+
+	if d := Distance(Hash(logo), Hash(resized)); d > 4 {
+		t.Errorf("Distance() of a resized logo = %d, want <= 4", d)
+	}
+
+	if d := Distance(Hash(logo), Hash(newLogo(256, 2))); d < 10 {
+		t.Errorf("Distance() of another logo = %d, want >= 10", d)
+	}
+}
+
+func Test_Similarity(t *testing.T) {
+	tests := []struct {
+		a, b string
+		min  float64
+		max  float64
+	}{
+		{a: "USDT", b: "usdt", min: 1, max: 1},
+		{a: "U$D-Т", b: "USDT", min: 1, max: 1},
+		{a: "Tether", b: "Tether USD", min: 0.9, max: 0.9},
+		{a: "Uniswap", b: "Unisvvap", min: 0.7, max: 0.8},
+		{a: "Bitcoin", b: "Shiba Inu", min: 0, max: 0.3},
+		{a: "", b: "USDT", min: 0, max: 0},
+	}
+
+	for _, tt := range tests {
+		if got := Similarity(tt.a, tt.b); got < tt.min || got > tt.max {
+			t.Errorf("Similarity(%q, %q) = %v, want %v..%v", tt.a, tt.b, got, tt.min, tt.max)
+		}
+	}
+}
+
+func writeAsset(t *testing.T, root, chain, id, info string, logo image.Image) {
+	t.Helper()
+
+	dir := filepath.Join(root, "blockchains", chain, "assets", id)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "info.json"), []byte(info), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	file, err := os.Create(filepath.Join(dir, "logo.png"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	if err = png.Encode(file, logo); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func Test_Match(t *testing.T) {
+	root := t.TempDir()
+	writeAsset(t, root, "ethereum", "0xUSDT", `{"name": "Tether", "symbol": "USDT", "status": "active"}`,
+		newLogo(256, 1))
+	writeAsset(t, root, "ethereum", "0xUNI", `{"name": "Uniswap", "symbol": "UNI", "status": "active"}`,
+		newLogo(256, 2))
+	writeAsset(t, root, "ethereum", "0xSCAM", `{"name": "Tether", "symbol": "USDT", "status": "spam"}`,
+		newLogo(256, 1))
+
+	built, err := Build(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	name := filepath.Join(t.TempDir(), "index.json")
+	if err = built.Save(name); err != nil {
+		t.Fatal(err)
+	}
+
+	index, err := Load(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(index.Entries) != 2 || index.Entries[0].Hash != built.Entries[0].Hash {
+		t.Fatalf("Load() entries = %+v, want %+v", index.Entries, built.Entries)
+	}
+
+	opts := Options{Threshold: 0.85, LogoDistance: 8, MaxMatches: 3}
+
+	tests := []struct {
+		name      string
+		candidate Candidate
+		want      []string
+	}{
+		{
+			name:      "Same logo",
+			candidate: Candidate{Chain: "smartchain", ID: "0x1", Name: "Free Money", Symbol: "FREE", Logo: newLogo(128, 1)},
+			want:      []string{"0xUSDT"},
+		},
+		{
+			name:      "Similar name and symbol",
+			candidate: Candidate{Chain: "smartchain", ID: "0x1", Name: "Tether USD", Symbol: "U$DT"},
+			want:      []string{"0xUSDT"},
+		},
+		{
+			name:      "Token itself",
+			candidate: Candidate{Chain: "ethereum", ID: "0xusdt", Name: "Tether", Symbol: "USDT", Logo: newLogo(256, 1)},
+		},
+		{
+			name:      "Unrelated",
+			candidate: Candidate{Chain: "ethereum", ID: "0x1", Name: "Some Token", Symbol: "SOME", Logo: newLogo(256, 3)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			matches := index.Match(tt.candidate, opts)
+
+			ids := make([]string, 0)
+			for _, m := range matches {
+				ids = append(ids, m.ID)
+			}
+
+			if len(ids) != len(tt.want) || (len(ids) > 0 && ids[0] != tt.want[0]) {
+				t.Errorf("Match() = %+v, want %v", matches, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,223 @@
+// Package impersonation scores new tokens against the asset catalogue to flag likely impersonations
+// of existing tokens by a similar logo, name or symbol.
+package impersonation
+
+import (
+	"encoding/json"
+	"fmt"
+	"image"
+	_ "image/png" // logos of the catalogue are PNG
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+const statusActive = "active"
+
+// Entry is a token of the catalogue.
+type Entry struct {
+	Chain  string `json:"chain"`
+	ID     string `json:"id"`
+	Name   string `json:"name"`
+	Symbol string `json:"symbol"`
+	// Hash is a perceptual hash of the logo, if HasLogo.
+	Hash    uint64 `json:"hash,string"`
+	HasLogo bool   `json:"has_logo"`
+}
+
+// Index is the reference catalogue of active tokens new tokens are compared to.
+type Index struct {
+	Built   time.Time `json:"built"`
+	Entries []Entry   `json:"entries"`
+}
+
+// Candidate is a token submitted in a PR.
+type Candidate struct {
+	Chain  string
+	ID     string
+	Name   string
+	Symbol string
+	// Logo is nil if there is no valid logo.
+	Logo image.Image
+}
+
+type Options struct {
+	// Threshold is the min score of a match, from 0 to 1.
+	Threshold float64
+	// LogoDistance is the max distance of hashes of similar logos.
+	LogoDistance int
+	MaxMatches   int
+}
+
+// Match is a token of the catalogue a candidate is similar to.
+type Match struct {
+	Entry
+	Score float64 `json:"score"`
+	// LogoDistance is the distance of the logo hashes, -1 if logos were not compared.
+	LogoDistance     int     `json:"logo_distance"`
+	NameSimilarity   float64 `json:"name_similarity"`
+	SymbolSimilarity float64 `json:"symbol_similarity"`
+}
+
+// Reasons describes what is similar.
+func (m *Match) Reasons() string {
+	reasons := make([]string, 0)
+
+	if m.LogoDistance >= 0 {
+		reasons = append(reasons, fmt.Sprintf("logo distance %d", m.LogoDistance))
+	}
+
+	reasons = append(reasons,
+		fmt.Sprintf("symbol %.0f%%", m.SymbolSimilarity*100),
+		fmt.Sprintf("name %.0f%%", m.NameSimilarity*100))
+
+	return strings.Join(reasons, ", ")
+}
+
+// Match returns tokens of the catalogue a candidate is likely to impersonate, the most similar first.
+// A candidate is scored by its logo if it is close to a logo of the catalogue, and by its name and symbol.
+// The token itself is skipped, so changes of existing tokens are not reported.
+func (i *Index) Match(c Candidate, opts Options) []Match {
+	var hash uint64
+	if c.Logo != nil {
+		hash = Hash(c.Logo)
+	}
+
+	matches := make([]Match, 0)
+
+	for _, e := range i.Entries {
+		if e.Chain == c.Chain && strings.EqualFold(e.ID, c.ID) {
+			continue
+		}
+
+		m := Match{
+			Entry:            e,
+			LogoDistance:     -1,
+			NameSimilarity:   Similarity(c.Name, e.Name),
+			SymbolSimilarity: Similarity(c.Symbol, e.Symbol),
+		}
+
+		m.Score = (m.NameSimilarity + m.SymbolSimilarity) / 2
+
+		if c.Logo != nil && e.HasLogo {
+			m.LogoDistance = Distance(hash, e.Hash)
+
+			if logoScore := 1 - float64(m.LogoDistance)/hashBits; m.LogoDistance <= opts.LogoDistance &&
+				logoScore > m.Score {
+				m.Score = logoScore
+			}
+		}
+
+		if m.Score >= opts.Threshold {
+			matches = append(matches, m)
+		}
+	}
+
+	sort.SliceStable(matches, func(a, b int) bool {
+		return matches[a].Score > matches[b].Score
+	})
+
+	if opts.MaxMatches > 0 && len(matches) > opts.MaxMatches {
+		matches = matches[:opts.MaxMatches]
+	}
+
+	return matches
+}
+
+// Build indexes active tokens of a local checkout of the assets repository.
+// Tokens with invalid info.json are skipped, tokens with invalid logos are indexed by name and symbol only.
+func Build(root string) (*Index, error) {
+	infos, err := filepath.Glob(filepath.Join(root, "blockchains", "*", "assets", "*", "info.json"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list assets: %w", err)
+	}
+
+	if len(infos) == 0 {
+		return nil, fmt.Errorf("no assets found in %s", root)
+	}
+
+	index := &Index{Built: time.Now().UTC(), Entries: make([]Entry, 0, len(infos))}
+
+	for _, infoPath := range infos {
+		dir := filepath.Dir(infoPath)
+
+		entry, ok := readEntry(infoPath)
+		if !ok {
+			continue
+		}
+
+		entry.ID = filepath.Base(dir)
+		entry.Chain = filepath.Base(filepath.Dir(filepath.Dir(dir)))
+
+		if img, imgErr := readLogo(filepath.Join(dir, "logo.png")); imgErr == nil {
+			entry.Hash = Hash(img)
+			entry.HasLogo = true
+		}
+
+		index.Entries = append(index.Entries, entry)
+	}
+
+	return index, nil
+}
+
+func readEntry(infoPath string) (Entry, bool) {
+	data, err := os.ReadFile(infoPath)
+	if err != nil {
+		return Entry{}, false
+	}
+
+	var info struct {
+		Name   string `json:"name"`
+		Symbol string `json:"symbol"`
+		Status string `json:"status"`
+	}
+
+	if err = json.Unmarshal(data, &info); err != nil || info.Status != statusActive {
+		return Entry{}, false
+	}
+
+	return Entry{Name: info.Name, Symbol: info.Symbol}, true
+}
+
+func readLogo(name string) (image.Image, error) {
+	file, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	img, _, err := image.Decode(file)
+
+	return img, err
+}
+
+// Load reads an index saved by Save.
+func Load(name string) (*Index, error) {
+	data, err := os.ReadFile(name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read index: %w", err)
+	}
+
+	index := &Index{}
+	if err = json.Unmarshal(data, index); err != nil {
+		return nil, fmt.Errorf("failed to parse index: %w", err)
+	}
+
+	return index, nil
+}
+
+// Save writes an index to a file.
+func (i *Index) Save(name string) error {
+	data, err := json.Marshal(i)
+	if err != nil {
+		return fmt.Errorf("failed to marshal index: %w", err)
+	}
+
+	if err = os.WriteFile(name, data, 0o600); err != nil {
+		return fmt.Errorf("failed to write index: %w", err)
+	}
+
+	return nil
+}
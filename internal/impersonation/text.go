@@ -0,0 +1,100 @@
+package impersonation
+
+import (
+	"strings"
+	"unicode"
+)
+
+const (
+	// minContained is the min length of a name contained in another one to be similar,
+	// e.g. "Tether" in "Tether USD", which have containedSimilarity.
+	minContained        = 3
+	containedSimilarity = 0.9
+)
+
+// nolint:gochecknoglobals // lookup table
+var confusables = map[rune]rune{
+	'0': 'o', '1': 'l', '3': 'e', '5': 's', '$': 's', '|': 'l',
+	// Cyrillic letters looking like Latin ones.
+	'а': 'a', 'в': 'b', 'е': 'e', 'к': 'k', 'м': 'm', 'н': 'h', 'о': 'o', 'р': 'p', 'с': 'c', 'т': 't',
+	'у': 'y', 'х': 'x', 'і': 'i',
+}
+
+// normalize returns a name or a symbol lowercase, look-alike characters replaced and the rest but letters
+// and digits removed, so "U$D-Т" (Cyrillic T) and "usdt" are the same.
+func normalize(s string) string {
+	var b strings.Builder
+
+	for _, r := range strings.ToLower(s) {
+		if c, ok := confusables[r]; ok {
+			r = c
+		}
+
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			b.WriteRune(r)
+		}
+	}
+
+	return b.String()
+}
+
+// Similarity returns how similar two names or symbols are, from 0 to 1 for the same ones after normalization.
+func Similarity(a, b string) float64 {
+	na, nb := []rune(normalize(a)), []rune(normalize(b))
+	if len(na) == 0 || len(nb) == 0 {
+		return 0
+	}
+
+	longest := len(na)
+	if len(nb) > longest {
+		longest = len(nb)
+	}
+
+	similarity := 1 - float64(levenshtein(na, nb))/float64(longest)
+
+	shortest := len(na) + len(nb) - longest
+	if shortest >= minContained && similarity < containedSimilarity &&
+		(strings.Contains(string(na), string(nb)) || strings.Contains(string(nb), string(na))) {
+		return containedSimilarity
+	}
+
+	return similarity
+}
+
+func levenshtein(a, b []rune) int {
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+
+			curr[j] = min3(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+
+		prev, curr = curr, prev
+	}
+
+	return prev[len(b)]
+}
+
+func min3(a, b, c int) int {
+	if b < a {
+		a = b
+	}
+
+	if c < a {
+		a = c
+	}
+
+	return a
+}
@@ -0,0 +1,244 @@
+package logo
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/color/palette"
+	"image/png"
+
+	"github.com/srwiley/oksvg"
+	"github.com/srwiley/rasterx"
+	"golang.org/x/image/draw"
+	_ "golang.org/x/image/webp" // register WebP to fix WebP logos
+
+	"github.com/trustwallet/assets-go-libs/validation"
+	"github.com/trustwallet/assets-manager/internal/findings"
+)
+
+const (
+	FormatSVG = "svg"
+
+	// maxSourcePixels limits images decoded for fixing.
+	maxSourcePixels = 4096 * 4096
+	// scaleStep is a factor the logo is scaled down by until it fits the size limit.
+	scaleStep = 0.8
+)
+
+var ErrImageTooLarge = errors.New("image is too large")
+
+// FixReport describes changes made to a logo by Fix.
+type FixReport struct {
+	Original Info     `json:"original"`
+	Result   Info     `json:"result"`
+	Actions  []string `json:"actions"`
+	// Findings are findings of the result, empty if the logo has been fixed.
+	Findings []findings.Finding `json:"findings"`
+}
+
+// Fix converts a PNG, JPEG, GIF, WebP or SVG image to a PNG logo of the accepted dimensions
+// under the size limit. A logo which can't be fixed completely is returned with its findings in the report.
+func Fix(data []byte) ([]byte, *FixReport, error) {
+	report := &FixReport{Actions: make([]string, 0)}
+
+	img, original, err := decode(data)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	report.Original = *original
+
+	if original.Format != FormatPNG {
+		report.Actions = append(report.Actions, fmt.Sprintf("converted from %s to png", original.Format))
+	}
+
+	size := squareSize(img.Bounds().Dx(), img.Bounds().Dy())
+	if img.Bounds().Dx() != img.Bounds().Dy() {
+		report.Actions = append(report.Actions, fmt.Sprintf("padded from %dx%d to a square",
+			img.Bounds().Dx(), img.Bounds().Dy()))
+	}
+
+	result, err := encode(img, size)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// The logo is scaled down until it fits the size limit, then reduced to a palette as the last resort.
+	for validation.ValidateLogoStreamSize(result) != nil && size > validation.MinW {
+		size = int(float64(size) * scaleStep)
+		if size < validation.MinW {
+			size = validation.MinW
+		}
+
+		if result, err = encode(img, size); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	if validation.ValidateLogoStreamSize(result) != nil {
+		if result, err = encodePaletted(img, size); err != nil {
+			return nil, nil, err
+		}
+
+		report.Actions = append(report.Actions, "reduced to a 256 color palette")
+	}
+
+	if size != img.Bounds().Dx() || size != img.Bounds().Dy() {
+		report.Actions = append(report.Actions, fmt.Sprintf("resized to %dx%d", size, size))
+	}
+
+	info, found := Check(result)
+	report.Result = *info
+	report.Findings = found
+
+	if len(result) < len(data) && original.Format == FormatPNG {
+		report.Actions = append(report.Actions, fmt.Sprintf("compressed from %d to %d bytes", len(data), len(result)))
+	}
+
+	return result, report, nil
+}
+
+// decode returns an image of raster formats registered or SVG, rasterized at the max logo size.
+func decode(data []byte) (image.Image, *Info, error) {
+	info := &Info{Size: len(data)}
+
+	if isSVG(data) {
+		img, err := rasterizeSVG(data, validation.MaxW)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		info.Format = FormatSVG
+		info.Width = img.Bounds().Dx()
+		info.Height = img.Bounds().Dy()
+
+		return img, info, nil
+	}
+
+	config, format, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to decode image: %w", err)
+	}
+
+	if config.Width*config.Height > maxSourcePixels {
+		return nil, nil, fmt.Errorf("%w: %dx%d", ErrImageTooLarge, config.Width, config.Height)
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to decode image: %w", err)
+	}
+
+	info.Format = format
+	info.Width = config.Width
+	info.Height = config.Height
+	info.AlphaChannel = hasAlphaChannel(config.ColorModel)
+
+	return img, info, nil
+}
+
+func isSVG(data []byte) bool {
+	head := data
+	if len(head) > 1024 {
+		head = head[:1024]
+	}
+
+	return bytes.Contains(bytes.ToLower(head), []byte("<svg"))
+}
+
+func rasterizeSVG(data []byte, size int) (image.Image, error) {
+	icon, err := oksvg.ReadIconStream(bytes.NewReader(data), oksvg.IgnoreErrorMode)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse svg: %w", err)
+	}
+
+	if icon.ViewBox.W <= 0 || icon.ViewBox.H <= 0 {
+		return nil, errors.New("failed to parse svg: no view box")
+	}
+
+	// The longer side is rasterized at the size, the aspect ratio is kept.
+	w, h := size, size
+	if icon.ViewBox.W > icon.ViewBox.H {
+		h = int(float64(size) * icon.ViewBox.H / icon.ViewBox.W)
+	} else {
+		w = int(float64(size) * icon.ViewBox.W / icon.ViewBox.H)
+	}
+
+	img := image.NewNRGBA(image.Rect(0, 0, w, h))
+	icon.SetTarget(0, 0, float64(w), float64(h))
+	icon.Draw(rasterx.NewDasher(w, h, rasterx.NewScannerGV(w, h, img, img.Bounds())), 1)
+
+	return img, nil
+}
+
+// squareSize returns a side of the square logo for an image, its longer side within the accepted dimensions.
+func squareSize(width, height int) int {
+	size := width
+	if height > size {
+		size = height
+	}
+
+	if size > validation.MaxW {
+		return validation.MaxW
+	}
+
+	if size < validation.MinW {
+		return validation.MinW
+	}
+
+	return size
+}
+
+// fit draws an image scaled to fit a transparent square, centered.
+func fit(img image.Image, size int) *image.NRGBA {
+	bounds := img.Bounds()
+	scale := float64(size) / float64(squareSide(bounds))
+
+	w := int(float64(bounds.Dx())*scale + 0.5)
+	h := int(float64(bounds.Dy())*scale + 0.5)
+	x := (size - w) / 2
+	y := (size - h) / 2
+
+	dst := image.NewNRGBA(image.Rect(0, 0, size, size))
+	draw.CatmullRom.Scale(dst, image.Rect(x, y, x+w, y+h), img, bounds, draw.Over, nil)
+
+	return dst
+}
+
+func squareSide(bounds image.Rectangle) int {
+	if bounds.Dx() > bounds.Dy() {
+		return bounds.Dx()
+	}
+
+	return bounds.Dy()
+}
+
+func encode(img image.Image, size int) ([]byte, error) {
+	var buf bytes.Buffer
+
+	encoder := png.Encoder{CompressionLevel: png.BestCompression}
+	if err := encoder.Encode(&buf, fit(img, size)); err != nil {
+		return nil, fmt.Errorf("failed to encode png: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// encodePaletted encodes a logo with the web-safe palette and a transparent color.
+func encodePaletted(img image.Image, size int) ([]byte, error) {
+	src := fit(img, size)
+
+	colors := append(palette.WebSafe[:len(palette.WebSafe):len(palette.WebSafe)], image.Transparent)
+	dst := image.NewPaletted(src.Bounds(), colors)
+	draw.FloydSteinberg.Draw(dst, dst.Bounds(), src, image.Point{})
+
+	var buf bytes.Buffer
+
+	encoder := png.Encoder{CompressionLevel: png.BestCompression}
+	if err := encoder.Encode(&buf, dst); err != nil {
+		return nil, fmt.Errorf("failed to encode png: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,80 @@
+package logo
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"math/rand"
+	"testing"
+
+	"github.com/trustwallet/assets-go-libs/validation"
+)
+
+func Test_Fix(t *testing.T) {
+	var jpegLogo bytes.Buffer
+	if err := jpeg.Encode(&jpegLogo, newImage(256, color.NRGBA{R: 200, A: 255}), nil); err != nil {
+		t.Fatal(err)
+	}
+
+	wide := image.NewNRGBA(image.Rect(0, 0, 300, 150))
+	for i := range wide.Pix {
+		wide.Pix[i] = 255
+	}
+
+	noise := image.NewNRGBA(image.Rect(0, 0, 1024, 1024))
+	rand.New(rand.NewSource(1)).Read(noise.Pix)
+
+	svg := []byte(`<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 100 50">` +
+		`<rect width="100" height="50" fill="#ff0000"/></svg>`)
+
+	tests := []struct {
+		name       string
+		data       []byte
+		wantFormat string
+		wantSize   int
+	}{
+		{name: "JPEG", data: jpegLogo.Bytes(), wantFormat: "jpeg", wantSize: 256},
+		{name: "Non-square", data: encodePNG(t, wide), wantFormat: FormatPNG, wantSize: 300},
+		{name: "Too small", data: encodePNG(t, newImage(64, color.Black)), wantFormat: FormatPNG, wantSize: 128},
+		{name: "Too large file", data: encodePNG(t, noise), wantFormat: FormatPNG},
+		{name: "SVG", data: svg, wantFormat: FormatSVG, wantSize: validation.MaxW},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fixed, report, err := Fix(tt.data)
+			if err != nil {
+				t.Fatalf("Fix() error = %v", err)
+			}
+
+			if report.Original.Format != tt.wantFormat {
+				t.Errorf("Fix() original format = %s, want %s", report.Original.Format, tt.wantFormat)
+			}
+
+			if len(report.Findings) != 0 {
+				t.Errorf("Fix() findings = %v, want none", report.Findings)
+			}
+
+			if report.Result.Format != FormatPNG || report.Result.Width != report.Result.Height {
+				t.Errorf("Fix() result = %+v, want a square png", report.Result)
+			}
+
+			if tt.wantSize != 0 && report.Result.Width != tt.wantSize {
+				t.Errorf("Fix() result width = %d, want %d", report.Result.Width, tt.wantSize)
+			}
+
+			if err = validation.ValidateLogoStreamSize(fixed); err != nil {
+				t.Errorf("Fix() result size: %v", err)
+			}
+		})
+	}
+}
+
+func Test_Fix_Invalid(t *testing.T) {
+	for _, data := range [][]byte{[]byte("not an image"), []byte("<svg></svg>")} {
+		if _, _, err := Fix(data); err == nil {
+			t.Errorf("Fix(%q) error = nil, want an error", data)
+		}
+	}
+}
@@ -0,0 +1,116 @@
+// Package logo checks logo images with the rules applied to logos of assets repository PRs.
+package logo
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	_ "image/gif"  // register GIF to report the format of not PNG logos
+	_ "image/jpeg" // register JPEG to report the format of not PNG logos
+	"image/png"
+
+	"github.com/trustwallet/assets-go-libs/validation"
+	"github.com/trustwallet/assets-manager/internal/findings"
+)
+
+const FormatPNG = "png"
+
+// Info describes a logo image.
+type Info struct {
+	Format string `json:"format"`
+	Width  int    `json:"width"`
+	Height int    `json:"height"`
+	Size   int    `json:"size"`
+	// AlphaChannel is whether the color model of the image supports transparency.
+	AlphaChannel bool `json:"alpha_channel"`
+	// Transparent is whether any pixel of the image is not fully opaque.
+	// It's reported for PNG images of valid dimensions only.
+	Transparent bool `json:"transparent"`
+}
+
+// Check returns info of a logo and findings of the logo rules.
+func Check(data []byte) (*Info, []findings.Finding) {
+	info := &Info{Size: len(data)}
+	found := make([]findings.Finding, 0)
+
+	config, format, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		found = append(found, findings.New(findings.RuleLogo, findings.SeverityError, "",
+			fmt.Errorf("failed to decode logo: %w", err)))
+	} else {
+		info.Format = format
+		info.Width = config.Width
+		info.Height = config.Height
+		info.AlphaChannel = hasAlphaChannel(config.ColorModel)
+
+		found = append(found, checkImage(info, data)...)
+	}
+
+	if err = validation.ValidateLogoStreamSize(data); err != nil {
+		found = append(found, findings.New(findings.RuleLogoSize, findings.SeverityError, "",
+			findings.WithFix(err, "compress the logo")))
+	}
+
+	return info, found
+}
+
+func checkImage(info *Info, data []byte) []findings.Finding {
+	found := make([]findings.Finding, 0)
+
+	if info.Format != FormatPNG {
+		found = append(found, findings.New(findings.RuleLogoFormat, findings.SeverityError, "",
+			findings.WithFix(fmt.Errorf("logo must be a PNG image, given %s", info.Format), "convert the logo to PNG")))
+	}
+
+	if err := validation.ValidateImageDimension(info.Width, info.Height); err != nil {
+		return append(found, findings.New(findings.RuleLogoDimensions, findings.SeverityError, "",
+			findings.WithFix(err, fmt.Sprintf("resize the logo to a square of %dx%d to %dx%d",
+				validation.MinW, validation.MinH, validation.MaxW, validation.MaxH))))
+	}
+
+	// Images are decoded only once their dimensions are known to be within the limits.
+	if info.Format == FormatPNG && info.AlphaChannel {
+		info.Transparent = isTransparent(data)
+	}
+
+	return found
+}
+
+func hasAlphaChannel(model color.Model) bool {
+	if palette, ok := model.(color.Palette); ok {
+		for _, c := range palette {
+			if _, _, _, a := c.RGBA(); a != 0xffff {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	switch model {
+	case color.RGBAModel, color.RGBA64Model, color.NRGBAModel, color.NRGBA64Model,
+		color.AlphaModel, color.Alpha16Model:
+		return true
+	default:
+		return false
+	}
+}
+
+func isTransparent(data []byte) bool {
+	img, err := png.Decode(bytes.NewReader(data))
+	if err != nil {
+		return false
+	}
+
+	bounds := img.Bounds()
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			if _, _, _, a := img.At(x, y).RGBA(); a != 0xffff {
+				return true
+			}
+		}
+	}
+
+	return false
+}
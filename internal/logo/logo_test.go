@@ -0,0 +1,119 @@
+package logo
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"math/rand"
+	"testing"
+
+	"github.com/trustwallet/assets-manager/internal/findings"
+)
+
+func encodePNG(t *testing.T, img image.Image) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatal(err)
+	}
+
+	return buf.Bytes()
+}
+
+func newImage(size int, fill color.Color) *image.NRGBA {
+	img := image.NewNRGBA(image.Rect(0, 0, size, size))
+	for y := 0; y < size; y++ {
+		for x := 0; x < size; x++ {
+			img.Set(x, y, fill)
+		}
+	}
+
+	return img
+}
+
+func Test_Check(t *testing.T) {
+	opaque := newImage(256, color.NRGBA{R: 10, G: 20, B: 30, A: 255})
+
+	transparent := newImage(256, color.NRGBA{R: 10, G: 20, B: 30, A: 255})
+	transparent.Set(0, 0, color.NRGBA{})
+
+	noise := image.NewNRGBA(image.Rect(0, 0, 512, 512))
+	rnd := rand.New(rand.NewSource(1))
+	rnd.Read(noise.Pix)
+
+	var jpegLogo bytes.Buffer
+	if err := jpeg.Encode(&jpegLogo, opaque, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name      string
+		data      []byte
+		wantInfo  Info
+		wantRules []string
+	}{
+		{
+			name:     "Valid opaque",
+			data:     encodePNG(t, opaque),
+			wantInfo: Info{Format: "png", Width: 256, Height: 256, AlphaChannel: true},
+		},
+		{
+			name:     "Valid transparent",
+			data:     encodePNG(t, transparent),
+			wantInfo: Info{Format: "png", Width: 256, Height: 256, AlphaChannel: true, Transparent: true},
+		},
+		{
+			name:      "Too small",
+			data:      encodePNG(t, newImage(64, color.NRGBA{A: 255})),
+			wantInfo:  Info{Format: "png", Width: 64, Height: 64, AlphaChannel: true},
+			wantRules: []string{findings.RuleLogoDimensions},
+		},
+		{
+			name:      "JPEG",
+			data:      jpegLogo.Bytes(),
+			wantInfo:  Info{Format: "jpeg", Width: 256, Height: 256},
+			wantRules: []string{findings.RuleLogoFormat},
+		},
+		{
+			name:      "Too large file",
+			data:      encodePNG(t, noise),
+			wantInfo:  Info{Format: "png", Width: 512, Height: 512, AlphaChannel: true, Transparent: true},
+			wantRules: []string{findings.RuleLogoSize},
+		},
+		{
+			name:      "Not an image",
+			data:      []byte("not an image"),
+			wantInfo:  Info{},
+			wantRules: []string{findings.RuleLogo},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			info, found := Check(tt.data)
+
+			tt.wantInfo.Size = len(tt.data)
+			if *info != tt.wantInfo {
+				t.Errorf("Check() info = %+v, want %+v", *info, tt.wantInfo)
+			}
+
+			rules := make([]string, 0, len(found))
+			for _, f := range found {
+				rules = append(rules, f.Rule)
+			}
+
+			if len(rules) != len(tt.wantRules) {
+				t.Fatalf("Check() rules = %v, want %v", rules, tt.wantRules)
+			}
+
+			for i := range rules {
+				if rules[i] != tt.wantRules[i] {
+					t.Errorf("Check() rules = %v, want %v", rules, tt.wantRules)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,113 @@
+package onchain
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/trustwallet/assets-manager/internal/findings"
+)
+
+// Check returns findings of info.json values compared with metadata of the token. Nil metadata,
+// e.g. of a token type no provider knows, leaves the number of holders to be checked manually.
+// Findings have fields of info.json to locate them.
+func Check(metadata *Result, decimals *int, symbol *string, holdersMinRequired int) []findings.Finding {
+	checks := []struct {
+		rule     string
+		field    string
+		severity findings.Severity
+		check    func() error
+	}{
+		{findings.RuleAssetDecimals, "decimals", findings.SeverityError, func() error {
+			return checkDecimals(decimals, metadata)
+		}},
+		{findings.RuleAssetSymbol, "symbol", findings.SeverityWarning, func() error {
+			return checkSymbol(symbol, metadata)
+		}},
+		{findings.RuleAssetHoldersUnchecked, "", findings.SeverityWarning, func() error {
+			return checkHoldersChecked(metadata)
+		}},
+		{findings.RuleAssetHolders, "", findings.SeverityError, func() error {
+			return checkHolders(metadata, holdersMinRequired)
+		}},
+		{findings.RuleAssetSupply, "", findings.SeverityWarning, func() error { return checkSupply(metadata) }},
+		{findings.RuleAssetMetadataConflict, "", findings.SeverityWarning, func() error {
+			return checkConflicts(metadata)
+		}},
+	}
+
+	found := make([]findings.Finding, 0)
+
+	for _, c := range checks {
+		if err := c.check(); err != nil {
+			found = append(found, findings.New(c.rule, c.severity, c.field, err))
+		}
+	}
+
+	return found
+}
+
+func checkDecimals(decimals *int, metadata *Result) error {
+	if decimals == nil || metadata == nil || metadata.Decimals == nil || *decimals == *metadata.Decimals {
+		return nil
+	}
+
+	return findings.WithFix(fmt.Errorf("decimals value is incorrect: expected %d instead of %d",
+		*metadata.Decimals, *decimals), fmt.Sprintf("set decimals to %d", *metadata.Decimals))
+}
+
+func checkSymbol(symbol *string, metadata *Result) error {
+	if symbol == nil || metadata == nil || metadata.Symbol == "" || *symbol == metadata.Symbol {
+		return nil
+	}
+
+	return findings.WithFix(fmt.Errorf("symbol differs from the token contract: expected %s instead of %s",
+		metadata.Symbol, *symbol), fmt.Sprintf("set symbol to %s", metadata.Symbol))
+}
+
+func checkHoldersChecked(metadata *Result) error {
+	if metadata == nil || metadata.Holders == nil {
+		return fmt.Errorf("number of holders not checked: please, check it manually")
+	}
+
+	return nil
+}
+
+func checkHolders(metadata *Result, holdersMinRequired int) error {
+	if metadata == nil || metadata.Holders == nil {
+		return nil
+	}
+
+	if *metadata.Holders < holdersMinRequired {
+		return fmt.Errorf("low token circulation: number of holders is %d, below limit of %d",
+			*metadata.Holders, holdersMinRequired)
+	}
+
+	return nil
+}
+
+func checkSupply(metadata *Result) error {
+	if metadata == nil || metadata.TotalSupply == nil {
+		return nil
+	}
+
+	if metadata.TotalSupply.Sign() == 0 {
+		return fmt.Errorf("total supply of the token is 0, it has not been minted yet")
+	}
+
+	return nil
+}
+
+// checkConflicts reports fields the providers disagree on, which are not checked.
+func checkConflicts(metadata *Result) error {
+	if metadata == nil || len(metadata.Conflicts) == 0 {
+		return nil
+	}
+
+	conflicts := make([]string, 0, len(metadata.Conflicts))
+	for _, c := range metadata.Conflicts {
+		conflicts = append(conflicts, c.String())
+	}
+
+	return fmt.Errorf("token metadata providers disagree, please, check it manually: %s",
+		strings.Join(conflicts, "; "))
+}
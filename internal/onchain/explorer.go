@@ -0,0 +1,79 @@
+package onchain
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/trustwallet/assets-go-libs/validation/info/external"
+	"github.com/trustwallet/go-primitives/types"
+)
+
+// nolint:gochecknoglobals // lookup table
+var explorerTypes = map[string]bool{
+	string(types.ERC20):     true,
+	string(types.BEP20):     true,
+	string(types.FANTOM):    true,
+	string(types.POLYGON):   true,
+	string(types.AVALANCHE): true,
+	string(types.SPL):       true,
+}
+
+// Explorer is a provider of token info of explorer APIs and pages, the only one knowing holders.
+type Explorer struct {
+	name  string
+	fetch func(tokenID, tokenType string) (*external.TokenInfo, error)
+}
+
+func NewExplorer(name string) *Explorer {
+	return &Explorer{
+		name:  name,
+		fetch: external.GetTokenInfo,
+	}
+}
+
+func (p *Explorer) Name() string {
+	return p.name
+}
+
+func (p *Explorer) Supports(tokenType string) bool {
+	return explorerTypes[strings.ToUpper(tokenType)]
+}
+
+// Metadata returns token info of an explorer. Explorers are requested without a context,
+// so a request is abandoned rather than canceled when the context is done.
+func (p *Explorer) Metadata(ctx context.Context, tokenID, tokenType string) (*Metadata, error) {
+	type fetched struct {
+		info *external.TokenInfo
+		err  error
+	}
+
+	done := make(chan fetched, 1)
+
+	go func() {
+		info, err := p.fetch(tokenID, tokenType)
+		done <- fetched{info: info, err: err}
+	}()
+
+	var result fetched
+
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case result = <-done:
+	}
+
+	if result.err != nil {
+		return nil, fmt.Errorf("failed to get token info: %w", result.err)
+	}
+
+	if result.info == nil {
+		return nil, ErrUnsupported
+	}
+
+	return &Metadata{
+		Symbol:   result.info.Symbol,
+		Decimals: &result.info.Decimals,
+		Holders:  &result.info.HoldersCount,
+	}, nil
+}
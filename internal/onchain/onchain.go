@@ -0,0 +1,181 @@
+// Package onchain resolves metadata of tokens (decimals, symbol, supply and holders) from providers:
+// RPC nodes of EVM chains and explorer APIs. Providers are asked in a fallback order and their answers
+// are compared, so validations check info.json only against values the providers agree on.
+package onchain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+const (
+	KindRPC      = "rpc"
+	KindExplorer = "explorer"
+
+	FieldSymbol      = "symbol"
+	FieldDecimals    = "decimals"
+	FieldTotalSupply = "total_supply"
+	FieldHolders     = "holders"
+)
+
+var (
+	ErrUnsupported = errors.New("no metadata provider for the token type")
+	ErrUnavailable = errors.New("token metadata is unavailable")
+)
+
+// Metadata of a token, unknown fields are empty.
+type Metadata struct {
+	Symbol      string
+	Decimals    *int
+	TotalSupply *big.Int
+	Holders     *int
+}
+
+// Provider is a source of token metadata.
+type Provider interface {
+	// Name identifies the provider in results and logs.
+	Name() string
+	// Supports reports whether the provider knows tokens of an asset type, e.g. ERC20.
+	Supports(tokenType string) bool
+	// Metadata returns metadata of a token, fields the provider doesn't know are left empty.
+	Metadata(ctx context.Context, tokenID, tokenType string) (*Metadata, error)
+}
+
+// NewProvider returns a provider of a kind, the URL and token types are used by RPC providers only.
+func NewProvider(kind, name, url string, tokenTypes []string) (Provider, error) {
+	switch kind {
+	case KindRPC:
+		if url == "" || len(tokenTypes) == 0 {
+			return nil, fmt.Errorf("rpc provider %s needs a url and token types", name)
+		}
+
+		return NewRPC(name, url, tokenTypes), nil
+	case KindExplorer:
+		return NewExplorer(name), nil
+	default:
+		return nil, fmt.Errorf("unknown kind %q of provider %s", kind, name)
+	}
+}
+
+// Result is metadata of a token the providers agree on.
+type Result struct {
+	// Metadata has fields reported by any of the providers which answered, with no other values reported.
+	Metadata
+	// Providers are names of the providers which answered, in the fallback order.
+	Providers []string
+	// Conflicts are fields the providers disagree on, they are left unknown in the metadata.
+	Conflicts []Conflict
+}
+
+// Conflict is a field providers reported different values of.
+type Conflict struct {
+	Field  string
+	Values []Value
+}
+
+// Value is a value of a field reported by a provider.
+type Value struct {
+	Provider string
+	Value    string
+}
+
+func (c Conflict) String() string {
+	values := make([]string, 0, len(c.Values))
+	for _, v := range c.Values {
+		values = append(values, fmt.Sprintf("%s (%s)", v.Value, v.Provider))
+	}
+
+	return fmt.Sprintf("%s: %s", c.Field, strings.Join(values, ", "))
+}
+
+type answer struct {
+	provider string
+	metadata *Metadata
+}
+
+// nolint:gochecknoglobals // lookup table
+var fields = []struct {
+	name  string
+	value func(m *Metadata) (string, bool)
+	set   func(dst, src *Metadata)
+}{
+	{
+		name:  FieldSymbol,
+		value: func(m *Metadata) (string, bool) { return m.Symbol, m.Symbol != "" },
+		set:   func(dst, src *Metadata) { dst.Symbol = src.Symbol },
+	},
+	{
+		name:  FieldDecimals,
+		value: func(m *Metadata) (string, bool) { return intString(m.Decimals) },
+		set:   func(dst, src *Metadata) { dst.Decimals = src.Decimals },
+	},
+	{
+		name: FieldTotalSupply,
+		value: func(m *Metadata) (string, bool) {
+			if m.TotalSupply == nil {
+				return "", false
+			}
+
+			return m.TotalSupply.String(), true
+		},
+		set: func(dst, src *Metadata) { dst.TotalSupply = src.TotalSupply },
+	},
+	{
+		name:  FieldHolders,
+		value: func(m *Metadata) (string, bool) { return intString(m.Holders) },
+		set:   func(dst, src *Metadata) { dst.Holders = src.Holders },
+	},
+}
+
+func intString(v *int) (string, bool) {
+	if v == nil {
+		return "", false
+	}
+
+	return fmt.Sprint(*v), true
+}
+
+// agree returns metadata of the fields all answers reporting them have the same values of.
+func agree(answers []answer) *Result {
+	result := &Result{Providers: make([]string, 0, len(answers))}
+
+	for _, a := range answers {
+		result.Providers = append(result.Providers, a.provider)
+	}
+
+	for _, field := range fields {
+		var agreed *Metadata
+
+		values := make([]Value, 0, len(answers))
+		conflict := false
+
+		for _, a := range answers {
+			value, ok := field.value(a.metadata)
+			if !ok {
+				continue
+			}
+
+			if len(values) > 0 && values[0].Value != value {
+				conflict = true
+			}
+
+			values = append(values, Value{Provider: a.provider, Value: value})
+
+			if agreed == nil {
+				agreed = a.metadata
+			}
+		}
+
+		switch {
+		case conflict:
+			result.Conflicts = append(result.Conflicts, Conflict{Field: field.name, Values: values})
+		case agreed != nil:
+			field.set(&result.Metadata, agreed)
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,463 @@
+package onchain
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/trustwallet/assets-manager/internal/findings"
+)
+
+const (
+	usdt   = "0xdAC17F958D2ee523a2206206994597C13D831ec7"
+	mkr    = "0x9f8F72aA9304c8B593d555F12eF6589cC3A579A2"
+	bare   = "0x0000000000000000000000000000000000000001"
+	wallet = "0x0000000000000000000000000000000000000002"
+)
+
+func word(n int64) string {
+	b := make([]byte, abiWordSize)
+	big.NewInt(n).FillBytes(b)
+
+	return hex.EncodeToString(b)
+}
+
+func abiString(s string) string {
+	data := make([]byte, (len(s)+abiWordSize-1)/abiWordSize*abiWordSize)
+	copy(data, s)
+
+	return word(abiWordSize) + word(int64(len(s))) + hex.EncodeToString(data)
+}
+
+func bytes32(s string) string {
+	data := make([]byte, abiWordSize)
+	copy(data, s)
+
+	return hex.EncodeToString(data)
+}
+
+// newNode starts a stand-in JSON-RPC node answering eth_call of contracts by their method selectors,
+// methods not listed revert.
+func newNode(t *testing.T, contracts map[string]map[string]string) *httptest.Server {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			ID     int64             `json:"id"`
+			Method string            `json:"method"`
+			Params []json.RawMessage `json:"params"`
+		}
+
+		var call struct {
+			To   string `json:"to"`
+			Data string `json:"data"`
+		}
+
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Method != "eth_call" ||
+			len(req.Params) != 2 || json.Unmarshal(req.Params[0], &call) != nil {
+			w.WriteHeader(http.StatusBadRequest)
+
+			return
+		}
+
+		resp := map[string]interface{}{"jsonrpc": "2.0", "id": req.ID}
+
+		methods, ok := contracts[call.To]
+		result, implemented := methods[call.Data[2:]]
+
+		switch {
+		case !ok:
+			resp["result"] = "0x"
+		case !implemented:
+			resp["error"] = map[string]interface{}{"code": 3, "message": "execution reverted"}
+		default:
+			resp["result"] = "0x" + result
+		}
+
+		if err := json.NewEncoder(w).Encode(resp); err != nil {
+			t.Error(err)
+		}
+	}))
+
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+func intPtr(v int) *int {
+	return &v
+}
+
+func Test_RPC_Metadata(t *testing.T) {
+	node := newNode(t, map[string]map[string]string{
+		usdt: {selectorDecimals: word(6), selectorSymbol: abiString("USDT"), selectorTotalSupply: word(1000)},
+		mkr:  {selectorDecimals: word(18), selectorSymbol: bytes32("MKR")},
+		bare: {selectorSymbol: abiString("BARE")},
+	})
+
+	down := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer down.Close()
+
+	tests := []struct {
+		name    string
+		url     string
+		id      string
+		want    *Metadata
+		wantErr error
+	}{
+		{
+			name: "ERC20",
+			url:  node.URL,
+			id:   usdt,
+			want: &Metadata{Symbol: "USDT", Decimals: intPtr(6), TotalSupply: big.NewInt(1000)},
+		},
+		{
+			name: "Bytes32 symbol, no totalSupply()",
+			url:  node.URL,
+			id:   mkr,
+			want: &Metadata{Symbol: "MKR", Decimals: intPtr(18)},
+		},
+		{name: "No decimals()", url: node.URL, id: bare, wantErr: ErrNotContract},
+		{name: "Not a contract", url: node.URL, id: wallet, wantErr: ErrNotContract},
+		{name: "Node is down", url: down.URL, id: usdt, wantErr: errors.New("")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider := NewRPC("node", tt.url, []string{"erc20"})
+			if !provider.Supports("ERC20") || provider.Supports("BEP20") {
+				t.Fatal("Supports() does not match the token types")
+			}
+
+			got, err := provider.Metadata(context.Background(), tt.id, "ERC20")
+
+			if tt.wantErr != nil {
+				if err == nil || (tt.wantErr.Error() != "" && !errors.Is(err, tt.wantErr)) {
+					t.Fatalf("Metadata() error = %v, want %v", err, tt.wantErr)
+				}
+
+				return
+			}
+
+			if err != nil || !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Metadata() = %+v, %v, want %+v", got, err, tt.want)
+			}
+		})
+	}
+}
+
+func Test_decodeString(t *testing.T) {
+	full, _ := hex.DecodeString(abiString("Tether USD"))
+	short, _ := hex.DecodeString(bytes32("MKR"))
+	broken, _ := hex.DecodeString(word(abiWordSize) + word(100))
+
+	tests := []struct {
+		name string
+		data []byte
+		want string
+	}{
+		{name: "String", data: full, want: "Tether USD"},
+		{name: "Bytes32", data: short, want: "MKR"},
+		{name: "Length out of data", data: broken, want: ""},
+		{name: "Empty", data: nil, want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := decodeString(tt.data); got != tt.want {
+				t.Errorf("decodeString() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// stubProvider answers the same metadata or error for any token.
+type stubProvider struct {
+	name     string
+	metadata *Metadata
+	err      error
+	calls    int
+}
+
+func (p *stubProvider) Name() string { return p.name }
+
+func (p *stubProvider) Supports(tokenType string) bool { return tokenType == "ERC20" }
+
+func (p *stubProvider) Metadata(context.Context, string, string) (*Metadata, error) {
+	p.calls++
+
+	return p.metadata, p.err
+}
+
+func Test_Resolver_Resolve(t *testing.T) {
+	node := newNode(t, map[string]map[string]string{
+		usdt: {selectorDecimals: word(6), selectorSymbol: abiString("USDT"), selectorTotalSupply: word(1000)},
+	})
+	other := newNode(t, map[string]map[string]string{
+		usdt: {selectorDecimals: word(18), selectorSymbol: abiString("USDT")},
+	})
+
+	slow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(300 * time.Millisecond):
+		}
+	}))
+	defer slow.Close()
+
+	explorer := &stubProvider{
+		name:     "explorer",
+		metadata: &Metadata{Symbol: "USDT", Decimals: intPtr(6), Holders: intPtr(5)},
+	}
+	failing := &stubProvider{name: "failing", err: errors.New("explorer is down")}
+	erc20 := []string{"ERC20"}
+
+	tests := []struct {
+		name          string
+		quorum        int
+		providers     []Provider
+		tokenType     string
+		want          *Metadata
+		wantProviders []string
+		wantConflicts []string
+		wantErr       error
+	}{
+		{
+			name:          "Fallback of a failed provider",
+			quorum:        1,
+			providers:     []Provider{failing, NewRPC("slow", slow.URL, erc20), NewRPC("node", node.URL, erc20)},
+			tokenType:     "ERC20",
+			want:          &Metadata{Symbol: "USDT", Decimals: intPtr(6), TotalSupply: big.NewInt(1000)},
+			wantProviders: []string{"node"},
+		},
+		{
+			name:          "Quorum reached",
+			quorum:        2,
+			providers:     []Provider{explorer, NewRPC("node", node.URL, erc20), NewRPC("other", other.URL, erc20)},
+			tokenType:     "ERC20",
+			want:          &Metadata{Symbol: "USDT", Decimals: intPtr(6), TotalSupply: big.NewInt(1000), Holders: intPtr(5)},
+			wantProviders: []string{"explorer", "node"},
+		},
+		{
+			name:          "Conflict",
+			quorum:        3,
+			providers:     []Provider{explorer, NewRPC("node", node.URL, erc20), NewRPC("other", other.URL, erc20)},
+			tokenType:     "ERC20",
+			want:          &Metadata{Symbol: "USDT", TotalSupply: big.NewInt(1000), Holders: intPtr(5)},
+			wantProviders: []string{"explorer", "node", "other"},
+			wantConflicts: []string{"decimals: 6 (explorer), 6 (node), 18 (other)"},
+		},
+		{name: "All failed", quorum: 1, providers: []Provider{failing}, tokenType: "ERC20", wantErr: ErrUnavailable},
+		{name: "Unsupported", quorum: 1, providers: []Provider{explorer}, tokenType: "SPL", wantErr: ErrUnsupported},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resolver := NewResolver(Options{Timeout: 100 * time.Millisecond, Quorum: tt.quorum}, tt.providers...)
+
+			got, err := resolver.Resolve(context.Background(), usdt, tt.tokenType)
+
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("Resolve() error = %v, want %v", err, tt.wantErr)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Resolve() error = %v", err)
+			}
+
+			conflicts := make([]string, 0)
+			for _, c := range got.Conflicts {
+				conflicts = append(conflicts, c.String())
+			}
+
+			if tt.wantConflicts == nil {
+				tt.wantConflicts = []string{}
+			}
+
+			if !reflect.DeepEqual(&got.Metadata, tt.want) || !reflect.DeepEqual(got.Providers, tt.wantProviders) ||
+				!reflect.DeepEqual(conflicts, tt.wantConflicts) {
+				t.Errorf("Resolve() = %+v, %v, %v, want %+v, %v, %v",
+					got.Metadata, got.Providers, conflicts, tt.want, tt.wantProviders, tt.wantConflicts)
+			}
+		})
+	}
+}
+
+func Test_Resolver_Cache(t *testing.T) {
+	provider := &stubProvider{name: "explorer", err: errors.New("explorer is down")}
+	resolver := NewResolver(Options{CacheTTL: time.Minute}, provider)
+
+	if _, err := resolver.Resolve(context.Background(), "a", "ERC20"); err == nil {
+		t.Fatal("Resolve() error = nil, want the provider error")
+	}
+
+	provider.err = nil
+	provider.metadata = &Metadata{Decimals: intPtr(8)}
+
+	for i := 0; i < 3; i++ {
+		result, err := resolver.Resolve(context.Background(), "a", "ERC20")
+		if err != nil || *result.Decimals != 8 {
+			t.Fatalf("Resolve() = %v, %v", result, err)
+		}
+	}
+
+	if provider.calls != 2 {
+		t.Errorf("provider calls = %d, want 2 (failures are not cached)", provider.calls)
+	}
+}
+
+// gatedProvider answers once the gate is closed, counting requests.
+type gatedProvider struct {
+	gate  chan struct{}
+	calls int32
+}
+
+func (p *gatedProvider) Name() string { return "gated" }
+
+func (p *gatedProvider) Supports(string) bool { return true }
+
+func (p *gatedProvider) Metadata(context.Context, string, string) (*Metadata, error) {
+	atomic.AddInt32(&p.calls, 1)
+	<-p.gate
+
+	return &Metadata{Decimals: intPtr(18)}, nil
+}
+
+func Test_Resolver_ConcurrentLookups(t *testing.T) {
+	provider := &gatedProvider{gate: make(chan struct{})}
+	resolver := NewResolver(Options{CacheTTL: time.Minute}, provider)
+
+	const lookups = 10
+
+	var started, wg sync.WaitGroup
+
+	started.Add(lookups)
+
+	for i := 0; i < lookups; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			started.Done()
+
+			result, err := resolver.Resolve(context.Background(), "a", "ERC20")
+			if err != nil || *result.Decimals != 18 {
+				t.Errorf("Resolve() = %v, %v", result, err)
+			}
+		}()
+	}
+
+	// Lookups started while the first one is in progress wait for its result, later ones get it cached.
+	started.Wait()
+	time.Sleep(50 * time.Millisecond)
+	close(provider.gate)
+	wg.Wait()
+
+	if calls := atomic.LoadInt32(&provider.calls); calls != 1 {
+		t.Errorf("provider calls = %d, want 1 for concurrent lookups of a token", calls)
+	}
+}
+
+func Test_NewProvider(t *testing.T) {
+	tests := []struct {
+		name    string
+		kind    string
+		url     string
+		types   []string
+		wantErr bool
+	}{
+		{name: "RPC", kind: KindRPC, url: "http://localhost:8545", types: []string{"ERC20"}},
+		{name: "RPC without a url", kind: KindRPC, types: []string{"ERC20"}, wantErr: true},
+		{name: "Explorer", kind: KindExplorer},
+		{name: "Unknown", kind: "scraper", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewProvider(tt.kind, tt.name, tt.url, tt.types); (err != nil) != tt.wantErr {
+				t.Errorf("NewProvider() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_Check(t *testing.T) {
+	decimals, holders := 6, 10
+
+	metadata := &Result{Metadata: Metadata{
+		Symbol:      "USDT",
+		Decimals:    &decimals,
+		TotalSupply: big.NewInt(0),
+		Holders:     &holders,
+	}}
+
+	conflicted := &Result{Conflicts: []Conflict{{
+		Field:  FieldSymbol,
+		Values: []Value{{Provider: "rpc", Value: "USDT"}, {Provider: "explorer", Value: "USDT2"}},
+	}}}
+
+	wrongDecimals, wrongSymbol := 18, "USD"
+
+	tests := []struct {
+		name     string
+		metadata *Result
+		decimals *int
+		symbol   *string
+		want     []string
+	}{
+		{
+			name: "Unknown metadata",
+			want: []string{findings.RuleAssetHoldersUnchecked},
+		},
+		{
+			name:     "Matching",
+			metadata: metadata,
+			decimals: &decimals,
+			symbol:   &metadata.Symbol,
+			want:     []string{findings.RuleAssetHolders, findings.RuleAssetSupply},
+		},
+		{
+			name:     "Mismatching",
+			metadata: metadata,
+			decimals: &wrongDecimals,
+			symbol:   &wrongSymbol,
+			want: []string{findings.RuleAssetDecimals, findings.RuleAssetSymbol,
+				findings.RuleAssetHolders, findings.RuleAssetSupply},
+		},
+		{
+			name:     "Conflicts",
+			metadata: conflicted,
+			decimals: &decimals,
+			want:     []string{findings.RuleAssetHoldersUnchecked, findings.RuleAssetMetadataConflict},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rules := make([]string, 0)
+			for _, f := range Check(tt.metadata, tt.decimals, tt.symbol, 100) {
+				rules = append(rules, f.Rule)
+			}
+
+			if !reflect.DeepEqual(rules, tt.want) {
+				t.Errorf("Check() rules = %v, want %v", rules, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,195 @@
+package onchain
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/trustwallet/assets-manager/internal/config"
+)
+
+// Options of a resolver.
+type Options struct {
+	// Timeout limits a request to a provider, 0 - no limit but the one of the context.
+	Timeout time.Duration
+	// Quorum is a number of providers answered enough for a result, the rest are fallbacks.
+	Quorum int
+	// CacheTTL is how long results are cached, 0 - no cache. Failed lookups are not cached.
+	CacheTTL time.Duration
+}
+
+// Resolver resolves token metadata asking providers in their order until a quorum of them answered.
+// Providers which fail or time out are skipped for the next ones. Concurrent lookups of a token
+// share one request to the providers.
+type Resolver struct {
+	providers []Provider
+	opts      Options
+
+	mu        sync.Mutex
+	entries   map[string]cacheEntry
+	inflight  map[string]*lookup
+	lastPrune time.Time
+}
+
+type cacheEntry struct {
+	result    *Result
+	expiresAt time.Time
+}
+
+// lookup is a lookup of a token in progress, done is closed once its result is set.
+type lookup struct {
+	done   chan struct{}
+	result *Result
+	err    error
+}
+
+func NewResolver(opts Options, providers ...Provider) *Resolver {
+	if opts.Quorum < 1 {
+		opts.Quorum = 1
+	}
+
+	return &Resolver{
+		providers: providers,
+		opts:      opts,
+		entries:   make(map[string]cacheEntry),
+		inflight:  make(map[string]*lookup),
+	}
+}
+
+// Resolve returns metadata of a token the providers agree on. ErrUnsupported is returned if no provider
+// knows the token type, ErrUnavailable if all of them failed.
+func (r *Resolver) Resolve(ctx context.Context, tokenID, tokenType string) (*Result, error) {
+	key := strings.ToUpper(tokenType) + "/" + tokenID
+
+	r.mu.Lock()
+
+	if entry, ok := r.entries[key]; ok && time.Now().Before(entry.expiresAt) {
+		r.mu.Unlock()
+
+		return entry.result, nil
+	}
+
+	if l, ok := r.inflight[key]; ok {
+		r.mu.Unlock()
+
+		select {
+		case <-l.done:
+			return l.result, l.err
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	l := &lookup{done: make(chan struct{})}
+	r.inflight[key] = l
+	r.mu.Unlock()
+
+	l.result, l.err = r.resolve(ctx, tokenID, tokenType)
+
+	r.mu.Lock()
+	delete(r.inflight, key)
+
+	if l.err == nil && r.opts.CacheTTL > 0 {
+		r.cache(key, l.result)
+	}
+
+	r.mu.Unlock()
+	close(l.done)
+
+	return l.result, l.err
+}
+
+// cache saves a result, the caller holds the lock.
+func (r *Resolver) cache(key string, result *Result) {
+	now := time.Now()
+
+	r.entries[key] = cacheEntry{result: result, expiresAt: now.Add(r.opts.CacheTTL)}
+
+	// Expired entries are removed at most once per TTL.
+	if now.Sub(r.lastPrune) >= r.opts.CacheTTL {
+		for k, e := range r.entries {
+			if !now.Before(e.expiresAt) {
+				delete(r.entries, k)
+			}
+		}
+
+		r.lastPrune = now
+	}
+}
+
+func (r *Resolver) resolve(ctx context.Context, tokenID, tokenType string) (*Result, error) {
+	answers := make([]answer, 0, r.opts.Quorum)
+	failures := make([]string, 0)
+
+	for _, p := range r.providers {
+		if len(answers) >= r.opts.Quorum {
+			break
+		}
+
+		if !p.Supports(tokenType) {
+			continue
+		}
+
+		metadata, err := r.ask(ctx, p, tokenID, tokenType)
+		if err != nil {
+			log.WithError(err).WithFields(log.Fields{
+				"provider": p.Name(),
+				"token":    tokenID,
+				"type":     tokenType,
+			}).Debug("Token metadata provider failed")
+
+			failures = append(failures, fmt.Sprintf("%s: %v", p.Name(), err))
+
+			continue
+		}
+
+		answers = append(answers, answer{provider: p.Name(), metadata: metadata})
+	}
+
+	if len(answers) == 0 {
+		if len(failures) == 0 {
+			return nil, fmt.Errorf("%w: %s", ErrUnsupported, tokenType)
+		}
+
+		return nil, fmt.Errorf("%w: %s", ErrUnavailable, strings.Join(failures, "; "))
+	}
+
+	return agree(answers), nil
+}
+
+func (r *Resolver) ask(ctx context.Context, p Provider, tokenID, tokenType string) (*Metadata, error) {
+	if r.opts.Timeout > 0 {
+		var cancel context.CancelFunc
+
+		ctx, cancel = context.WithTimeout(ctx, r.opts.Timeout)
+		defer cancel()
+	}
+
+	return p.Metadata(ctx, tokenID, tokenType)
+}
+
+// NewConfiguredResolver returns a resolver of the providers of the external validation config.
+func NewConfiguredResolver() (*Resolver, error) {
+	external := config.Default.Validation.External
+
+	providers := make([]Provider, 0, len(external.Providers))
+
+	for _, p := range external.Providers {
+		provider, err := NewProvider(p.Kind, p.Name, p.URL, p.Types)
+		if err != nil {
+			return nil, fmt.Errorf("invalid token metadata provider: %w", err)
+		}
+
+		providers = append(providers, provider)
+	}
+
+	return NewResolver(Options{
+		Timeout:  external.Timeout,
+		Quorum:   external.Quorum,
+		CacheTTL: external.CacheTTL,
+	}, providers...), nil
+}
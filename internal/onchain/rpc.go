@@ -0,0 +1,169 @@
+package onchain
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/trustwallet/go-libs/client"
+)
+
+const (
+	// Selectors of ERC20 methods, the first 4 bytes of keccak256 of their signatures.
+	selectorDecimals    = "313ce567"
+	selectorSymbol      = "95d89b41"
+	selectorTotalSupply = "18160ddd"
+
+	abiWordSize = 32
+)
+
+var ErrNotContract = errors.New("not an ERC20 contract")
+
+// RPC is a provider calling ERC20 methods of token contracts via a JSON-RPC node of an EVM chain.
+// It knows decimals, symbol and total supply, but not holders.
+type RPC struct {
+	name  string
+	types map[string]bool
+	rpc   client.Request
+}
+
+func NewRPC(name, url string, tokenTypes []string) *RPC {
+	types := make(map[string]bool, len(tokenTypes))
+	for _, t := range tokenTypes {
+		types[strings.ToUpper(t)] = true
+	}
+
+	return &RPC{
+		name:  name,
+		types: types,
+		rpc:   client.InitJSONClient(url, nil),
+	}
+}
+
+func (p *RPC) Name() string {
+	return p.name
+}
+
+func (p *RPC) Supports(tokenType string) bool {
+	return p.types[strings.ToUpper(tokenType)]
+}
+
+// Metadata returns metadata of a contract, symbol() and totalSupply() are optional as some tokens
+// don't implement them. Contracts without decimals() are not ERC20 ones.
+func (p *RPC) Metadata(ctx context.Context, tokenID, _ string) (*Metadata, error) {
+	data, err := p.optionalCall(ctx, tokenID, selectorDecimals)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call decimals(): %w", err)
+	}
+
+	decimals, ok := decodeUint(data)
+	if !ok || !decimals.IsUint64() || decimals.Uint64() > 255 {
+		return nil, fmt.Errorf("%w: %s has no decimals()", ErrNotContract, tokenID)
+	}
+
+	dec := int(decimals.Uint64())
+	metadata := &Metadata{Decimals: &dec}
+
+	if data, err = p.optionalCall(ctx, tokenID, selectorSymbol); err != nil {
+		return nil, fmt.Errorf("failed to call symbol(): %w", err)
+	}
+
+	metadata.Symbol = decodeString(data)
+
+	if data, err = p.optionalCall(ctx, tokenID, selectorTotalSupply); err != nil {
+		return nil, fmt.Errorf("failed to call totalSupply(): %w", err)
+	}
+
+	if supply, ok := decodeUint(data); ok {
+		metadata.TotalSupply = supply
+	}
+
+	return metadata, nil
+}
+
+// optionalCall returns no data for a method the contract reverts on.
+func (p *RPC) optionalCall(ctx context.Context, contract, selector string) ([]byte, error) {
+	data, err := p.call(ctx, contract, selector)
+
+	var rpcErr *client.RpcError
+	if errors.As(err, &rpcErr) {
+		return nil, nil
+	}
+
+	return data, err
+}
+
+// call returns the result of eth_call of a contract method without arguments at the latest block.
+func (p *RPC) call(ctx context.Context, contract, selector string) ([]byte, error) {
+	req := &client.RpcRequest{
+		JsonRpc: client.JsonRpcVersion,
+		Method:  "eth_call",
+		Params:  []interface{}{map[string]string{"to": contract, "data": "0x" + selector}, "latest"},
+		Id:      1,
+	}
+
+	var resp client.RpcResponseRaw
+	if err := p.rpc.PostWithContext(&resp, "", req, ctx); err != nil {
+		return nil, err
+	}
+
+	if resp.Error != nil {
+		return nil, resp.Error
+	}
+
+	var result string
+	if err := json.Unmarshal(resp.Result, &result); err != nil {
+		return nil, fmt.Errorf("invalid eth_call result: %w", err)
+	}
+
+	data, err := hex.DecodeString(strings.TrimPrefix(result, "0x"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid eth_call result: %w", err)
+	}
+
+	return data, nil
+}
+
+// decodeUint decodes an ABI uint256, false if there is no value, e.g. a call of an account without code.
+func decodeUint(data []byte) (*big.Int, bool) {
+	if len(data) < abiWordSize {
+		return nil, false
+	}
+
+	return new(big.Int).SetBytes(data[:abiWordSize]), true
+}
+
+// decodeString decodes an ABI string or a bytes32 one used by early tokens, e.g. MKR.
+// Undecodable values are returned empty.
+func decodeString(data []byte) string {
+	var s string
+
+	if len(data) == abiWordSize {
+		s = strings.TrimRight(string(data), "\x00")
+	} else {
+		offset, ok := decodeUint(data)
+		if !ok || !offset.IsUint64() || offset.Uint64() > uint64(len(data)) {
+			return ""
+		}
+
+		length, ok := decodeUint(data[offset.Uint64():])
+		start := offset.Uint64() + abiWordSize
+
+		if !ok || !length.IsUint64() || length.Uint64() > uint64(len(data))-start {
+			return ""
+		}
+
+		s = string(data[start : start+length.Uint64()])
+	}
+
+	if !utf8.ValidString(s) {
+		return ""
+	}
+
+	return s
+}
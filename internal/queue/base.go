@@ -7,7 +7,8 @@ import (
 )
 
 const (
-	QueueAssetManagerProcessGithubEvent mq.QueueName = "asset_manager_github_events_process"
+	QueueAssetManagerProcessGithubEvent    mq.QueueName = "asset_manager_github_events_process"
+	QueueAssetManagerGithubEventDeadLetter mq.QueueName = "asset_manager_github_events_dead_letter"
 )
 
 func SetupQueues(rabbitmqURL string) error {
@@ -18,6 +19,7 @@ func SetupQueues(rabbitmqURL string) error {
 
 	queues := []mq.Queue{
 		mqClient.InitQueue(QueueAssetManagerProcessGithubEvent),
+		mqClient.InitQueue(QueueAssetManagerGithubEventDeadLetter),
 	}
 
 	for _, queue := range queues {
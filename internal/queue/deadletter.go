@@ -0,0 +1,209 @@
+package queue
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/streadway/amqp"
+)
+
+// NewDeadLetter returns a dead letter of a message failed with an error.
+func NewDeadLetter(message []byte, attempts int, reason error) (*DeadLetter, error) {
+	id, err := newID()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate dead letter id: %w", err)
+	}
+
+	letter := &DeadLetter{
+		ID:       id,
+		Error:    reason.Error(),
+		Attempts: attempts,
+		FailedAt: time.Now().UTC(),
+	}
+
+	if json.Valid(message) {
+		letter.Message = message
+	} else {
+		letter.Raw = message
+	}
+
+	return letter, nil
+}
+
+// newID returns a random ID of a dead letter or a retry.
+func newID() (string, error) {
+	id := make([]byte, 8)
+	if _, err := rand.Read(id); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(id), nil
+}
+
+// Event returns a Github event message of a dead letter.
+func (d *DeadLetter) Event() (*GithubEventMessage, error) {
+	var event GithubEventMessage
+	if err := json.Unmarshal(d.Message, &event); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal Github event: %w", err)
+	}
+
+	return &event, nil
+}
+
+// body returns the message of a dead letter as it was consumed. Messages which were not a valid JSON
+// used to be kept as a JSON string in Message, they are unquoted.
+func (d *DeadLetter) body() ([]byte, error) {
+	if d.Raw != nil {
+		return d.Raw, nil
+	}
+
+	if len(d.Message) > 0 && d.Message[0] == '"' {
+		var quoted string
+		if err := json.Unmarshal(d.Message, &quoted); err != nil {
+			return nil, err
+		}
+
+		return []byte(quoted), nil
+	}
+
+	return d.Message, nil
+}
+
+// DeadLetterClient reads the dead-letter queue. go-libs mq only consumes queues,
+// so messages are fetched one by one and requeued unless they are replayed.
+type DeadLetterClient struct {
+	conn    *amqp.Connection
+	channel *amqp.Channel
+}
+
+func NewDeadLetterClient(rabbitmqURL string) (*DeadLetterClient, error) {
+	conn, err := amqp.Dial(rabbitmqURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to mq: %w", err)
+	}
+
+	channel, err := conn.Channel()
+	if err != nil {
+		return nil, fmt.Errorf("failed to open mq channel: %w", err)
+	}
+
+	return &DeadLetterClient{conn: conn, channel: channel}, nil
+}
+
+func (c *DeadLetterClient) Close() error {
+	if err := c.channel.Close(); err != nil {
+		return fmt.Errorf("failed to close mq channel: %w", err)
+	}
+
+	return c.conn.Close()
+}
+
+// List returns dead letters without removing them from the queue.
+func (c *DeadLetterClient) List() ([]DeadLetter, error) {
+	deliveries, letters, err := c.fetch()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(deliveries) > 0 {
+		if err = c.channel.Nack(deliveries[len(deliveries)-1].DeliveryTag, true, true); err != nil {
+			return nil, fmt.Errorf("failed to requeue dead letters: %w", err)
+		}
+	}
+
+	return letters, nil
+}
+
+// Replay publishes dead letters with the IDs, or all if no IDs are given, to the process queue
+// with attempts reset, and removes them from the dead-letter queue. Returns the replayed dead letters.
+func (c *DeadLetterClient) Replay(ids []string) ([]DeadLetter, error) {
+	deliveries, letters, err := c.fetch()
+	if err != nil {
+		return nil, err
+	}
+
+	selected := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		selected[id] = true
+	}
+
+	replayed := make([]DeadLetter, 0)
+
+	for i := range deliveries {
+		if len(ids) > 0 && !selected[letters[i].ID] {
+			if err = c.channel.Nack(deliveries[i].DeliveryTag, false, true); err != nil {
+				return replayed, fmt.Errorf("failed to requeue dead letter: %w", err)
+			}
+
+			continue
+		}
+
+		if err = c.replay(&letters[i]); err != nil {
+			return replayed, err
+		}
+
+		if err = c.channel.Ack(deliveries[i].DeliveryTag, false); err != nil {
+			return replayed, fmt.Errorf("failed to remove dead letter: %w", err)
+		}
+
+		replayed = append(replayed, letters[i])
+	}
+
+	return replayed, nil
+}
+
+func (c *DeadLetterClient) replay(letter *DeadLetter) error {
+	message, err := letter.body()
+	if err != nil {
+		return fmt.Errorf("failed to read dead letter %s: %w", letter.ID, err)
+	}
+
+	// Only Github events have attempts to reset, other messages are published unchanged.
+	if event, err := letter.Event(); err == nil {
+		event.Attempts = 0
+
+		if message, err = json.Marshal(event); err != nil {
+			return fmt.Errorf("failed to marshal event: %w", err)
+		}
+	}
+
+	err = c.channel.Publish("", string(QueueAssetManagerProcessGithubEvent), false, false, amqp.Publishing{
+		DeliveryMode: amqp.Persistent,
+		ContentType:  "text/plain",
+		Body:         message,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to publish dead letter %s: %w", letter.ID, err)
+	}
+
+	return nil
+}
+
+// fetch gets all messages of the dead-letter queue unacknowledged, so they stay in the queue
+// until acked or requeued.
+func (c *DeadLetterClient) fetch() ([]amqp.Delivery, []DeadLetter, error) {
+	deliveries := make([]amqp.Delivery, 0)
+	letters := make([]DeadLetter, 0)
+
+	for {
+		delivery, ok, err := c.channel.Get(string(QueueAssetManagerGithubEventDeadLetter), false)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to get dead letter: %w", err)
+		}
+
+		if !ok {
+			return deliveries, letters, nil
+		}
+
+		var letter DeadLetter
+		if err = json.Unmarshal(delivery.Body, &letter); err != nil {
+			return nil, nil, fmt.Errorf("failed to unmarshal dead letter: %w", err)
+		}
+
+		deliveries = append(deliveries, delivery)
+		letters = append(letters, letter)
+	}
+}
@@ -0,0 +1,91 @@
+package queue
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestNewDeadLetter(t *testing.T) {
+	tests := []struct {
+		name      string
+		message   string
+		wantType  string
+		wantEvent bool
+	}{
+		{name: "Github event", message: `{"type":"pull_request_opened","attempts":4}`, wantType: PullRequestOpened, wantEvent: true},
+		{name: "Invalid JSON", message: `{"type":`, wantEvent: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			letter, err := NewDeadLetter([]byte(tt.message), 5, errors.New("failed"))
+			if err != nil {
+				t.Fatalf("NewDeadLetter() error = %v", err)
+			}
+
+			if len(letter.ID) != 16 || letter.Attempts != 5 || letter.Error != "failed" {
+				t.Errorf("NewDeadLetter() = %+v", letter)
+			}
+
+			event, err := letter.Event()
+			if (err == nil) != tt.wantEvent {
+				t.Fatalf("Event() error = %v, want event %v", err, tt.wantEvent)
+			}
+
+			if tt.wantEvent && event.Type != tt.wantType {
+				t.Errorf("Event() type = %s, want %s", event.Type, tt.wantType)
+			}
+		})
+	}
+}
+
+func TestDeadLetter_body(t *testing.T) {
+	tests := []struct {
+		name    string
+		message []byte
+	}{
+		{name: "Github event", message: []byte(`{"type":"pull_request_opened","attempts":4}`)},
+		{name: "Invalid JSON", message: []byte(`{"type":`)},
+		{name: "Binary", message: []byte{0xff, 0x00, 0xfe}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			letter, err := NewDeadLetter(tt.message, 5, errors.New("failed"))
+			if err != nil {
+				t.Fatalf("NewDeadLetter() error = %v", err)
+			}
+
+			// Dead letters are read back from the queue.
+			data, err := json.Marshal(letter)
+			if err != nil {
+				t.Fatalf("json.Marshal() error = %v", err)
+			}
+
+			var stored DeadLetter
+			if err = json.Unmarshal(data, &stored); err != nil {
+				t.Fatalf("json.Unmarshal() error = %v", err)
+			}
+
+			body, err := stored.body()
+			if err != nil {
+				t.Fatalf("body() error = %v", err)
+			}
+
+			if !reflect.DeepEqual(body, tt.message) {
+				t.Errorf("body() = %q, want %q", body, tt.message)
+			}
+		})
+	}
+
+	t.Run("Quoted message", func(t *testing.T) {
+		letter := DeadLetter{Message: json.RawMessage(`"{\"type\":"`)}
+
+		body, err := letter.body()
+		if err != nil || string(body) != `{"type":` {
+			t.Errorf("body() = %q, %v, want %q", body, err, `{"type":`)
+		}
+	})
+}
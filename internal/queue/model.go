@@ -1,14 +1,29 @@
 package queue
 
-import ghlib "github.com/google/go-github/v38/github"
+import (
+	"encoding/json"
+	"time"
+
+	ghlib "github.com/google/go-github/v38/github"
+)
 
 type EventType string
 
 const (
-	PullRequestOpened              = "pull_request_opened"
-	PullRequestSynchronize         = "pull_request_synchronize"
+	PullRequestOpened               = "pull_request_opened"
+	PullRequestReopened             = "pull_request_reopened"
+	PullRequestSynchronize          = "pull_request_synchronize"
+	PullRequestClosed               = "pull_request_closed"
+	PullRequestLabeled              = "pull_request_labeled"
+	PullRequestUnlabeled            = "pull_request_unlabeled"
+	PullRequestReviewSubmitted      = "pull_request_review_submitted"
+	PullRequestReviewCommentCreated = "pull_request_review_comment_created"
+	IssueCommentCreated             = "issue_comment_created"
+	CheckRunRerequested             = "check_run_rerequested"
+
+	// PullRequestReviewCommentOpened is a type of review comment messages published before it was
+	// renamed to PullRequestReviewCommentCreated, kept to handle replayed messages.
 	PullRequestReviewCommentOpened = "pull_request_review_comment_opened"
-	IssueCommentCreated            = "issue_comment_created"
 )
 
 type (
@@ -17,5 +32,38 @@ type (
 		PullRequest              *ghlib.PullRequestEvent              `json:"pull_request"`
 		IssueComment             *ghlib.IssueCommentEvent             `json:"issue_comment"`
 		PullRequestReviewComment *ghlib.PullRequestReviewCommentEvent `json:"pull_request_review_comment"`
+		PullRequestReview        *ghlib.PullRequestReviewEvent        `json:"pull_request_review"`
+		CheckRun                 *ghlib.CheckRunEvent                 `json:"check_run"`
+		// Attempts is the number of failed attempts to handle the event.
+		Attempts int `json:"attempts,omitempty"`
+	}
+
+	// DeadLetter is a Github event message which has failed all attempts.
+	// A message which is not a valid JSON is kept in Raw, base64 encoded, instead of Message.
+	DeadLetter struct {
+		ID       string          `json:"id"`
+		Message  json.RawMessage `json:"message,omitempty"`
+		Raw      []byte          `json:"raw,omitempty"`
+		Error    string          `json:"error"`
+		Attempts int             `json:"attempts"`
+		FailedAt time.Time       `json:"failed_at"`
 	}
 )
+
+// Source returns a repository and an app installation the event has been sent from.
+func (m *GithubEventMessage) Source() (*ghlib.Repository, *ghlib.Installation) {
+	switch {
+	case m.PullRequest != nil:
+		return m.PullRequest.GetRepo(), m.PullRequest.GetInstallation()
+	case m.IssueComment != nil:
+		return m.IssueComment.GetRepo(), m.IssueComment.GetInstallation()
+	case m.PullRequestReview != nil:
+		return m.PullRequestReview.GetRepo(), m.PullRequestReview.GetInstallation()
+	case m.PullRequestReviewComment != nil:
+		return m.PullRequestReviewComment.GetRepo(), m.PullRequestReviewComment.GetInstallation()
+	case m.CheckRun != nil:
+		return m.CheckRun.GetRepo(), m.CheckRun.GetInstallation()
+	default:
+		return nil, nil
+	}
+}
@@ -0,0 +1,91 @@
+package queue
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/trustwallet/assets-manager/internal/storage"
+)
+
+const bucketRetries = "github_event_retries"
+
+// Retry is a failed Github event message scheduled for another attempt.
+type Retry struct {
+	ID            string          `json:"id"`
+	Message       json.RawMessage `json:"message"`
+	NextAttemptAt time.Time       `json:"next_attempt_at"`
+	Error         string          `json:"error"`
+}
+
+// RetryQueue keeps failed Github event messages until their next attempt, so that a failed event
+// is retried later without holding up the consumer. Due messages are published back by a poll.
+type RetryQueue struct {
+	store storage.Store
+	now   func() time.Time
+}
+
+func NewRetryQueue(store storage.Store) *RetryQueue {
+	return &RetryQueue{store: store, now: time.Now}
+}
+
+// Schedule saves a message for an attempt after the delay. Messages are keyed by the time
+// of the next attempt, so they are polled in that order.
+func (q *RetryQueue) Schedule(message []byte, delay time.Duration, reason error) (*Retry, error) {
+	id, err := newID()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate retry id: %w", err)
+	}
+
+	retry := &Retry{
+		ID:            id,
+		Message:       message,
+		NextAttemptAt: q.now().Add(delay),
+		Error:         reason.Error(),
+	}
+
+	if err = q.store.Put(bucketRetries, retry.key(), retry); err != nil {
+		return nil, fmt.Errorf("failed to save retry: %w", err)
+	}
+
+	return retry, nil
+}
+
+// PublishDue publishes messages due for an attempt and removes them from the queue.
+// Returns the number of published messages.
+func (q *RetryQueue) PublishDue(publish func(message []byte) error) (int, error) {
+	now := q.now()
+	due := make([]Retry, 0)
+
+	err := q.store.ForEach(bucketRetries, func(_ string, value []byte) error {
+		var retry Retry
+		if err := json.Unmarshal(value, &retry); err != nil {
+			return fmt.Errorf("failed to decode retry: %w", err)
+		}
+
+		if !retry.NextAttemptAt.After(now) {
+			due = append(due, retry)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	for i := range due {
+		if err = publish(due[i].Message); err != nil {
+			return i, fmt.Errorf("failed to publish retry %s: %w", due[i].ID, err)
+		}
+
+		if err = q.store.Delete(bucketRetries, due[i].key()); err != nil {
+			return i + 1, fmt.Errorf("failed to remove retry %s: %w", due[i].ID, err)
+		}
+	}
+
+	return len(due), nil
+}
+
+func (r *Retry) key() string {
+	return fmt.Sprintf("%020d/%s", r.NextAttemptAt.UnixNano(), r.ID)
+}
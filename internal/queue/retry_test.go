@@ -0,0 +1,63 @@
+package queue
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/trustwallet/assets-manager/internal/storage"
+)
+
+func TestRetryQueue(t *testing.T) {
+	now := time.Now()
+	q := NewRetryQueue(storage.NewMemoryStore())
+	q.now = func() time.Time { return now }
+
+	for _, message := range []string{`{"attempts":1}`, `{"attempts":2}`} {
+		if _, err := q.Schedule([]byte(message), time.Minute, errors.New("failed")); err != nil {
+			t.Fatalf("Schedule() error = %v", err)
+		}
+	}
+
+	published := make([]string, 0)
+	publish := func(message []byte) error {
+		published = append(published, string(message))
+
+		return nil
+	}
+
+	if n, err := q.PublishDue(publish); err != nil || n != 0 {
+		t.Fatalf("PublishDue() before the delay = %d, %v, want 0, nil", n, err)
+	}
+
+	now = now.Add(time.Minute)
+
+	if n, err := q.PublishDue(publish); err != nil || n != 2 {
+		t.Fatalf("PublishDue() after the delay = %d, %v, want 2, nil", n, err)
+	}
+
+	if n, err := q.PublishDue(publish); err != nil || n != 0 {
+		t.Fatalf("PublishDue() of published retries = %d, %v, want 0, nil", n, err)
+	}
+
+	if len(published) != 2 {
+		t.Errorf("published = %v, want 2 messages", published)
+	}
+}
+
+func TestRetryQueue_PublishFailed(t *testing.T) {
+	q := NewRetryQueue(storage.NewMemoryStore())
+
+	if _, err := q.Schedule([]byte(`{}`), 0, errors.New("failed")); err != nil {
+		t.Fatalf("Schedule() error = %v", err)
+	}
+
+	if _, err := q.PublishDue(func([]byte) error { return errors.New("mq is down") }); err == nil {
+		t.Fatal("PublishDue() with a failing publish error = nil")
+	}
+
+	// A message not published stays in the queue.
+	if n, err := q.PublishDue(func([]byte) error { return nil }); err != nil || n != 1 {
+		t.Errorf("PublishDue() = %d, %v, want 1, nil", n, err)
+	}
+}
@@ -17,8 +17,24 @@ const (
 	eventActionOpened      = "opened"
 	eventActionReopened    = "reopened"
 	eventActionSynchronize = "synchronize"
+	eventActionClosed      = "closed"
+	eventActionLabeled     = "labeled"
+	eventActionUnlabeled   = "unlabeled"
+	eventActionSubmitted   = "submitted"
+	eventActionRerequested = "rerequested"
+	eventActionRequested   = "requested_action"
 )
 
+// pullRequestEventTypes maps pull request event actions to queue event types.
+var pullRequestEventTypes = map[string]string{ // nolint:gochecknoglobals // lookup table
+	eventActionOpened:      queue.PullRequestOpened,
+	eventActionReopened:    queue.PullRequestReopened,
+	eventActionSynchronize: queue.PullRequestSynchronize,
+	eventActionClosed:      queue.PullRequestClosed,
+	eventActionLabeled:     queue.PullRequestLabeled,
+	eventActionUnlabeled:   queue.PullRequestUnlabeled,
+}
+
 type Controller struct {
 	client client.Request
 	queue  mq.Queue
@@ -49,35 +65,44 @@ func (i *Controller) GetGithubAccessToken(code string) (string, error) {
 func (i *Controller) PushGithubEventToQueue(eventPayload interface{}) error {
 	switch event := eventPayload.(type) {
 	case *ghlib.PullRequestEvent:
-		if *event.Action == eventActionOpened || *event.Action == eventActionReopened {
+		if eventType, ok := pullRequestEventTypes[event.GetAction()]; ok {
 			return publishGithubEvent(queue.GithubEventMessage{
-				Type:        queue.PullRequestOpened,
+				Type:        eventType,
 				PullRequest: event,
 			}, i.queue)
 		}
 
-		if *event.Action == eventActionSynchronize {
+	case *ghlib.IssueCommentEvent:
+		if event.GetAction() == eventActionCreated {
 			return publishGithubEvent(queue.GithubEventMessage{
-				Type:        queue.PullRequestSynchronize,
-				PullRequest: event,
+				Type:         queue.IssueCommentCreated,
+				IssueComment: event,
 			}, i.queue)
 		}
 
-	case *ghlib.IssueCommentEvent:
-		if *event.Action == eventActionCreated {
+	case *ghlib.PullRequestReviewEvent:
+		if event.GetAction() == eventActionSubmitted {
 			return publishGithubEvent(queue.GithubEventMessage{
-				Type:         queue.IssueCommentCreated,
-				IssueComment: event,
+				Type:              queue.PullRequestReviewSubmitted,
+				PullRequestReview: event,
 			}, i.queue)
 		}
 
 	case *ghlib.PullRequestReviewCommentEvent:
-		if *event.Action == eventActionOpened {
+		if event.GetAction() == eventActionCreated {
 			return publishGithubEvent(queue.GithubEventMessage{
-				Type:                     queue.PullRequestReviewCommentOpened,
+				Type:                     queue.PullRequestReviewCommentCreated,
 				PullRequestReviewComment: event,
 			}, i.queue)
 		}
+
+	case *ghlib.CheckRunEvent:
+		if event.GetAction() == eventActionRerequested || event.GetAction() == eventActionRequested {
+			return publishGithubEvent(queue.GithubEventMessage{
+				Type:     queue.CheckRunRerequested,
+				CheckRun: event,
+			}, i.queue)
+		}
 	}
 
 	return nil
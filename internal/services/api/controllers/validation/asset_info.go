@@ -1,61 +1,114 @@
 package validation
 
 import (
+	"context"
 	"fmt"
 	"strings"
 
 	log "github.com/sirupsen/logrus"
 
-	"github.com/trustwallet/assets-go-libs/validation"
 	"github.com/trustwallet/assets-go-libs/validation/info"
-	"github.com/trustwallet/assets-go-libs/validation/info/external"
+	"github.com/trustwallet/assets-manager/internal/assetid"
 	"github.com/trustwallet/assets-manager/internal/config"
-	"github.com/trustwallet/go-primitives/address"
+	"github.com/trustwallet/assets-manager/internal/findings"
+	"github.com/trustwallet/assets-manager/internal/onchain"
 	"github.com/trustwallet/go-primitives/coin"
 	"github.com/trustwallet/go-primitives/types"
 )
 
-func (i *Controller) ValidateAssetInfo(asset AssetInfoRequest) *AssetInfoResponse {
-	errors := make([]Error, 0)
-
+func (i *Controller) ValidateAssetInfo(ctx context.Context, asset AssetInfoRequest) *AssetInfoResponse {
 	assetModel := mapAssetModel(asset)
 
-	externalTokenInfo, err := external.GetTokenInfo(asset.ID, asset.Type)
+	metadata, err := i.metadata.Resolve(ctx, asset.ID, asset.Type)
 	if err != nil {
-		log.WithError(err).Debugf("Failed to get token info")
-	}
-
-	checks := []func() error{
-		func() error { return validateAssetInfoID(asset.ID, asset.Type) },
-		func() error { return validateAssetInfoType(asset.Type) },
-		func() error { return validateAssetInfoDecimals(asset.Decimals, externalTokenInfo) },
-		func() error { return info.ValidateDescription(asset.Description) },
-		func() error { return info.ValidateDescriptionWebsite(asset.Description, asset.Website) },
-		func() error { return validateAssetInfoExplorer(asset.Explorer, asset.ID, asset.Type) },
-		func() error { return info.ValidateStatus(asset.Status) },
-		func() error { return validateAssetInfoLinks(asset.Links) },
-		func() error { return validateAssetInfoTags(asset.Tags) },
-		func() error { return validateAssetInfoHolders(externalTokenInfo) },
-		func() error { return info.ValidateAssetRequiredKeys(assetModel) },
-	}
-
-	for _, check := range checks {
-		if err := check(); err != nil {
-			errors = append(errors, Error{Message: err.Error()})
+		log.WithError(err).Debugf("Failed to get token metadata")
+	}
+
+	const sevError = findings.SeverityError
+
+	checks := []struct {
+		rule     string
+		field    string
+		severity findings.Severity
+		check    func() error
+	}{
+		{findings.RuleAssetID, "id", sevError, func() error { return assetid.Validate(asset.ID, asset.Type) }},
+		{findings.RuleAssetType, "type", sevError, func() error { return validateAssetInfoType(asset.Type) }},
+		{findings.RuleAssetDecimals, "decimals", sevError, func() error {
+			return validateAssetInfoDecimals(asset.Decimals)
+		}},
+		{findings.RuleAssetDescription, "description", sevError, func() error {
+			return info.ValidateDescription(asset.Description)
+		}},
+		{findings.RuleAssetWebsite, "website", sevError, func() error {
+			return info.ValidateDescriptionWebsite(asset.Description, asset.Website)
+		}},
+		{findings.RuleAssetExplorer, "explorer", sevError, func() error {
+			return validateAssetInfoExplorer(asset.Explorer, asset.ID, asset.Type)
+		}},
+		{findings.RuleAssetStatus, "status", sevError, func() error { return info.ValidateStatus(asset.Status) }},
+		{findings.RuleAssetLinks, "links", sevError, func() error { return validateAssetInfoLinks(asset.Links) }},
+		{findings.RuleAssetTags, "tags", sevError, func() error { return validateAssetInfoTags(asset.Tags) }},
+		{findings.RuleAssetRequiredKeys, "", sevError, func() error { return info.ValidateAssetRequiredKeys(assetModel) }},
+	}
+
+	found := make([]findings.Finding, 0)
+
+	for _, c := range checks {
+		if err := c.check(); err != nil {
+			found = append(found, findings.New(c.rule, c.severity, c.field, err))
 		}
 	}
 
-	status := StatusTypeOk
-	if len(errors) > 0 {
-		status = StatusTypeError
+	found = append(found, onchain.Check(metadata, &asset.Decimals, &asset.Symbol,
+		config.Default.Validation.Asset.HoldersMinRequired)...)
+
+	return newAssetInfoResponse(findings.Override(found, findings.Overrides(chainHandle(asset.Type))))
+}
+
+// chainHandle returns a handle of the chain of an asset type, "" if unknown.
+func chainHandle(tokenType string) string {
+	chain, err := types.GetChainFromAssetType(tokenType)
+	if err != nil {
+		return ""
 	}
 
+	return chain.Handle
+}
+
+func newAssetInfoResponse(found []findings.Finding) *AssetInfoResponse {
 	return &AssetInfoResponse{
-		Status: status,
-		Errors: errors,
+		Status:   statusOf(found),
+		Errors:   errorsOf(found),
+		Findings: found,
+	}
+}
+
+// statusOf returns a status of a validation by the most severe of its findings.
+func statusOf(found []findings.Finding) StatusType {
+	switch {
+	case findings.HasErrors(found):
+		return StatusTypeError
+	case findings.HasWarnings(found):
+		return StatusTypeWarning
+	default:
+		return StatusTypeOk
 	}
 }
 
+// errorsOf returns messages of the error findings.
+func errorsOf(found []findings.Finding) []Error {
+	errors := make([]Error, 0)
+
+	for _, f := range found {
+		if f.Severity == findings.SeverityError {
+			errors = append(errors, Error{Message: f.Message})
+		}
+	}
+
+	return errors
+}
+
 func mapAssetModel(asset AssetInfoRequest) info.AssetModel {
 	links := make([]info.Link, len(asset.Links))
 	for i := range asset.Links {
@@ -80,32 +133,6 @@ func mapAssetModel(asset AssetInfoRequest) info.AssetModel {
 	}
 }
 
-func validateAssetInfoID(tokenID, tokenType string) error {
-	if tokenID == "" {
-		return fmt.Errorf("token id cannot be empty")
-	}
-
-	if strings.ToUpper(tokenType) == string(types.ERC20) || strings.ToUpper(tokenType) == string(types.BEP20) {
-		if !validation.IsEthereumAddress(tokenID) {
-			return fmt.Errorf("ID is not a valid Ethereum address")
-		}
-
-		checksum, err := address.EIP55Checksum(tokenID)
-		if err != nil {
-			return fmt.Errorf("failed to get checksum for %s: %w", tokenID, err)
-		}
-
-		if checksum != tokenID {
-			return fmt.Errorf("id is not in checksum format, should be %s (not %s). "+
-				"Please rename it. You may need to rename to a temp name first, "+
-				"then to the checksum format, because lowercase-uppercase-only renames "+
-				"are often ignored by the Git client or the filesystem", checksum, tokenID)
-		}
-	}
-
-	return nil
-}
-
 func validateAssetInfoType(tokenType string) error {
 	if tokenType == "" {
 		return fmt.Errorf("type field cannot be empty")
@@ -119,22 +146,13 @@ func validateAssetInfoType(tokenType string) error {
 	return nil
 }
 
-func validateAssetInfoDecimals(decimals int, externalTokenInfo *external.TokenInfo) error {
+func validateAssetInfoDecimals(decimals int) error {
 	decimalsMaxValue := config.Default.Validation.Asset.DecimalsMaxValue
 
 	if decimals > decimalsMaxValue {
 		return fmt.Errorf("decimals value is invalid: %d (max %d)", decimals, decimalsMaxValue)
 	}
 
-	if externalTokenInfo == nil {
-		return nil
-	}
-
-	if decimals != externalTokenInfo.Decimals {
-		return fmt.Errorf("decimals value is incorrect: expected %d instead of %d",
-			externalTokenInfo.Decimals, decimals)
-	}
-
 	return nil
 }
 
@@ -154,7 +172,8 @@ func validateAssetInfoExplorer(explorer, tokenID, tokenType string) error {
 	}
 
 	if !strings.EqualFold(explorer, explorerStandart) {
-		return fmt.Errorf("explorer field incorrect: should be standard %s, not %s", explorerStandart, explorer)
+		return findings.WithFix(fmt.Errorf("explorer field incorrect: should be standard %s, not %s",
+			explorerStandart, explorer), fmt.Sprintf("set explorer to %s", explorerStandart))
 	}
 
 	return nil
@@ -199,24 +218,9 @@ func validateAssetInfoTags(tags []string) error {
 
 	for _, tag := range tags {
 		if _, exists := tagMap[tag]; !exists {
-			return fmt.Errorf("tag '%s' is not allowed", tag)
+			return findings.WithFix(fmt.Errorf("tag '%s' is not allowed", tag), "use tags from /v1/values/tags")
 		}
 	}
 
 	return nil
 }
-
-func validateAssetInfoHolders(extTokenInfo *external.TokenInfo) error {
-	if extTokenInfo == nil {
-		return fmt.Errorf("number of holders not checked: please, check it manually")
-	}
-
-	holdersMinRequired := config.Default.Validation.Asset.HoldersMinRequired
-
-	if extTokenInfo.HoldersCount < holdersMinRequired {
-		return fmt.Errorf("low token circulation: number of holders is %d, below limit of %d",
-			extTokenInfo.HoldersCount, holdersMinRequired)
-	}
-
-	return nil
-}
@@ -0,0 +1,208 @@
+package validation
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+)
+
+var ErrBatchTooLarge = errors.New("too many assets in a batch")
+
+// AssetInfoBatchDecoder reads asset infos of a JSON array or a stream of newline-delimited JSON objects
+// one by one, so that assets are validated while the rest of the batch is being read.
+type AssetInfoBatchDecoder struct {
+	reader    *bufio.Reader
+	decoder   *json.Decoder
+	maxAssets int
+	count     int
+	array     bool
+	done      bool
+}
+
+func NewAssetInfoBatchDecoder(r io.Reader, maxAssets int) *AssetInfoBatchDecoder {
+	return &AssetInfoBatchDecoder{reader: bufio.NewReader(r), maxAssets: maxAssets}
+}
+
+// Next returns the next asset of the batch, io.EOF after the last one.
+func (d *AssetInfoBatchDecoder) Next() (AssetInfoRequest, error) {
+	var asset AssetInfoRequest
+
+	if d.done {
+		return asset, io.EOF
+	}
+
+	if d.decoder == nil {
+		if err := d.start(); err != nil || d.done {
+			return asset, err
+		}
+	}
+
+	if !d.decoder.More() {
+		d.done = true
+
+		if d.array {
+			if _, err := d.decoder.Token(); err != nil {
+				return asset, fmt.Errorf("failed to read batch: %w", err)
+			}
+		}
+
+		return asset, io.EOF
+	}
+
+	if d.count >= d.maxAssets {
+		d.done = true
+
+		return asset, fmt.Errorf("%w: max %d", ErrBatchTooLarge, d.maxAssets)
+	}
+
+	if err := d.decoder.Decode(&asset); err != nil {
+		d.done = true
+
+		return asset, fmt.Errorf("failed to decode asset %d: %w", d.count, err)
+	}
+
+	d.count++
+
+	return asset, nil
+}
+
+// start detects the batch format by its first non-space byte, an empty batch is done right away.
+func (d *AssetInfoBatchDecoder) start() error {
+	first, err := peekNonSpace(d.reader)
+	if err != nil {
+		d.done = true
+
+		return err
+	}
+
+	if first == 0 {
+		d.done = true
+
+		return io.EOF
+	}
+
+	d.decoder = json.NewDecoder(d.reader)
+
+	if first == '[' {
+		d.array = true
+
+		if _, err = d.decoder.Token(); err != nil {
+			d.done = true
+
+			return fmt.Errorf("failed to read batch: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// peekNonSpace returns the first non-space byte of a reader without consuming it, 0 if the reader is empty.
+func peekNonSpace(r *bufio.Reader) (byte, error) {
+	for {
+		b, err := r.Peek(1)
+		if errors.Is(err, io.EOF) {
+			return 0, nil
+		}
+
+		if err != nil {
+			return 0, fmt.Errorf("failed to read batch: %w", err)
+		}
+
+		if len(bytes.TrimSpace(b)) > 0 {
+			return b[0], nil
+		}
+
+		if _, err = r.ReadByte(); err != nil {
+			return 0, fmt.Errorf("failed to read batch: %w", err)
+		}
+	}
+}
+
+// ValidateAssetInfoBatch validates assets of a batch with a pool of workers as they are read. Results are sent
+// in the order of completion. A batch which can't be read further is reported by a result with an error.
+// The channel is closed when all assets are validated or the context is done.
+func (i *Controller) ValidateAssetInfoBatch(
+	ctx context.Context, batch *AssetInfoBatchDecoder, workers int,
+) <-chan AssetInfoBatchResult {
+	if workers < 1 {
+		workers = 1
+	}
+
+	type job struct {
+		index int
+		asset AssetInfoRequest
+	}
+
+	jobs := make(chan job)
+	results := make(chan AssetInfoBatchResult)
+
+	var wg sync.WaitGroup
+
+	send := func(result AssetInfoBatchResult) bool {
+		select {
+		case results <- result:
+			return true
+		case <-ctx.Done():
+			return false
+		}
+	}
+
+	wg.Add(1)
+
+	go func() {
+		defer wg.Done()
+		defer close(jobs)
+
+		for index := 0; ; index++ {
+			asset, err := batch.Next()
+			if errors.Is(err, io.EOF) {
+				return
+			}
+
+			if err != nil {
+				send(AssetInfoBatchResult{Index: index, Error: err.Error()})
+
+				return
+			}
+
+			select {
+			case jobs <- job{index: index, asset: asset}:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for j := range jobs {
+				result := AssetInfoBatchResult{
+					Index:             j.index,
+					ID:                j.asset.ID,
+					Type:              j.asset.Type,
+					AssetInfoResponse: i.ValidateAssetInfo(ctx, j.asset),
+				}
+
+				if !send(result) {
+					return
+				}
+			}
+		}()
+	}
+
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	return results
+}
@@ -0,0 +1,145 @@
+package validation
+
+import (
+	"context"
+	"errors"
+	"io"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/trustwallet/assets-manager/internal/onchain"
+)
+
+func TestAssetInfoBatchDecoder(t *testing.T) {
+	tests := []struct {
+		name    string
+		body    string
+		want    []string
+		wantErr bool
+	}{
+		{name: "Array", body: ` [{"id":"a"}, {"id":"b"}]`, want: []string{"a", "b"}},
+		{name: "NDJSON", body: "{\"id\":\"a\"}\n{\"id\":\"b\"}\n", want: []string{"a", "b"}},
+		{name: "Empty array", body: "[]", want: []string{}},
+		{name: "Empty body", body: "", want: []string{}},
+		{name: "Too many", body: `[{"id":"a"},{"id":"b"},{"id":"c"},{"id":"d"}]`, wantErr: true},
+		{name: "Invalid object", body: `[{"id":1}]`, wantErr: true},
+		{name: "Unterminated array", body: `[{"id":"a"}`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assets, err := decodeAll(NewAssetInfoBatchDecoder(strings.NewReader(tt.body), 3))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Next() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if tt.wantErr {
+				return
+			}
+
+			got := make([]string, 0, len(assets))
+			for _, a := range assets {
+				got = append(got, a.ID)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Next() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func decodeAll(d *AssetInfoBatchDecoder) ([]AssetInfoRequest, error) {
+	assets := make([]AssetInfoRequest, 0)
+
+	for {
+		asset, err := d.Next()
+		if errors.Is(err, io.EOF) {
+			return assets, nil
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		assets = append(assets, asset)
+	}
+}
+
+// countingProvider answers the same metadata for any token, counting requests.
+type countingProvider struct {
+	mu      sync.Mutex
+	fetched int
+}
+
+func (p *countingProvider) Name() string { return "counting" }
+
+func (p *countingProvider) Supports(string) bool { return true }
+
+func (p *countingProvider) Metadata(context.Context, string, string) (*onchain.Metadata, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.fetched++
+
+	decimals, holders := 18, 10000
+
+	return &onchain.Metadata{Decimals: &decimals, Holders: &holders}, nil
+}
+
+func Test_ValidateAssetInfoBatch(t *testing.T) {
+	provider := &countingProvider{}
+	controller := &Controller{metadata: onchain.NewResolver(onchain.Options{CacheTTL: time.Minute}, provider)}
+
+	const total = 20
+
+	body := strings.Repeat(`{"id":"token","type":"ERC20","decimals":18}`+"\n", total)
+	batch := NewAssetInfoBatchDecoder(strings.NewReader(body), total)
+
+	seen := make(map[int]bool)
+	for result := range controller.ValidateAssetInfoBatch(context.Background(), batch, 4) {
+		if seen[result.Index] {
+			t.Errorf("duplicate result of asset %d", result.Index)
+		}
+		seen[result.Index] = true
+
+		if result.AssetInfoResponse == nil || result.ID != "token" {
+			t.Errorf("unexpected result %+v", result)
+		}
+	}
+
+	if len(seen) != total {
+		t.Errorf("results = %d, want %d", len(seen), total)
+	}
+
+	// Concurrent workers may fetch the same token before it's cached, but not for every asset.
+	if provider.fetched < 1 || provider.fetched > 4 {
+		t.Errorf("fetched = %d, want 1-4", provider.fetched)
+	}
+}
+
+func Test_ValidateAssetInfoBatch_InvalidStream(t *testing.T) {
+	controller := &Controller{metadata: onchain.NewResolver(onchain.Options{}, &countingProvider{})}
+
+	body := `{"id":"a","type":"ERC20","decimals":18}` + "\n" + `{"id":` + "\n"
+	batch := NewAssetInfoBatchDecoder(strings.NewReader(body), 10)
+
+	var valid, failed int
+
+	for result := range controller.ValidateAssetInfoBatch(context.Background(), batch, 2) {
+		switch {
+		case result.Error != "" && result.Index == 1:
+			failed++
+		case result.AssetInfoResponse != nil && result.ID == "a":
+			valid++
+		default:
+			t.Errorf("unexpected result %+v", result)
+		}
+	}
+
+	if valid != 1 || failed != 1 {
+		t.Errorf("valid = %d, failed = %d, want 1 and 1", valid, failed)
+	}
+}
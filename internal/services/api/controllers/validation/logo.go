@@ -0,0 +1,188 @@
+package validation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"syscall"
+	"time"
+
+	"github.com/trustwallet/assets-manager/internal/config"
+	"github.com/trustwallet/assets-manager/internal/findings"
+	"github.com/trustwallet/assets-manager/internal/logo"
+)
+
+const logoMaxRedirects = 3
+
+var (
+	ErrLogoTooLarge          = errors.New("logo file is too large")
+	ErrInvalidLogoURL        = errors.New("invalid logo url")
+	ErrLogoAddressNotAllowed = errors.New("logo address is not allowed")
+)
+
+// nat64Prefix is the well-known NAT64 prefix, the last 4 bytes of an address are an IPv4 address.
+// nolint:gochecknoglobals // constant
+var nat64Prefix = netip.MustParsePrefix("64:ff9b::/96")
+
+// deniedPrefixes are special-purpose ranges of the IANA registries, logos are never fetched from them.
+// nolint:gochecknoglobals // lookup table
+var deniedPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),       // "this" network
+	netip.MustParsePrefix("10.0.0.0/8"),      // private
+	netip.MustParsePrefix("100.64.0.0/10"),   // carrier-grade NAT
+	netip.MustParsePrefix("127.0.0.0/8"),     // loopback
+	netip.MustParsePrefix("169.254.0.0/16"),  // link-local
+	netip.MustParsePrefix("172.16.0.0/12"),   // private
+	netip.MustParsePrefix("192.0.0.0/24"),    // IETF protocol assignments
+	netip.MustParsePrefix("192.0.2.0/24"),    // documentation
+	netip.MustParsePrefix("192.88.99.0/24"),  // 6to4 relay anycast
+	netip.MustParsePrefix("192.168.0.0/16"),  // private
+	netip.MustParsePrefix("198.18.0.0/15"),   // benchmarking
+	netip.MustParsePrefix("198.51.100.0/24"), // documentation
+	netip.MustParsePrefix("203.0.113.0/24"),  // documentation
+	netip.MustParsePrefix("224.0.0.0/4"),     // multicast
+	netip.MustParsePrefix("240.0.0.0/4"),     // reserved and broadcast
+	netip.MustParsePrefix("::/96"),           // unspecified, loopback and IPv4-compatible
+	netip.MustParsePrefix("64:ff9b:1::/48"),  // local-use NAT64
+	netip.MustParsePrefix("100::/64"),        // discard-only
+	netip.MustParsePrefix("2001::/23"),       // IETF protocol assignments, incl. Teredo
+	netip.MustParsePrefix("2001:db8::/32"),   // documentation
+	netip.MustParsePrefix("2002::/16"),       // 6to4
+	netip.MustParsePrefix("fc00::/7"),        // unique local
+	netip.MustParsePrefix("fe80::/10"),       // link-local
+	netip.MustParsePrefix("fec0::/10"),       // site-local
+	netip.MustParsePrefix("ff00::/8"),        // multicast
+}
+
+// ValidateLogo checks a logo with the rules applied to logos of PRs.
+func (i *Controller) ValidateLogo(data []byte) *LogoResponse {
+	info, found := logo.Check(data)
+	found = findings.Override(found, findings.Overrides(""))
+
+	return &LogoResponse{
+		Status:   statusOf(found),
+		Logo:     info,
+		Errors:   errorsOf(found),
+		Findings: found,
+	}
+}
+
+// FixLogo converts a logo to PNG of the accepted dimensions and compresses it under the size limit.
+func (i *Controller) FixLogo(data []byte) (*LogoFixResponse, error) {
+	fixed, report, err := logo.Fix(data)
+	if err != nil {
+		return nil, err
+	}
+
+	report.Findings = findings.Override(report.Findings, findings.Overrides(""))
+
+	return &LogoFixResponse{
+		Status: statusOf(report.Findings),
+		Logo:   fixed,
+		Report: report,
+	}, nil
+}
+
+// FetchLogo downloads a logo by an http(s) URL.
+func (i *Controller) FetchLogo(ctx context.Context, logoURL string) ([]byte, error) {
+	u, err := url.Parse(logoURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidLogoURL, logoURL)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, config.Default.Validation.Logo.FetchTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create logo request: %w", err)
+	}
+
+	resp, err := i.logoClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get logo: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to get logo: unsuccessful status code: %d", resp.StatusCode)
+	}
+
+	return ReadLogo(resp.Body)
+}
+
+// ReadLogo reads a logo up to the max file size.
+func ReadLogo(r io.Reader) ([]byte, error) {
+	maxSize := config.Default.Validation.Logo.MaxFileSize
+
+	data, err := io.ReadAll(io.LimitReader(r, maxSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read logo: %w", err)
+	}
+
+	if int64(len(data)) > maxSize {
+		return nil, fmt.Errorf("%w: max %d bytes", ErrLogoTooLarge, maxSize)
+	}
+
+	return data, nil
+}
+
+// newLogoClient returns a client fetching logos from public addresses only, so that logo URLs
+// can't reach internal networks. Addresses are checked on dial, after name resolution,
+// so redirects and names resolving to internal addresses are refused too.
+func newLogoClient(timeout time.Duration) *http.Client {
+	dialer := &net.Dialer{
+		Timeout: timeout,
+		Control: func(_, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+
+			if addr, err := netip.ParseAddr(host); err != nil || !isPublicIP(addr) {
+				return fmt.Errorf("%w: %s", ErrLogoAddressNotAllowed, host)
+			}
+
+			return nil
+		},
+	}
+
+	return &http.Client{
+		Transport: &http.Transport{
+			DialContext:         dialer.DialContext,
+			TLSHandshakeTimeout: timeout,
+		},
+		Timeout: timeout,
+		CheckRedirect: func(_ *http.Request, via []*http.Request) error {
+			if len(via) >= logoMaxRedirects {
+				return fmt.Errorf("stopped after %d redirects", logoMaxRedirects)
+			}
+
+			return nil
+		},
+	}
+}
+
+// isPublicIP reports if an address is not in a special-purpose range. IPv4 addresses mapped to IPv6
+// and translated by NAT64 are checked as IPv4.
+func isPublicIP(addr netip.Addr) bool {
+	addr = addr.WithZone("").Unmap()
+
+	if nat64Prefix.Contains(addr) {
+		b := addr.As16()
+		addr = netip.AddrFrom4([4]byte{b[12], b[13], b[14], b[15]})
+	}
+
+	for _, prefix := range deniedPrefixes {
+		if prefix.Contains(addr) {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,111 @@
+package validation
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/trustwallet/assets-manager/internal/config"
+)
+
+func Test_FetchLogo(t *testing.T) {
+	config.Default.Validation.Logo.MaxFileSize = 8
+	config.Default.Validation.Logo.FetchTimeout = time.Second
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/logo.png":
+			_, _ = w.Write([]byte("logo"))
+		case "/large.png":
+			_, _ = w.Write([]byte(strings.Repeat("x", 9)))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name    string
+		url     string
+		want    string
+		wantErr error
+	}{
+		{name: "Logo", url: server.URL + "/logo.png", want: "logo"},
+		{name: "Too large", url: server.URL + "/large.png", wantErr: ErrLogoTooLarge},
+		{name: "Not found", url: server.URL + "/missing.png", wantErr: errors.New("")},
+		{name: "Not http", url: "file:///etc/passwd", wantErr: ErrInvalidLogoURL},
+		{name: "No host", url: "https:///logo.png", wantErr: ErrInvalidLogoURL},
+		{name: "Loopback", url: server.URL + "/logo.png", wantErr: ErrLogoAddressNotAllowed},
+		{name: "Link-local", url: "http://169.254.169.254/latest/meta-data", wantErr: ErrLogoAddressNotAllowed},
+		{name: "Private", url: "http://10.0.0.1/logo.png", wantErr: ErrLogoAddressNotAllowed},
+	}
+
+	// The test server listens on a loopback address, which is refused by the logo client.
+	testController := &Controller{logoClient: server.Client()}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			controller := testController
+			if errors.Is(tt.wantErr, ErrLogoAddressNotAllowed) {
+				controller = NewController()
+			}
+
+			data, err := controller.FetchLogo(context.Background(), tt.url)
+
+			if tt.wantErr == nil {
+				if err != nil || string(data) != tt.want {
+					t.Errorf("FetchLogo() = %q, %v, want %q", data, err, tt.want)
+				}
+
+				return
+			}
+
+			if err == nil {
+				t.Fatalf("FetchLogo() error = nil, want %v", tt.wantErr)
+			}
+
+			if tt.wantErr.Error() != "" && !errors.Is(err, tt.wantErr) {
+				t.Errorf("FetchLogo() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_IsPublicIP(t *testing.T) {
+	tests := []struct {
+		addr string
+		want bool
+	}{
+		{addr: "1.1.1.1", want: true},
+		{addr: "2606:4700:4700::1111", want: true},
+		{addr: "0.0.0.0", want: false},
+		{addr: "0.1.2.3", want: false},
+		{addr: "10.0.0.1", want: false},
+		{addr: "100.64.0.1", want: false},
+		{addr: "127.0.0.1", want: false},
+		{addr: "169.254.169.254", want: false},
+		{addr: "198.18.0.1", want: false},
+		{addr: "255.255.255.255", want: false},
+		{addr: "::", want: false},
+		{addr: "::1", want: false},
+		{addr: "::ffff:127.0.0.1", want: false},
+		{addr: "::ffff:1.1.1.1", want: true},
+		{addr: "64:ff9b::a9fe:a9fe", want: false},
+		{addr: "64:ff9b::101:101", want: true},
+		{addr: "fd00::1", want: false},
+		{addr: "fe80::1%eth0", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.addr, func(t *testing.T) {
+			if got := isPublicIP(netip.MustParseAddr(tt.addr)); got != tt.want {
+				t.Errorf("isPublicIP(%s) = %v, want %v", tt.addr, got, tt.want)
+			}
+		})
+	}
+}
@@ -1,10 +1,16 @@
 package validation
 
+import (
+	"github.com/trustwallet/assets-manager/internal/findings"
+	"github.com/trustwallet/assets-manager/internal/logo"
+)
+
 type StatusType string
 
 const (
-	StatusTypeOk    StatusType = "ok"
-	StatusTypeError StatusType = "error"
+	StatusTypeOk      StatusType = "ok"
+	StatusTypeWarning StatusType = "warning"
+	StatusTypeError   StatusType = "error"
 )
 
 type (
@@ -29,12 +35,41 @@ type (
 
 	AssetInfoResponse struct {
 		Status StatusType `json:"status"`
-		Errors []Error    `json:"errors"`
+		// Errors are messages of the error findings, kept for clients not reading findings.
+		Errors   []Error            `json:"errors"`
+		Findings []findings.Finding `json:"findings"`
 	}
 
 	Error struct {
 		Message string `json:"message"`
 	}
+
+	// AssetInfoBatchResult is a validation result of an asset of a batch by its index in the batch.
+	// A batch which can't be read further ends with a result with the error only.
+	AssetInfoBatchResult struct {
+		Index int    `json:"index"`
+		ID    string `json:"id"`
+		Type  string `json:"type"`
+		Error string `json:"error,omitempty"`
+		*AssetInfoResponse
+	}
+)
+
+type (
+	LogoResponse struct {
+		Status   StatusType         `json:"status"`
+		Logo     *logo.Info         `json:"logo"`
+		Errors   []Error            `json:"errors"`
+		Findings []findings.Finding `json:"findings"`
+	}
+
+	// LogoFixResponse is a fixed logo, base64 encoded, with a report of the changes.
+	// The status is an error if the logo still fails the checks, listed in the report findings.
+	LogoFixResponse struct {
+		Status StatusType      `json:"status"`
+		Logo   []byte          `json:"logo"`
+		Report *logo.FixReport `json:"report"`
+	}
 )
 
 type (
@@ -1,7 +1,27 @@
 package validation
 
-type Controller struct{}
+import (
+	"net/http"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/trustwallet/assets-manager/internal/config"
+	"github.com/trustwallet/assets-manager/internal/onchain"
+)
+
+type Controller struct {
+	metadata   *onchain.Resolver
+	logoClient *http.Client
+}
 
 func NewController() *Controller {
-	return &Controller{}
+	metadata, err := onchain.NewConfiguredResolver()
+	if err != nil {
+		log.WithError(err).Fatal("Invalid token metadata provider")
+	}
+
+	return &Controller{
+		logoClient: newLogoClient(config.Default.Validation.Logo.FetchTimeout),
+		metadata:   metadata,
+	}
 }
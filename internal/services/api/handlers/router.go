@@ -36,6 +36,9 @@ func NewRouter(mq *mq.Client) http.Handler {
 
 func (api *ValidationAPI) Setup(router *gin.Engine) {
 	router.POST("/v1/validate/asset_info", api.ValidateAssetInfo)
+	router.POST("/v1/validate/asset_info/batch", api.ValidateAssetInfoBatch)
+	router.POST("/v1/validate/logo", api.ValidateLogo)
+	router.POST("/v1/logo/fix", api.FixLogo)
 	router.GET("/v1/validate/url/status", api.CheckURLStatus)
 }
 
@@ -1,12 +1,15 @@
 package handlers
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
 	log "github.com/sirupsen/logrus"
 
+	"github.com/trustwallet/assets-manager/internal/config"
 	"github.com/trustwallet/assets-manager/internal/services/api/controllers/validation"
 )
 
@@ -31,11 +34,104 @@ func (api *ValidationAPI) ValidateAssetInfo(c *gin.Context) {
 		return
 	}
 
-	response := api.validator.ValidateAssetInfo(request)
+	response := api.validator.ValidateAssetInfo(c.Request.Context(), request)
 
 	c.JSON(http.StatusOK, response)
 }
 
+// @Description Validate a batch of asset infos, a JSON array or newline-delimited JSON objects.
+// @Description Assets are validated as they are read, results are streamed as newline-delimited JSON
+// @Description in the order of completion. A batch which can't be read further ends with an error result.
+// @Router /v1/validate/asset_info/batch [post]
+func (api *ValidationAPI) ValidateAssetInfoBatch(c *gin.Context) {
+	body := http.MaxBytesReader(c.Writer, c.Request.Body, config.Default.Validation.Batch.MaxBodySize)
+	batch := validation.NewAssetInfoBatchDecoder(body, config.Default.Validation.Batch.MaxAssets)
+
+	results := api.validator.ValidateAssetInfoBatch(c.Request.Context(), batch,
+		config.Default.Validation.Batch.Workers)
+
+	c.Header("Content-Type", "application/x-ndjson")
+	c.Status(http.StatusOK)
+
+	encoder := json.NewEncoder(c.Writer)
+
+	for result := range results {
+		if result.Error != "" {
+			log.WithField("error", result.Error).Debug("Invalid asset info batch")
+		}
+
+		if err := encoder.Encode(result); err != nil {
+			log.WithError(err).Debug("Failed to write asset info batch result")
+
+			continue
+		}
+
+		c.Writer.Flush()
+	}
+}
+
+// @Description Validate a logo uploaded as a multipart "file" or downloaded by "url" (query or form)
+// @Router /v1/validate/logo [post]
+func (api *ValidationAPI) ValidateLogo(c *gin.Context) {
+	data, err := api.readLogo(c)
+	if err != nil {
+		log.WithError(err).Debug("Failed to read logo")
+		c.AbortWithStatusJSON(http.StatusBadRequest, errorResponse(err.Error()))
+
+		return
+	}
+
+	c.JSON(http.StatusOK, api.validator.ValidateLogo(data))
+}
+
+// @Description Fix a logo uploaded as a multipart "file" or downloaded by "url" (query or form):
+// @Description convert PNG, JPEG, GIF, WebP or SVG to PNG, resize and compress it to the logo requirements
+// @Router /v1/logo/fix [post]
+func (api *ValidationAPI) FixLogo(c *gin.Context) {
+	data, err := api.readLogo(c)
+	if err != nil {
+		log.WithError(err).Debug("Failed to read logo")
+		c.AbortWithStatusJSON(http.StatusBadRequest, errorResponse(err.Error()))
+
+		return
+	}
+
+	response, err := api.validator.FixLogo(data)
+	if err != nil {
+		log.WithError(err).Debug("Failed to fix logo")
+		c.AbortWithStatusJSON(http.StatusUnprocessableEntity, errorResponse(err.Error()))
+
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// readLogo returns a logo uploaded as a multipart file or downloaded by URL.
+func (api *ValidationAPI) readLogo(c *gin.Context) ([]byte, error) {
+	header, err := c.FormFile("file")
+	if err == nil {
+		file, openErr := header.Open()
+		if openErr != nil {
+			return nil, fmt.Errorf("failed to open uploaded logo: %w", openErr)
+		}
+		defer file.Close()
+
+		return validation.ReadLogo(file)
+	}
+
+	logoURL := c.Query("url")
+	if logoURL == "" {
+		logoURL = c.PostForm("url")
+	}
+
+	if logoURL == "" {
+		return nil, errors.New("logo file or url is required")
+	}
+
+	return api.validator.FetchLogo(c.Request.Context(), logoURL)
+}
+
 // @Description Checks URL's status
 // @Router /v1/validate/url/status [get]
 func (api *ValidationAPI) CheckURLStatus(c *gin.Context) {
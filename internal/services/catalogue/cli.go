@@ -0,0 +1,120 @@
+package catalogue
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"image"
+	"io"
+	"os"
+	"path/filepath"
+	"text/tabwriter"
+
+	"github.com/trustwallet/assets-manager/internal/config"
+	"github.com/trustwallet/assets-manager/internal/impersonation"
+)
+
+const usage = `Usage: catalogue <command> [arguments]
+
+Commands:
+  build <checkout> <index>    index active tokens of a local checkout of the assets repository
+  match <index> <asset dir>   print tokens of the index an asset is likely to impersonate
+`
+
+var ErrUsage = errors.New("invalid arguments")
+
+// Run executes a catalogue index command and writes its output to out.
+func Run(args []string, out io.Writer) error {
+	if len(args) != 3 {
+		fmt.Fprint(out, usage)
+
+		return ErrUsage
+	}
+
+	switch args[0] {
+	case "build":
+		index, err := impersonation.Build(args[1])
+		if err != nil {
+			return err
+		}
+
+		if err = index.Save(args[2]); err != nil {
+			return err
+		}
+
+		fmt.Fprintf(out, "indexed %d tokens to %s\n", len(index.Entries), args[2])
+
+		return nil
+	case "match":
+		config.SetConfig()
+
+		index, err := impersonation.Load(args[1])
+		if err != nil {
+			return err
+		}
+
+		candidate, err := readCandidate(args[2])
+		if err != nil {
+			return err
+		}
+
+		options := config.Default.Validation.Impersonation
+
+		return printMatches(out, index.Match(candidate, impersonation.Options{
+			Threshold:    options.Threshold,
+			LogoDistance: options.LogoDistance,
+			MaxMatches:   options.MaxMatches,
+		}))
+	}
+
+	fmt.Fprint(out, usage)
+
+	return ErrUsage
+}
+
+// readCandidate reads info.json and logo.png of an asset directory, blockchains/<chain>/assets/<id>.
+func readCandidate(dir string) (impersonation.Candidate, error) {
+	dir = filepath.Clean(dir)
+	candidate := impersonation.Candidate{
+		ID:    filepath.Base(dir),
+		Chain: filepath.Base(filepath.Dir(filepath.Dir(dir))),
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, "info.json"))
+	if err != nil {
+		return candidate, fmt.Errorf("failed to read info.json: %w", err)
+	}
+
+	var info struct {
+		Name   string `json:"name"`
+		Symbol string `json:"symbol"`
+	}
+
+	if err = json.Unmarshal(data, &info); err != nil {
+		return candidate, fmt.Errorf("failed to parse info.json: %w", err)
+	}
+
+	candidate.Name, candidate.Symbol = info.Name, info.Symbol
+
+	if file, openErr := os.Open(filepath.Join(dir, "logo.png")); openErr == nil {
+		defer file.Close()
+
+		if candidate.Logo, _, err = image.Decode(file); err != nil {
+			return candidate, fmt.Errorf("failed to decode logo.png: %w", err)
+		}
+	}
+
+	return candidate, nil
+}
+
+func printMatches(out io.Writer, matches []impersonation.Match) error {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "SCORE\tCHAIN\tID\tNAME\tSYMBOL\tREASONS")
+
+	for i := range matches {
+		m := &matches[i]
+		fmt.Fprintf(w, "%.2f\t%s\t%s\t%s\t%s\t%s\n", m.Score, m.Chain, m.ID, m.Name, m.Symbol, m.Reasons())
+	}
+
+	return w.Flush()
+}
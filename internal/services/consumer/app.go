@@ -10,29 +10,52 @@ import (
 	"github.com/prometheus/client_golang/prometheus"
 	log "github.com/sirupsen/logrus"
 
-	assetsmanager "github.com/trustwallet/assets-go-libs/client/assets-manager"
 	"github.com/trustwallet/assets-manager/internal/config"
+	"github.com/trustwallet/assets-manager/internal/impersonation"
+	"github.com/trustwallet/assets-manager/internal/onchain"
 	"github.com/trustwallet/assets-manager/internal/queue"
 	"github.com/trustwallet/assets-manager/internal/services"
+	"github.com/trustwallet/assets-manager/internal/services/consumer/assetinfo"
 	"github.com/trustwallet/assets-manager/internal/services/consumer/blockchain"
+	"github.com/trustwallet/assets-manager/internal/services/consumer/burns"
 	"github.com/trustwallet/assets-manager/internal/services/consumer/events"
 	"github.com/trustwallet/assets-manager/internal/services/consumer/github"
+	"github.com/trustwallet/assets-manager/internal/services/consumer/ledger"
 	"github.com/trustwallet/assets-manager/internal/services/consumer/metrics"
+	"github.com/trustwallet/assets-manager/internal/services/consumer/prstate"
+	"github.com/trustwallet/assets-manager/internal/services/consumer/reconcile"
+	"github.com/trustwallet/assets-manager/internal/services/consumer/refunds"
+	"github.com/trustwallet/assets-manager/internal/storage"
 	metricsLib "github.com/trustwallet/go-libs/metrics"
 	"github.com/trustwallet/go-libs/mq"
 	"github.com/trustwallet/go-libs/worker"
 )
 
 type App struct {
+	store         storage.Store
 	mqClient      *mq.Client
-	eventHandler  *events.Handler
+	handlers      events.Handlers
+	retries       *queue.RetryQueue
 	metricsPusher worker.Worker
 }
 
 func NewApp() *App {
 	services.Setup()
 
-	githubClient, err := github.NewClient()
+	store, err := storage.New(config.Default.Storage.Driver, config.Default.Storage.Path)
+	if err != nil {
+		log.WithError(err).Fatal("failed to init storage")
+	}
+
+	githubCache, err := github.NewCache(config.Default.Github.Cache.Driver, config.Default.Github.Cache.MaxEntries,
+		config.Default.Github.Cache.TTL, store)
+	if err != nil {
+		log.WithError(err).Fatal("failed to init github cache")
+	}
+
+	prometheus := metrics.NewPrometheus()
+
+	githubClient, err := github.NewClient(prometheus, githubCache)
 	if err != nil {
 		log.WithError(err).Fatal("failed to create github instance")
 	}
@@ -50,14 +73,74 @@ func NewApp() *App {
 		log.WithError(err).Error("failed to init metrics pusher")
 	}
 
-	assetsManagerClient := assetsmanager.InitClient(config.Default.Clients.AssetsManager.API, nil)
-	blockchainClient := blockchain.NewClient()
-	prometheus := metrics.NewPrometheus()
-	eventHandler := events.NewHandler(prometheus, githubClient, blockchainClient, &assetsManagerClient)
+	// Payments of all repositories are kept in one ledger and matched by PR number,
+	// so a payment chain can't be shared by repositories.
+	if err = config.Default.CheckPaymentChains(); err != nil {
+		log.WithError(err).Fatal("invalid repositories config")
+	}
+
+	paymentChains, err := blockchain.NewPaymentChains()
+	if err != nil {
+		log.WithError(err).Fatal("failed to init payment chains")
+	}
+
+	paymentLedger, err := ledger.New(store, paymentChains.List(), ledger.Options{
+		History:      config.Default.Payment.Ledger.History,
+		SyncWindow:   config.Default.Payment.Ledger.SyncWindow,
+		SyncInterval: config.Default.Payment.Ledger.SyncInterval,
+	})
+	if err != nil {
+		log.WithError(err).Fatal("failed to init payment ledger")
+	}
+
+	burnOptions := burns.Options{
+		MaxAttempts: config.Default.Payment.Burn.MaxAttempts,
+		Backoff:     config.Default.Payment.Burn.Backoff,
+		MaxBackoff:  config.Default.Payment.Burn.MaxBackoff,
+	}
+
+	if config.Default.Payment.Burn.Batch.Enabled {
+		burnOptions.BatchInterval = config.Default.Payment.Burn.Batch.Interval
+		burnOptions.BatchThreshold = config.Default.Payment.Burn.Batch.Threshold
+	}
+
+	assetInfoClient := assetinfo.NewClient(config.Default.Clients.AssetsManager.API)
+
+	metadata, err := onchain.NewConfiguredResolver()
+	if err != nil {
+		log.WithError(err).Fatal("failed to init token metadata providers")
+	}
+
+	var catalogue *impersonation.Index
+	if config.Default.Validation.Impersonation.Index != "" {
+		if catalogue, err = impersonation.Load(config.Default.Validation.Impersonation.Index); err != nil {
+			log.WithError(err).Fatal("failed to load impersonation index")
+		}
+	}
+
+	handlers := make(events.Handlers)
+
+	repos := config.Default.Repos()
+	for i := range repos {
+		repo := repos[i]
+
+		// Pull request numbers are unique per repository only, so every repository has its own buckets.
+		// The repository served before multiple repositories were supported keeps the unprefixed ones.
+		repoStore := store
+		if !config.Default.IsLegacyRepo(&repo) {
+			repoStore = storage.WithPrefix(store, repo.FullName()+"/")
+		}
+
+		handlers.Add(events.NewHandler(repo, prometheus, githubClient, paymentChains, paymentLedger,
+			refunds.NewRegistry(repoStore), reconcile.NewStore(repoStore), prstate.NewMachine(repoStore),
+			burns.NewQueue(repoStore, burnOptions), assetInfoClient, metadata, catalogue))
+	}
 
 	return &App{
+		store:         store,
 		mqClient:      mqClient,
-		eventHandler:  eventHandler,
+		handlers:      handlers,
+		retries:       queue.NewRetryQueue(store),
 		metricsPusher: metricsPusher,
 	}
 }
@@ -70,9 +153,12 @@ func (a *App) Run(ctx context.Context) {
 	signal.Notify(stop, os.Interrupt, syscall.SIGINT, syscall.SIGTERM)
 
 	a.mqClient.ListenConnectionAsync(ctx, wg)
-	runBackgroundChecker(ctx, wg, a.eventHandler)
+	runBackgroundChecker(ctx, wg, a.handlers)
+	runPaymentReconciliation(ctx, wg, a.handlers)
+	runBurnProcessor(ctx, wg, a.handlers)
+	runEventRetries(ctx, wg, a.retries, a.mqClient.InitQueue(queue.QueueAssetManagerProcessGithubEvent))
 
-	err := a.mqClient.StartConsumers(ctx, initConsumers(ctx, a.mqClient, a.eventHandler)...)
+	err := a.mqClient.StartConsumers(ctx, initConsumers(ctx, a.mqClient, a.handlers, a.retries)...)
 	if err != nil {
 		log.WithError(err).Fatal("failed to start Rabbit MQ consumers")
 	}
@@ -85,14 +171,17 @@ func (a *App) Run(ctx context.Context) {
 
 	cancel()
 	wg.Wait()
-}
 
-func runBackgroundChecker(ctx context.Context, wg *sync.WaitGroup, eh *events.Handler) {
-	repoOwner := config.Default.Github.RepoOwner
-	repoName := config.Default.Github.RepoName
+	if err := a.store.Close(); err != nil {
+		log.WithError(err).Error("failed to close storage")
+	}
+}
 
+func runBackgroundChecker(ctx context.Context, wg *sync.WaitGroup, handlers events.Handlers) {
 	w := worker.NewWorkerBuilder("pr_checker", func() error {
-		return eh.CheckOpenPullRequests(ctx, repoOwner, repoName, nil)
+		return handlers.Sweep("pr_checker", func(eh *events.Handler, owner, repo string) error {
+			return eh.CheckOpenPullRequests(ctx, owner, repo, nil)
+		})
 	}).
 		WithOptions(worker.DefaultWorkerOptions(config.Default.Timeout.BackgroundCheck)).
 		Build()
@@ -100,7 +189,49 @@ func runBackgroundChecker(ctx context.Context, wg *sync.WaitGroup, eh *events.Ha
 	w.Start(ctx, wg)
 }
 
-func initConsumers(ctx context.Context, mqClient *mq.Client, eh *events.Handler) []mq.Consumer {
+func runPaymentReconciliation(ctx context.Context, wg *sync.WaitGroup, handlers events.Handlers) {
+	w := worker.NewWorkerBuilder("payment_reconciliation", func() error {
+		return handlers.Sweep("payment_reconciliation", func(eh *events.Handler, owner, repo string) error {
+			return eh.ReconcilePayments(ctx, owner, repo)
+		})
+	}).
+		WithOptions(worker.DefaultWorkerOptions(config.Default.Payment.Reconciliation.Interval)).
+		Build()
+
+	w.Start(ctx, wg)
+}
+
+func runBurnProcessor(ctx context.Context, wg *sync.WaitGroup, handlers events.Handlers) {
+	w := worker.NewWorkerBuilder("burn_processor", func() error {
+		return handlers.Sweep("burn_processor", func(eh *events.Handler, owner, repo string) error {
+			return eh.ProcessBurns(ctx, owner, repo)
+		})
+	}).
+		WithOptions(worker.DefaultWorkerOptions(config.Default.Payment.Burn.Interval)).
+		Build()
+
+	w.Start(ctx, wg)
+}
+
+// runEventRetries publishes failed Github events back to the process queue once their retry delay passed.
+func runEventRetries(ctx context.Context, wg *sync.WaitGroup, retries *queue.RetryQueue, processQueue mq.Queue) {
+	w := worker.NewWorkerBuilder("event_retries", func() error {
+		published, err := retries.PublishDue(processQueue.Publish)
+		if published > 0 {
+			log.WithField("count", published).Info("Github events published for retry")
+		}
+
+		return err
+	}).
+		WithOptions(worker.DefaultWorkerOptions(config.Default.Consumer.RetryPollInterval)).
+		Build()
+
+	w.Start(ctx, wg)
+}
+
+func initConsumers(
+	ctx context.Context, mqClient *mq.Client, handlers events.Handlers, retries *queue.RetryQueue,
+) []mq.Consumer {
 	options := mq.DefaultConsumerOptions(config.Default.Consumer.Workers)
 
 	options.PerformanceMetric = metricsLib.NewPerformanceMetric(
@@ -110,7 +241,10 @@ func initConsumers(ctx context.Context, mqClient *mq.Client, eh *events.Handler)
 	)
 
 	consumers := []mq.Consumer{
-		mqClient.InitConsumer(queue.QueueAssetManagerProcessGithubEvent, options, events.GetEventConsumer(ctx, eh)),
+		mqClient.InitConsumer(queue.QueueAssetManagerProcessGithubEvent, options, events.GetEventConsumer(ctx, handlers,
+			retries,
+			mqClient.InitQueue(queue.QueueAssetManagerGithubEventDeadLetter),
+		)),
 	}
 
 	return consumers
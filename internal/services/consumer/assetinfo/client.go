@@ -0,0 +1,52 @@
+// Package assetinfo validates info.json of assets with the assets manager API.
+package assetinfo
+
+import (
+	"errors"
+	"fmt"
+
+	assetsmanager "github.com/trustwallet/assets-go-libs/client/assets-manager"
+	"github.com/trustwallet/assets-manager/internal/findings"
+	"github.com/trustwallet/go-libs/client"
+)
+
+type (
+	Client struct {
+		req client.Request
+	}
+
+	response struct {
+		Status   string             `json:"status"`
+		Errors   []responseError    `json:"errors"`
+		Findings []findings.Finding `json:"findings"`
+	}
+
+	responseError struct {
+		Message string `json:"message"`
+	}
+)
+
+func NewClient(url string) *Client {
+	return &Client{req: client.InitJSONClient(url, nil)}
+}
+
+// Validate returns findings of an asset info. Errors of an API version not reporting findings
+// are returned as findings of the asset info rule.
+func (c *Client) Validate(req *assetsmanager.AssetValidationReq) ([]findings.Finding, error) {
+	var resp response
+
+	if err := c.req.Post(&resp, "/v1/validate/asset_info", req); err != nil {
+		return nil, fmt.Errorf("failed to validate asset info: %w", err)
+	}
+
+	if resp.Findings != nil {
+		return resp.Findings, nil
+	}
+
+	found := make([]findings.Finding, 0, len(resp.Errors))
+	for _, e := range resp.Errors {
+		found = append(found, findings.New(findings.RuleAssetInfo, findings.SeverityError, "", errors.New(e.Message)))
+	}
+
+	return found, nil
+}
@@ -0,0 +1,61 @@
+package assetinfo
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	assetsmanager "github.com/trustwallet/assets-go-libs/client/assets-manager"
+	"github.com/trustwallet/assets-manager/internal/findings"
+)
+
+func Test_Validate(t *testing.T) {
+	tests := []struct {
+		name     string
+		response string
+		want     []findings.Finding
+	}{
+		{
+			name:     "Findings",
+			response: `{"status":"error","errors":[{"message":"bad"}],"findings":[{"rule":"asset_decimals","severity":"error","field":"decimals","message":"bad","fix":"set decimals to 18"}]}`,
+			want: []findings.Finding{
+				{Rule: findings.RuleAssetDecimals, Severity: findings.SeverityError, Field: "decimals", Message: "bad", Fix: "set decimals to 18"},
+			},
+		},
+		{
+			name:     "Errors only",
+			response: `{"status":"error","errors":[{"message":"bad"}]}`,
+			want: []findings.Finding{
+				{Rule: findings.RuleAssetInfo, Severity: findings.SeverityError, Message: "bad"},
+			},
+		},
+		{
+			name:     "OK",
+			response: `{"status":"ok","errors":[],"findings":[]}`,
+			want:     []findings.Finding{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/v1/validate/asset_info" {
+					t.Errorf("path = %s", r.URL.Path)
+				}
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = w.Write([]byte(tt.response))
+			}))
+			defer server.Close()
+
+			got, err := NewClient(server.URL).Validate(&assetsmanager.AssetValidationReq{})
+			if err != nil {
+				t.Fatalf("Validate() error = %v", err)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Validate() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -4,22 +4,45 @@ import (
 	"fmt"
 
 	"github.com/binance-chain/go-sdk/client"
+	"github.com/binance-chain/go-sdk/client/transaction"
 	"github.com/binance-chain/go-sdk/common/types"
 	"github.com/binance-chain/go-sdk/keys"
+	"github.com/binance-chain/go-sdk/types/msg"
 	"github.com/pkg/errors"
 	log "github.com/sirupsen/logrus"
 
 	"github.com/trustwallet/assets-manager/internal/config"
-	"github.com/trustwallet/go-libs/blockchain/binance/api"
 	"github.com/trustwallet/go-primitives/coin"
 )
 
-type Client struct {
-	client       client.DexClient
-	customClient api.Client
+const (
+	binanceTxTypeTransfer = "TRANSFER"
+	binancePageSize       = 100
+)
+
+// BinanceChain is a payment chain working with Binance Chain (BEP2 tokens).
+type BinanceChain struct {
+	name     string
+	coin     uint
+	address  string
+	explorer string
+	client   client.DexClient
+	fetcher  TxFetcher
+}
+
+func NewBinanceChain(name string, coinID uint, address string, dex client.DexClient, fetcher TxFetcher) *BinanceChain {
+	return &BinanceChain{
+		name:     name,
+		coin:     coinID,
+		address:  address,
+		explorer: config.Default.Clients.Binance.Explorer,
+		client:   dex,
+		fetcher:  fetcher,
+	}
 }
 
-func NewClient() *Client {
+// NewDexClient returns a Binance DEX client with a key manager made of the payment seed phrase.
+func NewDexClient() client.DexClient {
 	keyManager, err := keys.NewMnemonicKeyManager(config.Default.Payment.SeedPhrase)
 	if err != nil {
 		log.WithError(err).Error("failed to create a mnemonic key manager")
@@ -30,22 +53,65 @@ func NewClient() *Client {
 		log.WithError(err).Error("failed to create a dex client to binance api")
 	}
 
-	return &Client{
-		client:       c,
-		customClient: api.InitClient(config.Default.Clients.Binance.API, nil),
-	}
+	return c
 }
 
-func (c *Client) BurnToken(token string, amount int64) (string, error) {
-	if c.client == nil {
-		return "", nil
+func (c *BinanceChain) Name() string {
+	return c.name
+}
+
+func (c *BinanceChain) Coin() uint {
+	return c.coin
+}
+
+func (c *BinanceChain) Address() string {
+	return c.address
+}
+
+func (c *BinanceChain) GetIncomingTransfers(startTime, endTime int64) ([]Tx, error) {
+	transfers := make([]Tx, 0)
+
+	for offset := 0; ; offset += binancePageSize {
+		page, err := c.fetcher.GetTransactions(c.address, startTime, endTime, offset, binancePageSize)
+		if err != nil {
+			return nil, err
+		}
+
+		for i := range page.Tx {
+			if page.Tx[i].Type != binanceTxTypeTransfer || page.Tx[i].ToAddr != c.address {
+				continue
+			}
+
+			tx := fromAPITx(&page.Tx[i])
+			tx.Chain = c.name
+			transfers = append(transfers, tx)
+		}
+
+		if len(page.Tx) < binancePageSize || offset+len(page.Tx) >= page.Total {
+			return transfers, nil
+		}
 	}
+}
 
+func (c *BinanceChain) ValidateTx(tx *Tx, memo, token string, startTime, endTime int64) bool {
+	return ValidateTransfer(tx, c.address, memo, token, startTime, endTime)
+}
+
+func (c *BinanceChain) ExplorerLink(hash string) string {
+	return fmt.Sprintf("%s/tx/%s", c.explorer, hash)
+}
+
+func (c *BinanceChain) BurnToken(token string, amount float64) (string, error) {
+	// Nothing is burned for the native coin.
 	if token == coin.Coins[coin.BINANCE].Symbol {
 		return "", nil
 	}
 
-	res, err := c.client.BurnToken(token, amount, true)
+	if c.client == nil {
+		return "", errors.New("dex client is not initialized")
+	}
+
+	res, err := c.client.BurnToken(token, int64(amount*AmountPrecision), true)
 	if err != nil {
 		return "", errors.Wrap(err, "failed to burn a token")
 	}
@@ -55,16 +121,34 @@ func (c *Client) BurnToken(token string, amount int64) (string, error) {
 		"amount": amount,
 	}).Debugf("tokens has been burned")
 
-	return fmt.Sprintf("%s/tx/%s", config.Default.Clients.Binance.Explorer, res.Hash), nil
+	return c.ExplorerLink(res.Hash), nil
 }
 
-func (c *Client) GetTransactionsForAddress(address string) ([]api.Tx, error) {
-	var txs []api.Tx
-	var err error
+func (c *BinanceChain) Transfer(to, token string, amount float64, memo string) (string, error) {
+	if c.client == nil {
+		return "", errors.New("dex client is not initialized")
+	}
+
+	toAddr, err := types.AccAddressFromBech32(to)
+	if err != nil {
+		return "", errors.Wrapf(err, "invalid destination address %s", to)
+	}
 
-	if txs, err = c.customClient.GetTransactionsByAddress(address, 50); err != nil {
-		return nil, errors.Wrap(err, "failed to fetch transactions by address")
+	transfers := []msg.Transfer{{
+		ToAddr: toAddr,
+		Coins:  types.Coins{types.Coin{Denom: token, Amount: int64(amount * AmountPrecision)}},
+	}}
+
+	res, err := c.client.SendToken(transfers, true, transaction.WithMemo(memo))
+	if err != nil {
+		return "", errors.Wrap(err, "failed to send a token")
 	}
 
-	return txs, nil
+	log.WithFields(log.Fields{
+		"token":  token,
+		"amount": amount,
+		"to":     to,
+	}).Debugf("tokens has been sent")
+
+	return c.ExplorerLink(res.Hash), nil
 }
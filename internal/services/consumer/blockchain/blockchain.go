@@ -10,14 +10,37 @@ import (
 
 const AmountPrecision = 100000000.0
 
+// PaymentChain is a blockchain where PR fees can be paid.
+type PaymentChain interface {
+	// Name returns a chain name used in payment options config.
+	Name() string
+	// Coin returns SLIP-44 coin ID of the chain, used for deep links.
+	Coin() uint
+	// Address returns the payment address on the chain.
+	Address() string
+	// GetIncomingTransfers returns all transfers to the payment address within a time range (in milliseconds).
+	GetIncomingTransfers(startTime, endTime int64) ([]Tx, error)
+	// ValidateTx checks if a transfer is a payment with the given memo and token made within a time range.
+	ValidateTx(tx *Tx, memo, token string, startTime, endTime int64) bool
+	// ExplorerLink returns a link to a transaction in the chain explorer.
+	ExplorerLink(hash string) string
+	// BurnToken burns (or forwards to a burn address) received tokens. Returns an explorer link,
+	// empty if there is nothing to burn, e.g. for a native coin.
+	BurnToken(token string, amount float64) (string, error)
+	// Transfer sends tokens from the payment address, e.g. for refunds. Returns an explorer link.
+	Transfer(to, token string, amount float64, memo string) (string, error)
+}
+
 type PaymentStatus struct {
 	Paid         bool
 	Amount       float64
 	Token        string
+	Chain        string
 	Transactions []Tx
 }
 
 type Tx struct {
+	Chain        string
 	Hash         string
 	Amount       float64
 	Token        string
@@ -28,8 +51,9 @@ type Tx struct {
 	ExplorerLink string
 }
 
-func GetPaymentStatus(
-	txs []api.Tx, address, memo, token string, startTime, endTime int64, minAmount float64,
+// GetTransfersPaymentStatus returns a payment status from transfers of a payment chain.
+func GetTransfersPaymentStatus(
+	chain PaymentChain, txs []Tx, memo, token string, startTime, endTime int64, minAmount float64,
 ) *PaymentStatus {
 	if len(txs) == 0 {
 		return &PaymentStatus{}
@@ -39,51 +63,51 @@ func GetPaymentStatus(
 	transactions := make([]Tx, 0)
 
 	for i := range txs {
-		tx := Tx{
-			Hash:        txs[i].Hash,
-			Amount:      txs[i].Amount / 100000000,
-			Token:       txs[i].Asset,
-			Date:        txs[i].BlockTime,
-			Memo:        txs[i].Memo,
-			DestAddress: txs[i].ToAddr,
-			FromAddress: txs[i].FromAddr,
-			ExplorerLink: fmt.Sprintf("%s/tx/%s",
-				config.Default.Clients.Binance.Explorer, txs[i].Hash),
-		}
-
-		valid := ValidateTx(&txs[i], address, memo, token, startTime, endTime)
-		if !valid {
+		if !chain.ValidateTx(&txs[i], memo, token, startTime, endTime) {
 			continue
 		}
 
-		sum += tx.Amount
-		transactions = append(transactions, tx)
+		sum += txs[i].Amount
+		transactions = append(transactions, txs[i])
 	}
 
 	return &PaymentStatus{
 		Paid:         sum >= minAmount,
 		Amount:       sum,
 		Token:        token,
+		Chain:        chain.Name(),
 		Transactions: transactions,
 	}
 }
 
-func ValidateTx(tx *api.Tx, address, memo, token string, startTime, endTime int64) bool {
-	if tx.Type != "TRANSFER" {
+// ValidateTransfer checks transfer destination, time range, memo and token.
+func ValidateTransfer(tx *Tx, address, memo, token string, startTime, endTime int64) bool {
+	if tx.DestAddress != address {
 		return false
 	}
-	if tx.ToAddr != address {
-		return false
-	}
-	if tx.BlockTime < startTime || tx.BlockTime > endTime {
+	if tx.Date < startTime || tx.Date > endTime {
 		return false
 	}
 	if !strings.EqualFold(tx.Memo, memo) {
 		return false
 	}
-	if !strings.EqualFold(tx.Asset, token) {
+	if !strings.EqualFold(tx.Token, token) {
 		return false
 	}
 
 	return true
 }
+
+func fromAPITx(tx *api.Tx) Tx {
+	return Tx{
+		Hash:        tx.Hash,
+		Amount:      tx.Amount / AmountPrecision,
+		Token:       tx.Asset,
+		Date:        tx.BlockTime,
+		Memo:        tx.Memo,
+		DestAddress: tx.ToAddr,
+		FromAddress: tx.FromAddr,
+		ExplorerLink: fmt.Sprintf("%s/tx/%s",
+			config.Default.Clients.Binance.Explorer, tx.Hash),
+	}
+}
@@ -9,7 +9,7 @@ import (
 	"github.com/trustwallet/go-libs/blockchain/binance/api"
 )
 
-func Test_validateTx(t *testing.T) {
+func Test_ValidateTransfer(t *testing.T) {
 	tests := []struct {
 		name      string
 		address   string
@@ -17,7 +17,7 @@ func Test_validateTx(t *testing.T) {
 		token     string
 		startTime int64
 		endTime   int64
-		value     *api.Tx
+		value     *Tx
 		want      bool
 	}{
 		{
@@ -27,13 +27,12 @@ func Test_validateTx(t *testing.T) {
 			token:     "TWT-8C2",
 			startTime: 1630187474000,
 			endTime:   1630792274000,
-			value: &api.Tx{
-				ToAddr:    "correct addr",
-				Memo:      "correct memo",
-				Asset:     "TWT-8C2",
-				BlockTime: 1630757347146,
-				Amount:    2000,
-				Type:      "TRANSFER",
+			value: &Tx{
+				DestAddress: "correct addr",
+				Memo:        "correct memo",
+				Token:       "TWT-8C2",
+				Date:        1630757347146,
+				Amount:      2000,
 			},
 			want: true,
 		},
@@ -44,33 +43,15 @@ func Test_validateTx(t *testing.T) {
 			token:     "BNB",
 			startTime: 1630187474000,
 			endTime:   1630792274000,
-			value: &api.Tx{
-				ToAddr:    "correct addr",
-				Memo:      "CoRrect mEmO",
-				Asset:     "BNB",
-				BlockTime: 1630757347146,
-				Amount:    2000,
-				Type:      "TRANSFER",
+			value: &Tx{
+				DestAddress: "correct addr",
+				Memo:        "CoRrect mEmO",
+				Token:       "BNB",
+				Date:        1630757347146,
+				Amount:      2000,
 			},
 			want: true,
 		},
-		{
-			name:      "Tx type is invalid",
-			address:   "correct addr",
-			memo:      "correct memo",
-			token:     "TWT-8C2",
-			startTime: 1630187474000,
-			endTime:   1630792274000,
-			value: &api.Tx{
-				ToAddr:    "correct addr",
-				Memo:      "correct memo",
-				Asset:     "TWT-8C2",
-				BlockTime: 1630757347146,
-				Amount:    2000,
-				Type:      "WRONG TYPE",
-			},
-			want: false,
-		},
 		{
 			name:      "Tx addr is invalid",
 			address:   "correct addr",
@@ -78,13 +59,12 @@ func Test_validateTx(t *testing.T) {
 			token:     "TWT-8C2",
 			startTime: 1630187474000,
 			endTime:   1630792274000,
-			value: &api.Tx{
-				ToAddr:    "incorrect addr",
-				Memo:      "correct memo",
-				Asset:     "TWT-8C2",
-				BlockTime: 1630757347146,
-				Amount:    2000,
-				Type:      "TRANSFER",
+			value: &Tx{
+				DestAddress: "incorrect addr",
+				Memo:        "correct memo",
+				Token:       "TWT-8C2",
+				Date:        1630757347146,
+				Amount:      2000,
 			},
 			want: false,
 		},
@@ -95,13 +75,12 @@ func Test_validateTx(t *testing.T) {
 			token:     "TWT-8C2",
 			startTime: 1630187474000,
 			endTime:   1630792274000,
-			value: &api.Tx{
-				ToAddr:    "correct addr",
-				Memo:      "correct memo",
-				Asset:     "TWT-8C2",
-				BlockTime: 1640757347146,
-				Amount:    2000,
-				Type:      "TRANSFER",
+			value: &Tx{
+				DestAddress: "correct addr",
+				Memo:        "correct memo",
+				Token:       "TWT-8C2",
+				Date:        1640757347146,
+				Amount:      2000,
 			},
 			want: false,
 		},
@@ -112,13 +91,12 @@ func Test_validateTx(t *testing.T) {
 			token:     "TWT-8C2",
 			startTime: 1630187474000,
 			endTime:   1630792274000,
-			value: &api.Tx{
-				ToAddr:    "correct addr",
-				Memo:      "incorrect memo",
-				Asset:     "TWT-8C2",
-				BlockTime: 1630757347146,
-				Amount:    2000,
-				Type:      "TRANSFER",
+			value: &Tx{
+				DestAddress: "correct addr",
+				Memo:        "incorrect memo",
+				Token:       "TWT-8C2",
+				Date:        1630757347146,
+				Amount:      2000,
 			},
 			want: false,
 		},
@@ -129,13 +107,12 @@ func Test_validateTx(t *testing.T) {
 			token:     "TWT-8C2",
 			startTime: 1630187474000,
 			endTime:   1630792274000,
-			value: &api.Tx{
-				ToAddr:    "correct addr",
-				Memo:      "correct memo",
-				Asset:     "BNB",
-				BlockTime: 1630757347146,
-				Amount:    2000,
-				Type:      "TRANSFER",
+			value: &Tx{
+				DestAddress: "correct addr",
+				Memo:        "correct memo",
+				Token:       "BNB",
+				Date:        1630757347146,
+				Amount:      2000,
 			},
 			want: false,
 		},
@@ -143,7 +120,7 @@ func Test_validateTx(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got := ValidateTx(tt.value, tt.address, tt.memo, tt.token, tt.startTime, tt.endTime)
+			got := ValidateTransfer(tt.value, tt.address, tt.memo, tt.token, tt.startTime, tt.endTime)
 			if got != tt.want {
 				t.Errorf("value = %v, want %v", got, tt.want)
 			}
@@ -151,7 +128,7 @@ func Test_validateTx(t *testing.T) {
 	}
 }
 
-func Test_GetPaymentStatus(t *testing.T) {
+func Test_GetTransfersPaymentStatus(t *testing.T) {
 	tests := []struct {
 		name      string
 		txs       []api.Tx
@@ -199,6 +176,7 @@ func Test_GetPaymentStatus(t *testing.T) {
 				Paid:   true,
 				Amount: 2000,
 				Token:  "TWT-8C2",
+				Chain:  "binance",
 				Transactions: []Tx{
 					{
 						Hash:         "hash",
@@ -238,6 +216,7 @@ func Test_GetPaymentStatus(t *testing.T) {
 				Paid:   true,
 				Amount: 2500,
 				Token:  "TWT-8C2",
+				Chain:  "binance",
 				Transactions: []Tx{
 					{
 						Hash:        "hash",
@@ -298,6 +277,7 @@ func Test_GetPaymentStatus(t *testing.T) {
 				Paid:   true,
 				Amount: 2000,
 				Token:  "TWT-8C2",
+				Chain:  "binance",
 				Transactions: []Tx{
 					{
 						Hash:        "hash1",
@@ -360,6 +340,7 @@ func Test_GetPaymentStatus(t *testing.T) {
 				Paid:   false,
 				Amount: 1800,
 				Token:  "TWT-8C2",
+				Chain:  "binance",
 				Transactions: []Tx{
 					{
 						Hash:        "hash",
@@ -379,10 +360,29 @@ func Test_GetPaymentStatus(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got := GetPaymentStatus(tt.txs, tt.address, tt.memo, tt.token, tt.startTime, tt.endTime, tt.minAmount)
+			chain := NewBinanceChain("binance", 714, tt.address, nil, nil)
+
+			txs := make([]Tx, len(tt.txs))
+			for i := range tt.txs {
+				txs[i] = fromAPITx(&tt.txs[i])
+			}
+
+			got := GetTransfersPaymentStatus(chain, txs, tt.memo, tt.token, tt.startTime, tt.endTime, tt.minAmount)
 			if !reflect.DeepEqual(*got, *tt.want) {
 				t.Errorf("value = %v, want %v", got, tt.want)
 			}
 		})
 	}
 }
+
+func TestBinanceChain_BurnToken_NoClient(t *testing.T) {
+	chain := NewBinanceChain("binance", 714, "bnb1fee", nil, nil)
+
+	if link, err := chain.BurnToken("BNB", 5); err != nil || link != "" {
+		t.Errorf("BurnToken() of the native coin = %q, %v, want nothing burned", link, err)
+	}
+
+	if _, err := chain.BurnToken("TWT-8C2", 700); err == nil {
+		t.Error("BurnToken() without a dex client error = nil")
+	}
+}
@@ -0,0 +1,80 @@
+package blockchain
+
+import (
+	"fmt"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/trustwallet/assets-manager/internal/config"
+)
+
+const (
+	ChainTypeBinance = "binance"
+	ChainTypeEVM     = "evm"
+)
+
+// Chains is a set of configured payment chains by name.
+type Chains map[string]PaymentChain
+
+// NewPaymentChains returns payment chains from the payment config.
+func NewPaymentChains() (Chains, error) {
+	chains := make(Chains)
+	disabled := make(map[string]bool)
+
+	for _, c := range config.Default.Payment.Chains {
+		// A chain without a payment address can't receive payments, it's kept in the config to be filled in.
+		if c.Address == "" {
+			log.WithField("chain", c.Name).Warn("Payment chain without an address is disabled")
+
+			disabled[c.Name] = true
+
+			continue
+		}
+
+		switch c.Type {
+		case ChainTypeBinance:
+			chains[c.Name] = NewBinanceChain(c.Name, c.Coin, c.Address,
+				NewDexClient(), NewTxFetcher(config.Default.Clients.Binance.API))
+		case ChainTypeEVM:
+			chains[c.Name] = NewEVMChain(c.Name, c.Coin, c.Address, c.API, c.APIKey, c.RPC, c.Explorer)
+		default:
+			return nil, fmt.Errorf("unknown payment chain type '%s' of chain '%s'", c.Type, c.Name)
+		}
+	}
+
+	for _, repo := range config.Default.Repos() {
+		for _, option := range repo.PaymentOptions {
+			if disabled[option.Chain] {
+				return nil, fmt.Errorf("payment option %s of %s refers to chain '%s' without an address",
+					option.Symbol, repo.FullName(), option.Chain)
+			}
+
+			if _, ok := chains[option.Chain]; !ok {
+				return nil, fmt.Errorf("payment option %s of %s refers to unknown chain '%s'",
+					option.Symbol, repo.FullName(), option.Chain)
+			}
+		}
+	}
+
+	return chains, nil
+}
+
+// Get returns a chain by name.
+func (c Chains) Get(name string) (PaymentChain, error) {
+	chain, ok := c[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown payment chain '%s'", name)
+	}
+
+	return chain, nil
+}
+
+// List returns all chains.
+func (c Chains) List() []PaymentChain {
+	list := make([]PaymentChain, 0, len(c))
+	for _, chain := range c {
+		list = append(list, chain)
+	}
+
+	return list
+}
@@ -0,0 +1,63 @@
+package blockchain
+
+import (
+	"testing"
+
+	"github.com/trustwallet/assets-manager/internal/config"
+)
+
+func Test_NewPaymentChains(t *testing.T) {
+	chains := []config.PaymentChain{
+		{Name: "binance", Type: ChainTypeBinance, Coin: 714, Address: "bnb1address"},
+		{Name: "smartchain", Type: ChainTypeEVM, Coin: 20000714},
+	}
+
+	tests := []struct {
+		name    string
+		options []config.PaymentOption
+		want    []string
+		wantErr bool
+	}{
+		{
+			name:    "Chain without an address is disabled",
+			options: []config.PaymentOption{{Symbol: "BNB", Chain: "binance"}},
+			want:    []string{"binance"},
+		},
+		{
+			name:    "Option of a disabled chain",
+			options: []config.PaymentOption{{Symbol: "BNB", Chain: "smartchain"}},
+			wantErr: true,
+		},
+		{
+			name:    "Option of an unknown chain",
+			options: []config.PaymentOption{{Symbol: "BNB", Chain: "ethereum"}},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config.Default.Payment.Chains = chains
+			config.Default.Payment.Options = tt.options
+
+			got, err := NewPaymentChains()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NewPaymentChains() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if tt.wantErr {
+				return
+			}
+
+			if len(got) != len(tt.want) {
+				t.Fatalf("NewPaymentChains() = %v, want %v", got, tt.want)
+			}
+
+			for _, name := range tt.want {
+				if _, getErr := got.Get(name); getErr != nil {
+					t.Errorf("NewPaymentChains() has no chain %s", name)
+				}
+			}
+		})
+	}
+}
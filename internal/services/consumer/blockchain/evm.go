@@ -0,0 +1,283 @@
+package blockchain
+
+import (
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"net/url"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/pkg/errors"
+
+	"github.com/trustwallet/go-libs/client"
+	"github.com/trustwallet/go-primitives/coin"
+)
+
+const (
+	evmPageSize = 1000
+	// erc20TransferArgsLen is a length of "transfer(address,uint256)" selector and arguments in bytes.
+	erc20TransferArgsLen  = 4 + 32 + 32
+	erc20TransferSelector = "a9059cbb"
+)
+
+type (
+	explorerResponse struct {
+		Status  string      `json:"status"`
+		Message string      `json:"message"`
+		Result  interface{} `json:"result"`
+	}
+
+	explorerTx struct {
+		BlockNumber     string `json:"blockNumber"`
+		TimeStamp       string `json:"timeStamp"`
+		Hash            string `json:"hash"`
+		From            string `json:"from"`
+		To              string `json:"to"`
+		Value           string `json:"value"`
+		Input           string `json:"input"`
+		IsError         string `json:"isError"`
+		ContractAddress string `json:"contractAddress"`
+		TokenDecimal    string `json:"tokenDecimal"`
+	}
+
+	rpcTx struct {
+		Hash  string `json:"hash"`
+		Input string `json:"input"`
+	}
+)
+
+// EVMChain is a payment chain working with EVM-compatible chains via an etherscan-compatible
+// explorer API. Memo is read from transaction input data: for native transfers it's the whole input,
+// for ERC20 transfers it's the data appended after transfer() arguments. Most wallets can't add such data,
+// so the chain is experimental: payments without a memo are left to the reconciliation.
+type EVMChain struct {
+	name     string
+	coin     uint
+	address  string
+	apiKey   string
+	explorer string
+	api      client.Request
+	rpc      client.Request
+}
+
+func NewEVMChain(name string, coinID uint, address, apiURL, apiKey, rpcURL, explorer string) *EVMChain {
+	return &EVMChain{
+		name:     name,
+		coin:     coinID,
+		address:  address,
+		apiKey:   apiKey,
+		explorer: explorer,
+		api:      client.InitJSONClient(apiURL, nil),
+		rpc:      client.InitJSONClient(rpcURL, nil),
+	}
+}
+
+func (c *EVMChain) Name() string {
+	return c.name
+}
+
+func (c *EVMChain) Coin() uint {
+	return c.coin
+}
+
+func (c *EVMChain) Address() string {
+	return c.address
+}
+
+func (c *EVMChain) GetIncomingTransfers(startTime, endTime int64) ([]Tx, error) {
+	startBlock, err := c.getBlockByTime(startTime / 1000)
+	if err != nil {
+		return nil, err
+	}
+
+	native, err := c.getExplorerTxs("txlist", startBlock)
+	if err != nil {
+		return nil, err
+	}
+
+	tokens, err := c.getExplorerTxs("tokentx", startBlock)
+	if err != nil {
+		return nil, err
+	}
+
+	transfers := make([]Tx, 0)
+
+	for i := range native {
+		if native[i].IsError == "1" || native[i].Value == "0" {
+			continue
+		}
+
+		tx, ok := c.toTransfer(&native[i], coin.Coins[c.coin].Symbol, coin.Coins[c.coin].Decimals, startTime, endTime)
+		if !ok {
+			continue
+		}
+
+		tx.Memo = decodeMemo(native[i].Input)
+		transfers = append(transfers, tx)
+	}
+
+	for i := range tokens {
+		decimals, err := strconv.Atoi(tokens[i].TokenDecimal)
+		if err != nil {
+			continue
+		}
+
+		tx, ok := c.toTransfer(&tokens[i], tokens[i].ContractAddress, uint(decimals), startTime, endTime)
+		if !ok {
+			continue
+		}
+
+		tx.Memo, err = c.getTokenTransferMemo(tx.Hash)
+		if err != nil {
+			return nil, err
+		}
+
+		transfers = append(transfers, tx)
+	}
+
+	return transfers, nil
+}
+
+func (c *EVMChain) ValidateTx(tx *Tx, memo, token string, startTime, endTime int64) bool {
+	if !strings.EqualFold(tx.DestAddress, c.address) {
+		return false
+	}
+
+	transfer := *tx
+	transfer.DestAddress = c.address
+
+	return ValidateTransfer(&transfer, c.address, memo, token, startTime, endTime)
+}
+
+func (c *EVMChain) ExplorerLink(hash string) string {
+	return fmt.Sprintf("%s/tx/%s", c.explorer, hash)
+}
+
+// BurnToken is not supported on EVM chains yet. The error fails the burn job,
+// so moderators are notified to burn the tokens kept on the payment address manually.
+func (c *EVMChain) BurnToken(token string, amount float64) (string, error) {
+	return "", fmt.Errorf("burns are not supported on chain %s, %v %s have to be burned manually", c.name, amount, token)
+}
+
+// Transfer is not supported on EVM chains yet, refunds must be sent manually.
+func (c *EVMChain) Transfer(to, token string, amount float64, memo string) (string, error) {
+	return "", fmt.Errorf("transfers are not supported on chain %s", c.name)
+}
+
+func (c *EVMChain) toTransfer(etx *explorerTx, token string, decimals uint, startTime, endTime int64) (Tx, bool) {
+	if !strings.EqualFold(etx.To, c.address) {
+		return Tx{}, false
+	}
+
+	timestamp, err := strconv.ParseInt(etx.TimeStamp, 10, 64)
+	if err != nil || timestamp*1000 < startTime || timestamp*1000 > endTime {
+		return Tx{}, false
+	}
+
+	return Tx{
+		Chain:        c.name,
+		Hash:         etx.Hash,
+		Amount:       toFloatAmount(etx.Value, decimals),
+		Token:        token,
+		Date:         timestamp * 1000,
+		DestAddress:  etx.To,
+		FromAddress:  etx.From,
+		ExplorerLink: c.ExplorerLink(etx.Hash),
+	}, true
+}
+
+func (c *EVMChain) getBlockByTime(timestamp int64) (string, error) {
+	params := url.Values{
+		"module":    {"block"},
+		"action":    {"getblocknobytime"},
+		"timestamp": {strconv.FormatInt(timestamp, 10)},
+		"closest":   {"after"},
+		"apikey":    {c.apiKey},
+	}
+
+	var block string
+
+	resp := explorerResponse{Result: &block}
+	if err := c.api.Get(&resp, "api", params); err != nil {
+		return "", errors.Wrap(err, "failed to get block by time")
+	}
+
+	if resp.Status != "1" {
+		return "", fmt.Errorf("failed to get block by time: %s", resp.Message)
+	}
+
+	return block, nil
+}
+
+func (c *EVMChain) getExplorerTxs(action, startBlock string) ([]explorerTx, error) {
+	txs := make([]explorerTx, 0)
+
+	for page := 1; ; page++ {
+		params := url.Values{
+			"module":     {"account"},
+			"action":     {action},
+			"address":    {c.address},
+			"startblock": {startBlock},
+			"page":       {strconv.Itoa(page)},
+			"offset":     {strconv.Itoa(evmPageSize)},
+			"sort":       {"asc"},
+			"apikey":     {c.apiKey},
+		}
+
+		var result []explorerTx
+
+		resp := explorerResponse{Result: &result}
+		if err := c.api.Get(&resp, "api", params); err != nil {
+			return nil, errors.Wrapf(err, "failed to get %s", action)
+		}
+
+		txs = append(txs, result...)
+
+		if len(result) < evmPageSize {
+			return txs, nil
+		}
+	}
+}
+
+func (c *EVMChain) getTokenTransferMemo(hash string) (string, error) {
+	var tx rpcTx
+	if err := c.rpc.RpcCall(&tx, "eth_getTransactionByHash", []string{hash}); err != nil {
+		return "", errors.Wrap(err, "failed to get transaction by hash")
+	}
+
+	input := strings.TrimPrefix(tx.Input, "0x")
+	if !strings.HasPrefix(input, erc20TransferSelector) || len(input) <= erc20TransferArgsLen*2 {
+		return "", nil
+	}
+
+	return decodeMemo(input[erc20TransferArgsLen*2:]), nil
+}
+
+// decodeMemo decodes a memo from hex input data, an empty memo is returned for non-text data.
+func decodeMemo(input string) string {
+	data, err := hex.DecodeString(strings.TrimPrefix(input, "0x"))
+	if err != nil {
+		return ""
+	}
+
+	memo := strings.Trim(string(data), "\x00 ")
+	if !utf8.ValidString(memo) {
+		return ""
+	}
+
+	return memo
+}
+
+func toFloatAmount(value string, decimals uint) float64 {
+	v, ok := new(big.Float).SetString(value)
+	if !ok {
+		return 0
+	}
+
+	divider := new(big.Float).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(decimals)), nil))
+	amount, _ := new(big.Float).Quo(v, divider).Float64()
+
+	return amount
+}
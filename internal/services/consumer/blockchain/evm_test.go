@@ -0,0 +1,46 @@
+package blockchain
+
+import "testing"
+
+func Test_decodeMemo(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{name: "Memo in native transfer input", input: "0x33333935", want: "3395"},
+		{name: "Memo padded with zero bytes", input: "3132000000", want: "12"},
+		{name: "Empty input", input: "0x", want: ""},
+		{name: "Not a hex input", input: "0xzz", want: ""},
+		{name: "Binary data", input: "0xfffe", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := decodeMemo(tt.input); got != tt.want {
+				t.Errorf("decodeMemo() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_toFloatAmount(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    string
+		decimals uint
+		want     float64
+	}{
+		{name: "18 decimals", value: "700000000000000000000", decimals: 18, want: 700},
+		{name: "8 decimals", value: "150000000", decimals: 8, want: 1.5},
+		{name: "Invalid value", value: "abc", decimals: 18, want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := toFloatAmount(tt.value, tt.decimals); got != tt.want {
+				t.Errorf("toFloatAmount() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,45 @@
+package blockchain
+
+import (
+	"net/url"
+	"strconv"
+
+	"github.com/pkg/errors"
+
+	"github.com/trustwallet/go-libs/blockchain/binance/api"
+	"github.com/trustwallet/go-libs/client"
+)
+
+// TxFetcher fetches a page of address transactions within a time range (in milliseconds).
+type TxFetcher interface {
+	GetTransactions(address string, startTime, endTime int64, offset, limit int) (*api.TransactionsResponse, error)
+}
+
+type apiTxFetcher struct {
+	req client.Request
+}
+
+// NewTxFetcher returns a TxFetcher working with Binance Chain API.
+func NewTxFetcher(url string) TxFetcher {
+	return &apiTxFetcher{req: client.InitJSONClient(url, nil)}
+}
+
+func (f *apiTxFetcher) GetTransactions(
+	address string, startTime, endTime int64, offset, limit int,
+) (*api.TransactionsResponse, error) {
+	params := url.Values{
+		"address":   {address},
+		"startTime": {strconv.FormatInt(startTime, 10)},
+		"endTime":   {strconv.FormatInt(endTime, 10)},
+		"offset":    {strconv.Itoa(offset)},
+		"limit":     {strconv.Itoa(limit)},
+	}
+
+	var result api.TransactionsResponse
+
+	if err := f.req.Get(&result, "bc/api/v1/txs", params); err != nil {
+		return nil, errors.Wrap(err, "failed to fetch transactions by address")
+	}
+
+	return &result, nil
+}
@@ -0,0 +1,362 @@
+package burns
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/trustwallet/assets-manager/internal/storage"
+)
+
+const bucketJobs = "burn_jobs"
+
+type Status string
+
+const (
+	StatusPending Status = "pending"
+	// StatusInProgress is a job being burned. A job left in progress by a crash is failed,
+	// since the burn may have been sent.
+	StatusInProgress Status = "in_progress"
+	StatusDone       Status = "done"
+	StatusFailed     Status = "failed"
+)
+
+const errInterrupted = "burn interrupted, the result is unknown"
+
+type (
+	// Job is a burn of a fee paid for a pull request.
+	Job struct {
+		ID            string    `json:"id"`
+		PRNum         int       `json:"pr_num"`
+		Chain         string    `json:"chain"`
+		Token         string    `json:"token"`
+		Amount        float64   `json:"amount"`
+		TxHash        string    `json:"tx_hash"`
+		Status        Status    `json:"status"`
+		Attempts      int       `json:"attempts"`
+		NextAttemptAt time.Time `json:"next_attempt_at"`
+		LastError     string    `json:"last_error,omitempty"`
+		BurnLink      string    `json:"burn_link,omitempty"`
+		BatchSize     int       `json:"batch_size"`
+		Notified      bool      `json:"notified"`
+		CreatedAt     time.Time `json:"created_at"`
+		UpdatedAt     time.Time `json:"updated_at"`
+	}
+
+	Options struct {
+		// MaxAttempts is the number of attempts before a job is failed.
+		MaxAttempts int
+		// Backoff is the delay after the first failed attempt, doubled after every next one.
+		Backoff time.Duration
+		// MaxBackoff is the max delay between attempts.
+		MaxBackoff time.Duration
+		// BatchInterval is the max time a job waits to be burned with other jobs of the same token.
+		// Zero disables batching.
+		BatchInterval time.Duration
+		// BatchThreshold is a total amount of a token burned in a batch before the interval passes.
+		BatchThreshold float64
+	}
+
+	// BurnFunc burns tokens of a job. Returns an explorer link of the burn transaction.
+	BurnFunc func(job *Job) (string, error)
+
+	// BatchBurnFunc burns total tokens of jobs in one transaction. Returns an explorer link of the burn transaction.
+	BatchBurnFunc func(jobs []*Job) (string, error)
+)
+
+// Queue is a durable queue of burn jobs. A job is keyed by PR number and payment tx hash,
+// so a payment is burned once however many times it is enqueued.
+type Queue struct {
+	store   storage.Store
+	options Options
+
+	mu      sync.Mutex
+	running map[string]bool
+	now     func() time.Time
+}
+
+func NewQueue(store storage.Store, options Options) *Queue {
+	return &Queue{
+		store:   store,
+		options: options,
+		running: make(map[string]bool),
+		now:     time.Now,
+	}
+}
+
+// JobID returns an ID of a burn job for a payment.
+func JobID(prNum int, txHash string) string {
+	return fmt.Sprintf("%d/%s", prNum, txHash)
+}
+
+// Enqueue saves a new pending job. Returns false if the job has been enqueued already.
+func (q *Queue) Enqueue(job *Job) (bool, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	job.ID = JobID(job.PRNum, job.TxHash)
+
+	_, err := q.get(job.ID)
+	if err == nil {
+		return false, nil
+	}
+
+	if !errors.Is(err, storage.ErrNotFound) {
+		return false, err
+	}
+
+	now := q.now()
+	job.Status = StatusPending
+	job.NextAttemptAt = now
+	job.CreatedAt = now
+	job.UpdatedAt = now
+
+	if err = q.save(job); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// Get returns a job by ID.
+func (q *Queue) Get(id string) (*Job, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	return q.get(id)
+}
+
+// List returns all jobs ordered by creation time. Jobs left in progress by a crash are failed.
+func (q *Queue) List() ([]*Job, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	jobs := make([]*Job, 0)
+
+	err := q.store.ForEach(bucketJobs, func(_ string, value []byte) error {
+		var job Job
+		if err := json.Unmarshal(value, &job); err != nil {
+			return fmt.Errorf("failed to decode burn job: %w", err)
+		}
+
+		jobs = append(jobs, &job)
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, job := range jobs {
+		if job.Status == StatusInProgress && !q.running[job.ID] {
+			job.Status = StatusFailed
+			job.LastError = errInterrupted
+			job.UpdatedAt = q.now()
+
+			if err = q.save(job); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	sort.SliceStable(jobs, func(i, j int) bool { return jobs[i].CreatedAt.Before(jobs[j].CreatedAt) })
+
+	return jobs, nil
+}
+
+// IsDue reports if a job is waiting for an attempt.
+func (q *Queue) IsDue(job *Job) bool {
+	return job.Status == StatusPending && !job.NextAttemptAt.After(q.now())
+}
+
+// Run makes an attempt of a due job and returns the job with the result. The attempt is saved
+// before the burn, so a burn is never repeated after an unknown result. A failed attempt is
+// retried with a backoff until max attempts are reached. Jobs not due are returned unchanged.
+func (q *Queue) Run(id string, burn BurnFunc) (*Job, error) {
+	jobs, err := q.RunBatch([]string{id}, func(jobs []*Job) (string, error) {
+		return burn(jobs[0])
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(jobs) == 0 {
+		return q.Get(id)
+	}
+
+	return jobs[0], nil
+}
+
+// RunBatch makes an attempt of due jobs burned in one transaction, same as Run does for one job.
+// Returns the jobs which have been attempted.
+func (q *Queue) RunBatch(ids []string, burn BatchBurnFunc) ([]*Job, error) {
+	q.mu.Lock()
+
+	jobs := make([]*Job, 0, len(ids))
+
+	for _, id := range ids {
+		job, err := q.get(id)
+		if err != nil {
+			q.mu.Unlock()
+
+			return nil, err
+		}
+
+		if q.IsDue(job) && !q.running[id] {
+			jobs = append(jobs, job)
+		}
+	}
+
+	if len(jobs) == 0 {
+		q.mu.Unlock()
+
+		return jobs, nil
+	}
+
+	for _, job := range jobs {
+		job.Status = StatusInProgress
+		job.Attempts++
+		job.BatchSize = len(jobs)
+		job.UpdatedAt = q.now()
+
+		if err := q.save(job); err != nil {
+			q.mu.Unlock()
+
+			return nil, err
+		}
+
+		q.running[job.ID] = true
+	}
+
+	q.mu.Unlock()
+
+	link, burnErr := burn(jobs)
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	for _, job := range jobs {
+		delete(q.running, job.ID)
+
+		job.UpdatedAt = q.now()
+
+		switch {
+		case burnErr == nil:
+			job.Status = StatusDone
+			job.BurnLink = link
+			job.LastError = ""
+		case job.Attempts >= q.options.MaxAttempts:
+			job.Status = StatusFailed
+			job.LastError = burnErr.Error()
+		default:
+			job.Status = StatusPending
+			job.LastError = burnErr.Error()
+			job.NextAttemptAt = job.UpdatedAt.Add(q.backoff(job.Attempts))
+		}
+
+		if err := q.save(job); err != nil {
+			return nil, err
+		}
+	}
+
+	return jobs, nil
+}
+
+// IsBatched reports if jobs are burned in batches.
+func (q *Queue) IsBatched() bool {
+	return q.options.BatchInterval > 0
+}
+
+// DueBatches groups due jobs by chain and token. A group is returned when its oldest job has waited
+// for the batch interval or its total amount reaches the batch threshold.
+func (q *Queue) DueBatches(jobs []*Job) [][]*Job {
+	groups := make(map[string][]*Job)
+	keys := make([]string, 0)
+
+	for _, job := range jobs {
+		if !q.IsDue(job) {
+			continue
+		}
+
+		key := job.Chain + "/" + job.Token
+		if _, ok := groups[key]; !ok {
+			keys = append(keys, key)
+		}
+
+		groups[key] = append(groups[key], job)
+	}
+
+	sort.Strings(keys)
+
+	batches := make([][]*Job, 0)
+
+	for _, key := range keys {
+		var (
+			total  float64
+			oldest = q.now()
+		)
+
+		for _, job := range groups[key] {
+			total += job.Amount
+
+			if job.CreatedAt.Before(oldest) {
+				oldest = job.CreatedAt
+			}
+		}
+
+		thresholdReached := q.options.BatchThreshold > 0 && total >= q.options.BatchThreshold
+		if thresholdReached || q.now().Sub(oldest) >= q.options.BatchInterval {
+			batches = append(batches, groups[key])
+		}
+	}
+
+	return batches
+}
+
+// SetNotified marks a done or a failed job as reported.
+func (q *Queue) SetNotified(id string) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	job, err := q.get(id)
+	if err != nil {
+		return err
+	}
+
+	job.Notified = true
+
+	return q.save(job)
+}
+
+func (q *Queue) backoff(attempts int) time.Duration {
+	delay := q.options.Backoff
+	for i := 1; i < attempts && delay < q.options.MaxBackoff; i++ {
+		delay *= 2
+	}
+
+	if delay > q.options.MaxBackoff {
+		delay = q.options.MaxBackoff
+	}
+
+	return delay
+}
+
+func (q *Queue) get(id string) (*Job, error) {
+	var job Job
+	if err := q.store.Get(bucketJobs, id, &job); err != nil {
+		return nil, fmt.Errorf("failed to read burn job %s: %w", id, err)
+	}
+
+	return &job, nil
+}
+
+func (q *Queue) save(job *Job) error {
+	if err := q.store.Put(bucketJobs, job.ID, job); err != nil {
+		return fmt.Errorf("failed to save burn job: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,180 @@
+package burns
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/trustwallet/assets-manager/internal/storage"
+)
+
+func newTestQueue(now *time.Time) *Queue {
+	q := NewQueue(storage.NewMemoryStore(), Options{MaxAttempts: 3, Backoff: time.Minute, MaxBackoff: 90 * time.Second})
+	q.now = func() time.Time { return *now }
+
+	return q
+}
+
+func TestQueue_RetryAndFail(t *testing.T) {
+	now := time.Date(2021, 10, 1, 0, 0, 0, 0, time.UTC)
+	q := newTestQueue(&now)
+
+	enqueued, err := q.Enqueue(&Job{PRNum: 42, TxHash: "ABC", Token: "TWT-8C2", Amount: 700})
+	if err != nil || !enqueued {
+		t.Fatalf("Enqueue() = %v, %v, want true, nil", enqueued, err)
+	}
+
+	enqueued, err = q.Enqueue(&Job{PRNum: 42, TxHash: "ABC", Token: "TWT-8C2", Amount: 700})
+	if err != nil || enqueued {
+		t.Fatalf("Enqueue() of a duplicate = %v, %v, want false, nil", enqueued, err)
+	}
+
+	var calls int
+
+	failing := func(*Job) (string, error) {
+		calls++
+
+		return "", errors.New("node is down")
+	}
+
+	wantDelays := []time.Duration{time.Minute, 90 * time.Second}
+	for i, delay := range wantDelays {
+		job, err := q.Run("42/ABC", failing)
+		if err != nil {
+			t.Fatalf("Run() error = %v", err)
+		}
+
+		if job.Status != StatusPending || job.NextAttemptAt.Sub(now) != delay {
+			t.Fatalf("attempt %d: Run() = %s, next in %s, want pending in %s",
+				i+1, job.Status, job.NextAttemptAt.Sub(now), delay)
+		}
+
+		if _, err = q.Run("42/ABC", failing); err != nil || calls != i+1 {
+			t.Fatalf("Run() of a job not due = %v, %d calls, want no attempt", err, calls)
+		}
+
+		now = job.NextAttemptAt
+	}
+
+	job, err := q.Run("42/ABC", failing)
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	if job.Status != StatusFailed || job.Attempts != 3 || job.LastError != "node is down" {
+		t.Errorf("Run() = %+v, want failed after 3 attempts", job)
+	}
+}
+
+func TestQueue_ExactlyOnce(t *testing.T) {
+	now := time.Date(2021, 10, 1, 0, 0, 0, 0, time.UTC)
+	q := newTestQueue(&now)
+
+	if _, err := q.Enqueue(&Job{PRNum: 1, TxHash: "A"}); err != nil {
+		t.Fatalf("Enqueue() error = %v", err)
+	}
+
+	var calls int
+
+	burn := func(*Job) (string, error) {
+		calls++
+
+		return "link", nil
+	}
+
+	for i := 0; i < 2; i++ {
+		job, err := q.Run("1/A", burn)
+		if err != nil {
+			t.Fatalf("Run() error = %v", err)
+		}
+
+		if job.Status != StatusDone || job.BurnLink != "link" {
+			t.Errorf("Run() = %+v, want done", job)
+		}
+	}
+
+	if calls != 1 {
+		t.Errorf("burn called %d times, want 1", calls)
+	}
+}
+
+func TestQueue_List_Interrupted(t *testing.T) {
+	now := time.Date(2021, 10, 1, 0, 0, 0, 0, time.UTC)
+	store := storage.NewMemoryStore()
+
+	err := store.Put(bucketJobs, "1/A", Job{ID: "1/A", Status: StatusInProgress, Attempts: 1})
+	if err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+
+	q := NewQueue(store, Options{MaxAttempts: 3})
+	q.now = func() time.Time { return now }
+
+	jobs, err := q.List()
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+
+	if len(jobs) != 1 || jobs[0].Status != StatusFailed || jobs[0].LastError != errInterrupted {
+		t.Errorf("List() = %+v, want an interrupted job failed", jobs)
+	}
+}
+
+func TestQueue_DueBatches(t *testing.T) {
+	now := time.Date(2021, 10, 1, 0, 0, 0, 0, time.UTC)
+	q := NewQueue(storage.NewMemoryStore(), Options{MaxAttempts: 3, BatchInterval: 24 * time.Hour, BatchThreshold: 2000})
+	q.now = func() time.Time { return now }
+
+	jobs := []*Job{
+		{ID: "1/A", Chain: "binance", Token: "TWT-8C2", Amount: 700, Status: StatusPending, CreatedAt: now.Add(-time.Hour)},
+		{ID: "2/B", Chain: "binance", Token: "TWT-8C2", Amount: 700, Status: StatusPending, CreatedAt: now},
+		{ID: "3/C", Chain: "binance", Token: "BNB", Amount: 5, Status: StatusPending, CreatedAt: now.Add(-25 * time.Hour)},
+		{ID: "4/D", Chain: "binance", Token: "BNB", Amount: 5, Status: StatusDone, CreatedAt: now.Add(-48 * time.Hour)},
+	}
+
+	batches := q.DueBatches(jobs)
+	if len(batches) != 1 || len(batches[0]) != 1 || batches[0][0].ID != "3/C" {
+		t.Fatalf("DueBatches() = %+v, want a batch of the expired BNB job", batches)
+	}
+
+	jobs[1].Amount = 1300
+
+	batches = q.DueBatches(jobs)
+	if len(batches) != 2 || len(batches[1]) != 2 {
+		t.Fatalf("DueBatches() = %+v, want the TWT batch over the threshold", batches)
+	}
+}
+
+func TestQueue_RunBatch(t *testing.T) {
+	now := time.Date(2021, 10, 1, 0, 0, 0, 0, time.UTC)
+	q := newTestQueue(&now)
+
+	for _, job := range []*Job{{PRNum: 1, TxHash: "A", Amount: 700}, {PRNum: 2, TxHash: "B", Amount: 800}} {
+		if _, err := q.Enqueue(job); err != nil {
+			t.Fatalf("Enqueue() error = %v", err)
+		}
+	}
+
+	var total float64
+
+	jobs, err := q.RunBatch([]string{"1/A", "2/B"}, func(jobs []*Job) (string, error) {
+		for _, job := range jobs {
+			total += job.Amount
+		}
+
+		return "link", nil
+	})
+	if err != nil {
+		t.Fatalf("RunBatch() error = %v", err)
+	}
+
+	if total != 1500 || len(jobs) != 2 {
+		t.Fatalf("RunBatch() burned %v of %d jobs, want 1500 of 2", total, len(jobs))
+	}
+
+	for _, job := range jobs {
+		if job.Status != StatusDone || job.BurnLink != "link" || job.BatchSize != 2 {
+			t.Errorf("RunBatch() job = %+v, want done with a shared link", job)
+		}
+	}
+}
@@ -0,0 +1,79 @@
+package events
+
+import (
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/trustwallet/assets-manager/internal/config"
+	"github.com/trustwallet/assets-manager/internal/services/consumer/github"
+)
+
+type (
+	// sweeps keeps the last runs of background sweeps of a repository.
+	sweeps struct {
+		mu      sync.Mutex
+		lastRun map[string]time.Time
+	}
+
+	// budgetOptions set how background sweeps are slowed down when the rate limit runs low.
+	budgetOptions struct {
+		SlowBelow    float64
+		SkipBelow    float64
+		SlowInterval time.Duration
+	}
+)
+
+func newSweeps() *sweeps {
+	return &sweeps{lastRun: make(map[string]time.Time)}
+}
+
+// shouldSweep reports if a background sweep of the repository runs now with the Github rate limit left.
+func (e Handler) shouldSweep(name string) bool {
+	now := time.Now()
+	rate, known := e.github.Rate(e.repo.Owner, e.repo.Name)
+
+	e.sweeps.mu.Lock()
+	defer e.sweeps.mu.Unlock()
+
+	run, reason := sweepDecision(rate, known, e.sweeps.lastRun[name], now, budgetOptions{
+		SlowBelow:    config.Default.Github.RateLimit.SlowBelow,
+		SkipBelow:    config.Default.Github.RateLimit.SkipBelow,
+		SlowInterval: config.Default.Github.RateLimit.SlowInterval,
+	})
+	if !run {
+		log.WithFields(log.Fields{
+			"sweep":     name,
+			"repo":      e.repo.FullName(),
+			"remaining": rate.Remaining,
+			"reset":     rate.Reset,
+		}).Debug(reason)
+
+		return false
+	}
+
+	e.sweeps.lastRun[name] = now
+
+	return true
+}
+
+// sweepDecision decides if a sweep runs with the rate limit left. A sweep is skipped until the limit resets
+// when less than SkipBelow of it is left, and runs at most every SlowInterval when less than SlowBelow is left.
+func sweepDecision(rate github.Rate, known bool, lastRun, now time.Time, options budgetOptions) (bool, string) {
+	if !known || rate.Limit == 0 || now.After(rate.Reset) {
+		return true, ""
+	}
+
+	left := 1 - rate.Used()
+
+	if left < options.SkipBelow {
+		return false, "Sweep skipped, Github rate limit is almost exhausted"
+	}
+
+	if left < options.SlowBelow && now.Sub(lastRun) < options.SlowInterval {
+		return false, "Sweep slowed down, Github rate limit is running low"
+	}
+
+	return true, ""
+}
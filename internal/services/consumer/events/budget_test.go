@@ -0,0 +1,60 @@
+package events
+
+import (
+	"testing"
+	"time"
+
+	"github.com/trustwallet/assets-manager/internal/services/consumer/github"
+)
+
+func Test_SweepDecision(t *testing.T) {
+	now := time.Date(2021, 10, 1, 12, 0, 0, 0, time.UTC)
+	reset := now.Add(30 * time.Minute)
+	options := budgetOptions{SlowBelow: 0.2, SkipBelow: 0.05, SlowInterval: 10 * time.Minute}
+
+	tests := []struct {
+		name    string
+		rate    github.Rate
+		known   bool
+		lastRun time.Time
+		want    bool
+	}{
+		{name: "Unknown rate", known: false, want: true},
+		{name: "Enough budget", rate: github.Rate{Limit: 5000, Remaining: 4000, Reset: reset}, known: true, want: true},
+		{
+			name:    "Low budget, ran recently",
+			rate:    github.Rate{Limit: 5000, Remaining: 500, Reset: reset},
+			known:   true,
+			lastRun: now.Add(-time.Minute),
+			want:    false,
+		},
+		{
+			name:    "Low budget, slow interval passed",
+			rate:    github.Rate{Limit: 5000, Remaining: 500, Reset: reset},
+			known:   true,
+			lastRun: now.Add(-11 * time.Minute),
+			want:    true,
+		},
+		{
+			name:    "Exhausted budget",
+			rate:    github.Rate{Limit: 5000, Remaining: 100, Reset: reset},
+			known:   true,
+			lastRun: now.Add(-time.Hour),
+			want:    false,
+		},
+		{
+			name:  "Exhausted budget reset",
+			rate:  github.Rate{Limit: 5000, Remaining: 0, Reset: now.Add(-time.Second)},
+			known: true,
+			want:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got, _ := sweepDecision(tt.rate, tt.known, tt.lastRun, now, options); got != tt.want {
+				t.Errorf("sweepDecision() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,215 @@
+package events
+
+import (
+	"context"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/trustwallet/assets-manager/internal/services/consumer/burns"
+	"github.com/trustwallet/assets-manager/internal/services/consumer/prstate"
+)
+
+// ProcessBurns retries due burn jobs and finishes jobs whose result hasn't been reported yet.
+func (e Handler) ProcessBurns(ctx context.Context, owner, repo string) error {
+	jobs, err := e.burns.List()
+	if err != nil {
+		return err
+	}
+
+	if e.burns.IsBatched() {
+		if err = e.burnBatches(jobs); err != nil {
+			return err
+		}
+	}
+
+	for _, job := range jobs {
+		if e.burns.IsDue(job) && !e.burns.IsBatched() {
+			if job, err = e.attemptBurn(job.ID); err != nil {
+				return err
+			}
+		}
+
+		if err = e.finishBurn(ctx, owner, repo, job); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// burnBatches burns due jobs of the same token in one transaction per batch.
+// Jobs are updated in place, so they are finished with the rest.
+func (e Handler) burnBatches(jobs []*burns.Job) error {
+	for _, batch := range e.burns.DueBatches(jobs) {
+		ids := make([]string, len(batch))
+		for i, job := range batch {
+			ids[i] = job.ID
+		}
+
+		attempted, err := e.burns.RunBatch(ids, e.burnBatch)
+		if err != nil {
+			return err
+		}
+
+		for _, job := range attempted {
+			for i := range jobs {
+				if jobs[i].ID == job.ID {
+					*jobs[i] = *job
+				}
+			}
+		}
+
+		log.WithFields(log.Fields{
+			"jobs":  len(attempted),
+			"token": batch[0].Token,
+		}).Info("Batch burn attempted")
+	}
+
+	return nil
+}
+
+// runBurn makes an attempt of a burn job right away and reports its result.
+func (e Handler) runBurn(ctx context.Context, owner, repo, id string) error {
+	job, err := e.attemptBurn(id)
+	if err != nil {
+		return err
+	}
+
+	return e.finishBurn(ctx, owner, repo, job)
+}
+
+func (e Handler) attemptBurn(id string) (*burns.Job, error) {
+	job, err := e.burns.Run(id, e.burn)
+	if err != nil {
+		return nil, err
+	}
+
+	if job.Status == burns.StatusPending && job.LastError != "" {
+		log.WithFields(log.Fields{
+			"pr_num":   job.PRNum,
+			"job":      job.ID,
+			"attempts": job.Attempts,
+			"next":     job.NextAttemptAt,
+			"error":    job.LastError,
+		}).Warn("Burn attempt failed, will be retried")
+	}
+
+	return job, nil
+}
+
+func (e Handler) burn(job *burns.Job) (string, error) {
+	return e.burnBatch([]*burns.Job{job})
+}
+
+// burnBatch burns a total amount of jobs of the same chain and token.
+func (e Handler) burnBatch(jobs []*burns.Job) (string, error) {
+	chain, err := e.chains.Get(jobs[0].Chain)
+	if err != nil {
+		return "", err
+	}
+
+	var amount float64
+	for _, job := range jobs {
+		amount += job.Amount
+	}
+
+	return chain.BurnToken(jobs[0].Token, amount)
+}
+
+// finishBurn reports a done or a failed job once.
+func (e Handler) finishBurn(ctx context.Context, owner, repo string, job *burns.Job) error {
+	if job.Status == burns.StatusDone {
+		return e.finishDoneBurn(job, func(text string) error {
+			return e.github.CreateCommentOnPullRequest(ctx, owner, repo, text, job.PRNum)
+		})
+	}
+
+	if job.Status != burns.StatusFailed || job.Notified {
+		return nil
+	}
+
+	log.WithFields(log.Fields{
+		"pr_num":   job.PRNum,
+		"job":      job.ID,
+		"attempts": job.Attempts,
+		"error":    job.LastError,
+	}).Error("Burn has permanently failed")
+
+	e.metrics.IncCounterBurnsFailed()
+
+	text := substituteDynamicContent(e.repo.Message.BurnFailed, &contentParams{
+		PaidAmount: job.Amount,
+		PaidSymbol: strings.Split(job.Token, "-")[0],
+		Moderators: e.repo.UserAccess.Moderators,
+		Burn:       job,
+	})
+
+	if err := e.github.CreateCommentOnPullRequest(ctx, owner, repo, text, job.PRNum); err != nil {
+		return err
+	}
+
+	return e.burns.SetNotified(job.ID)
+}
+
+// finishDoneBurn comments a burn of a done job once whatever the state of the pull request is,
+// since most pull requests are merged before a batched burn. A paid pull request moves to the burned state.
+func (e Handler) finishDoneBurn(job *burns.Job, comment func(text string) error) error {
+	if !job.Notified {
+		reported, err := e.isBurnReported(job)
+		if err != nil {
+			return err
+		}
+
+		// Nothing is burned for a native coin, so there is no burn to report.
+		if !reported && job.BurnLink != "" {
+			if err = comment(e.burnedText(job)); err != nil {
+				return err
+			}
+		}
+
+		if err = e.burns.SetNotified(job.ID); err != nil {
+			return err
+		}
+
+		job.Notified = true
+	}
+
+	_, err := e.transition(job.PRNum, prstate.StateBurned, "burn", func() (string, error) {
+		return job.BurnLink, nil
+	})
+
+	return err
+}
+
+// isBurnReported reports if a burn has been commented on the transition to the burned state,
+// as it was done before jobs recorded it.
+func (e Handler) isBurnReported(job *burns.Job) (bool, error) {
+	st, err := e.states.Get(job.PRNum)
+	if err != nil {
+		return false, err
+	}
+
+	for _, t := range st.History {
+		if t.To == prstate.StateBurned && t.Details == job.BurnLink {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// burnedText returns a comment text of a done burn job, batched burns report the batch size.
+func (e Handler) burnedText(job *burns.Job) string {
+	message := e.repo.Message.Burned
+	if job.BatchSize > 1 {
+		message = e.repo.Message.BurnedBatch
+	}
+
+	return substituteDynamicContent(message, &contentParams{
+		PaidAmount:       job.Amount,
+		PaidSymbol:       strings.Split(job.Token, "-")[0],
+		BurnExplorerLink: job.BurnLink,
+		Burn:             job,
+	})
+}
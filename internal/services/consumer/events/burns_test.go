@@ -0,0 +1,104 @@
+package events
+
+import (
+	"testing"
+	"time"
+
+	"github.com/trustwallet/assets-manager/internal/config"
+	"github.com/trustwallet/assets-manager/internal/services/consumer/burns"
+	"github.com/trustwallet/assets-manager/internal/services/consumer/prstate"
+	"github.com/trustwallet/assets-manager/internal/storage"
+)
+
+func Test_BurnedText(t *testing.T) {
+	e := Handler{repo: config.Repo{Message: config.Message{
+		Burned:      "$PAID_AMOUNT $PAID_SYMBOL have been [burned]($BURN_EXPLORER_LINK).",
+		BurnedBatch: "$PAID_AMOUNT $PAID_SYMBOL have been [burned]($BURN_EXPLORER_LINK) in a batch of $BURN_BATCH_SIZE PR fees.",
+	}}}
+
+	tests := []struct {
+		name string
+		job  *burns.Job
+		want string
+	}{
+		{
+			name: "Single burn",
+			job:  &burns.Job{Token: "TWT-8C2", Amount: 500, BurnLink: "https://explorer/tx/1", BatchSize: 1},
+			want: "500.00 TWT have been [burned](https://explorer/tx/1).",
+		},
+		{
+			name: "Batched burn",
+			job:  &burns.Job{Token: "TWT-8C2", Amount: 500, BurnLink: "https://explorer/tx/2", BatchSize: 3},
+			want: "500.00 TWT have been [burned](https://explorer/tx/2) in a batch of 3 PR fees.",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := e.burnedText(tt.job); got != tt.want {
+				t.Errorf("burnedText() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_FinishDoneBurn_MergedBeforeBatch(t *testing.T) {
+	store := storage.NewMemoryStore()
+	e := Handler{
+		repo: config.Repo{Message: config.Message{
+			BurnedBatch: "$PAID_AMOUNT $PAID_SYMBOL have been [burned]($BURN_EXPLORER_LINK) in a batch.",
+		}},
+		states: prstate.NewMachine(store),
+		burns:  burns.NewQueue(store, burns.Options{MaxAttempts: 3, BatchInterval: 24 * time.Hour}),
+	}
+
+	for _, to := range []prstate.State{prstate.StateOpened, prstate.StatePaid, prstate.StateMerged} {
+		if _, err := e.states.Transition(1, to, "test", ""); err != nil {
+			t.Fatalf("Transition(%s) error = %v", to, err)
+		}
+	}
+
+	ids := make([]string, 0)
+
+	for i, hash := range []string{"tx1", "tx2"} {
+		job := &burns.Job{PRNum: i + 1, Chain: "bsc", Token: "TWT-8C2", Amount: 500, TxHash: hash}
+		if _, err := e.burns.Enqueue(job); err != nil {
+			t.Fatalf("Enqueue() error = %v", err)
+		}
+
+		ids = append(ids, job.ID)
+	}
+
+	jobs, err := e.burns.RunBatch(ids, func([]*burns.Job) (string, error) { return "https://explorer/tx/burn", nil })
+	if err != nil || len(jobs) != 2 {
+		t.Fatalf("RunBatch() = %d jobs, %v", len(jobs), err)
+	}
+
+	comments := make([]string, 0)
+	comment := func(text string) error {
+		comments = append(comments, text)
+
+		return nil
+	}
+
+	for i := 0; i < 2; i++ {
+		job, err := e.burns.Get(ids[0])
+		if err != nil {
+			t.Fatalf("Get() error = %v", err)
+		}
+
+		if err = e.finishDoneBurn(job, comment); err != nil {
+			t.Fatalf("finishDoneBurn() error = %v", err)
+		}
+	}
+
+	want := "500.00 TWT have been [burned](https://explorer/tx/burn) in a batch."
+	if len(comments) != 1 || comments[0] != want {
+		t.Errorf("comments = %v, want one %q", comments, want)
+	}
+
+	st, err := e.states.Get(1)
+	if err != nil || st.State != prstate.StateMerged {
+		t.Errorf("state = %v, %v, want %s", st, err, prstate.StateMerged)
+	}
+}
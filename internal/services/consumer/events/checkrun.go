@@ -0,0 +1,219 @@
+package events
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	gh "github.com/google/go-github/v38/github"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/trustwallet/assets-manager/internal/config"
+	"github.com/trustwallet/assets-manager/internal/findings"
+)
+
+const summaryTruncated = "\n\n_The summary is truncated, see the summary comment of the PR._"
+
+const (
+	conclusionSuccess = "success"
+	conclusionFailure = "failure"
+
+	checkRunCompleted = "completed"
+	checkRunRerun     = "rerun"
+
+	// Github accepts up to 50 annotations per request and a summary up to 65535 characters.
+	checkRunAnnotationsMax = 50
+	checkRunSummaryMax     = 65535
+)
+
+// checkReport collects findings of the PR checks. A nil report ignores them.
+type checkReport struct {
+	found []findings.Finding
+}
+
+func (r *checkReport) add(found ...findings.Finding) {
+	if r == nil {
+		return
+	}
+
+	r.found = append(r.found, found...)
+}
+
+func (r *checkReport) conclusion() string {
+	if findings.HasErrors(r.found) {
+		return conclusionFailure
+	}
+
+	return conclusionSuccess
+}
+
+// fieldLine returns a line number of a field of a JSON file, 1 if not found.
+func fieldLine(content []byte, field string) int {
+	if field == "" {
+		return 1
+	}
+
+	key := regexp.MustCompile(`"` + regexp.QuoteMeta(field) + `"\s*:`)
+
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for line := 1; scanner.Scan(); line++ {
+		if key.Match(scanner.Bytes()) {
+			return line
+		}
+	}
+
+	return 1
+}
+
+// publishCheckRun reports the PR checks as a completed check run on the head commit.
+// Annotations over the request limit are added by updates of the check run.
+func (e Handler) publishCheckRun(
+	ctx context.Context, owner, repo string, pr *gh.PullRequest, summary string, report *checkReport,
+) error {
+	if !config.Default.Github.CheckRun.Enabled {
+		return nil
+	}
+
+	name := config.Default.Github.CheckRun.Name
+	conclusion := report.conclusion()
+	title := "Checks passed"
+	if conclusion == conclusionFailure {
+		title = fmt.Sprintf("Checks failed: %d problem(s)", len(report.found))
+	}
+
+	summary = truncateSummary(summary, checkRunSummaryMax, summaryTruncated)
+
+	annotations := findings.Annotations(report.found)
+
+	batches := make([][]*gh.CheckRunAnnotation, 0)
+	for i := 0; i < len(annotations); i += checkRunAnnotationsMax {
+		end := i + checkRunAnnotationsMax
+		if end > len(annotations) {
+			end = len(annotations)
+		}
+
+		batches = append(batches, annotations[i:end])
+	}
+
+	output := func(i int) *gh.CheckRunOutput {
+		out := &gh.CheckRunOutput{Title: gh.String(title), Summary: gh.String(summary)}
+		if i < len(batches) {
+			out.Annotations = batches[i]
+		}
+
+		return out
+	}
+
+	id, err := e.github.CreateCheckRun(ctx, owner, repo, gh.CreateCheckRunOptions{
+		Name:        name,
+		HeadSHA:     pr.GetHead().GetSHA(),
+		ExternalID:  gh.String(strconv.Itoa(pr.GetNumber())),
+		Status:      gh.String(checkRunCompleted),
+		Conclusion:  gh.String(conclusion),
+		CompletedAt: &gh.Timestamp{Time: time.Now()},
+		Output:      output(0),
+		Actions: []*gh.CheckRunAction{
+			{Label: "Re-run", Description: "Run the checks again", Identifier: checkRunRerun},
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	for i := 1; i < len(batches); i++ {
+		err = e.github.UpdateCheckRun(ctx, owner, repo, id, gh.UpdateCheckRunOptions{Name: name, Output: output(i)})
+		if err != nil {
+			return err
+		}
+	}
+
+	log.WithFields(log.Fields{
+		"pr_num":      pr.GetNumber(),
+		"conclusion":  conclusion,
+		"annotations": len(annotations),
+	}).Debug("Check run published")
+
+	return nil
+}
+
+// HandleCheckRunRerequested runs the PR checks again when the check run of the bot is re-run
+// from the Github UI or by its re-run action.
+func (e Handler) HandleCheckRunRerequested(ctx context.Context, event *gh.CheckRunEvent) error {
+	run := event.GetCheckRun()
+	if run.GetApp().GetID() != config.Default.Github.AppID || run.GetName() != config.Default.Github.CheckRun.Name {
+		return nil
+	}
+
+	if event.GetAction() == "requested_action" {
+		if action := event.GetRequestedAction(); action == nil || action.Identifier != checkRunRerun {
+			return nil
+		}
+	}
+
+	// Check runs of PRs from forks have no pull requests linked, the number is kept in the external ID.
+	prNum, err := strconv.Atoi(run.GetExternalID())
+	if err != nil {
+		return fmt.Errorf("invalid check run external ID %q: %w", run.GetExternalID(), err)
+	}
+
+	owner := event.GetRepo().GetOwner().GetLogin()
+	repo := event.GetRepo().GetName()
+
+	log.WithFields(log.Fields{
+		"pr_num": prNum,
+		"sender": event.GetSender().GetLogin(),
+	}).Debug("Check run rerequested")
+
+	pr, err := e.github.GetPullRequest(ctx, owner, repo, prNum)
+	if err != nil {
+		return err
+	}
+
+	if pr.GetState() != "open" {
+		return nil
+	}
+
+	return e.checkPullRequestChanges(ctx, owner, repo, pr)
+}
+
+// truncateSummary cuts a summary longer than max bytes at the last line which fits, closes code blocks
+// and collapsed sections left open and appends a note that the summary is truncated.
+func truncateSummary(summary string, max int, note string) string {
+	if len(summary) <= max {
+		return summary
+	}
+
+	// Room for the note and the closing tags of every open block.
+	closers := strings.Count(summary, "<details>")*len("\n</details>") + len("\n```")
+	cut := max - len(note) - closers
+
+	if cut < 0 {
+		cut = 0
+	}
+
+	if i := strings.LastIndexByte(summary[:cut], '\n'); i >= 0 {
+		cut = i
+	}
+
+	for cut > 0 && !utf8.RuneStart(summary[cut]) {
+		cut--
+	}
+
+	text := summary[:cut]
+
+	if strings.Count(text, "```")%2 == 1 {
+		text += "\n```"
+	}
+
+	for open := strings.Count(text, "<details>") - strings.Count(text, "</details>"); open > 0; open-- {
+		text += "\n</details>"
+	}
+
+	return text + note
+}
@@ -0,0 +1,80 @@
+package events
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/trustwallet/assets-manager/internal/findings"
+)
+
+func Test_FieldLine(t *testing.T) {
+	content := []byte(`{
+    "name": "Token",
+    "symbol": "TKN",
+    "decimals": 18,
+    "explorer" : "https://etherscan.io/token/0x0",
+    "links": [
+        {"name": "github", "url": "https://github.com/token"}
+    ]
+}`)
+
+	tests := []struct {
+		field string
+		want  int
+	}{
+		{field: "name", want: 2},
+		{field: "decimals", want: 4},
+		{field: "explorer", want: 5},
+		{field: "links", want: 6},
+		{field: "tags", want: 1},
+		{field: "", want: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.field, func(t *testing.T) {
+			if got := fieldLine(content, tt.field); got != tt.want {
+				t.Errorf("fieldLine() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_CheckReport(t *testing.T) {
+	report := &checkReport{}
+	if got := report.conclusion(); got != conclusionSuccess {
+		t.Errorf("conclusion() = %s, want %s", got, conclusionSuccess)
+	}
+
+	report.add(findings.Finding{Rule: findings.RuleAssetHolders, Severity: findings.SeverityWarning})
+	if got := report.conclusion(); got != conclusionSuccess {
+		t.Errorf("conclusion() with a warning = %s, want %s", got, conclusionSuccess)
+	}
+
+	report.add(findings.Finding{Rule: findings.RulePRFiles, Severity: findings.SeverityError})
+	if got := report.conclusion(); got != conclusionFailure {
+		t.Errorf("conclusion() with an error = %s, want %s", got, conclusionFailure)
+	}
+
+	var nilReport *checkReport
+	nilReport.add(findings.Finding{Rule: findings.RulePRFiles})
+}
+
+func Test_TruncateSummary(t *testing.T) {
+	summary := "**Checks**\n<details><summary>Files</summary>\n\n" + strings.Repeat("❌ Invalid logo\n", 20) + "</details>"
+
+	if got := truncateSummary(summary, len(summary), summaryTruncated); got != summary {
+		t.Errorf("truncateSummary() of a short summary = %q", got)
+	}
+
+	max := len(summaryTruncated) + 150
+	got := truncateSummary(summary, max, summaryTruncated)
+
+	if len(got) > max || !utf8.ValidString(got) {
+		t.Fatalf("truncateSummary() = %q, want valid UTF-8 up to %d bytes", got, max)
+	}
+
+	if !strings.HasSuffix(got, "❌ Invalid logo\n</details>"+summaryTruncated) {
+		t.Errorf("truncateSummary() = %q, want whole lines with closed blocks and the note", got)
+	}
+}
@@ -0,0 +1,326 @@
+package events
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	gh "github.com/google/go-github/v38/github"
+
+	"github.com/trustwallet/assets-go-libs/path"
+	"github.com/trustwallet/assets-go-libs/validation/tokenlist"
+	"github.com/trustwallet/assets-manager/internal/findings"
+	"github.com/trustwallet/assets-manager/internal/services/consumer/snapshot"
+	"github.com/trustwallet/go-primitives/types"
+)
+
+// nolint:gochecknoglobals // compiled once
+var regexChainListFile = regexp.MustCompile(`^blockchains/([^/]+)/(allowlist|denylist|tokenlist)\.json$`)
+
+func allowlistPath(chain string) string {
+	return fmt.Sprintf("blockchains/%s/allowlist.json", chain)
+}
+
+func denylistPath(chain string) string {
+	return fmt.Sprintf("blockchains/%s/denylist.json", chain)
+}
+
+func assetsPath(chain string) string {
+	return fmt.Sprintf("blockchains/%s/assets", chain)
+}
+
+// getConsistencyCheckSummary checks tokens of a PR against the base branch: duplicates, clashes with
+// well-known tokens and the denylist. Lists of chains changed by the PR are checked for consistency.
+func (e Handler) getConsistencyCheckSummary(
+	ctx context.Context, files []*gh.CommitFile, head *prHead, report *checkReport,
+) string {
+	tokens := prTokens(files)
+
+	chains := make([]string, 0)
+	seen := make(map[string]bool)
+
+	for _, f := range files {
+		if m := regexChainListFile.FindStringSubmatch(f.GetFilename()); m != nil && !seen[m[1]] {
+			seen[m[1]] = true
+			chains = append(chains, m[1])
+		}
+	}
+
+	if len(tokens) == 0 && len(chains) == 0 {
+		return ""
+	}
+
+	ids := make([]string, 0, len(tokens))
+	for id := range tokens {
+		ids = append(ids, id)
+	}
+
+	sort.Strings(ids)
+	sort.Strings(chains)
+
+	found := make([]findings.Finding, 0)
+
+	for _, id := range ids {
+		// Unknown types are reported by the token check.
+		chain, err := types.GetChainFromAssetType(tokens[id])
+		if err != nil {
+			continue
+		}
+
+		found = append(found, findings.Override(checkTokenConsistency(ctx, head, chain.Handle, id),
+			findings.Overrides(chain.Handle))...)
+	}
+
+	for _, chain := range chains {
+		found = append(found, findings.Override(checkChainLists(ctx, head.tree, chain), findings.Overrides(chain))...)
+	}
+
+	report.add(found...)
+
+	text := "**Repository consistency check**\n"
+	if len(found) == 0 {
+		return text + "✅ Check OK"
+	}
+
+	return text + findings.Markdown(found)
+}
+
+// checkTokenConsistency checks a token of a PR against the base branch.
+func checkTokenConsistency(ctx context.Context, head *prHead, chain, id string) []findings.Finding {
+	found := make([]findings.Finding, 0)
+
+	names, err := readDir(ctx, head.base, assetsPath(chain))
+	if err != nil {
+		return append(found, baseFailure(findings.RuleAssetDuplicate, err))
+	}
+
+	headNames, err := readDir(ctx, head.tree, assetsPath(chain))
+	if err != nil {
+		return append(found, baseFailure(findings.RuleAssetDuplicate, err))
+	}
+
+	// A folder renamed by the PR, e.g. to a checksum address, is not a duplicate.
+	inHead := make(map[string]bool, len(headNames))
+	for _, name := range headNames {
+		inHead[name] = true
+	}
+
+	for _, name := range names {
+		if name != id && strings.EqualFold(name, id) && inHead[name] {
+			found = append(found, findings.Finding{
+				Rule:     findings.RuleAssetDuplicate,
+				Severity: findings.SeverityError,
+				Message:  fmt.Sprintf("Token `%s` already exists as `%s` in the base branch.", id, name),
+				Fix:      fmt.Sprintf("use the existing ID `%s`", name),
+			})
+		}
+	}
+
+	denylist, _, err := readIDList(ctx, head.base, denylistPath(chain))
+	if err != nil {
+		return append(found, baseFailure(findings.RuleAssetDenylisted, err))
+	}
+
+	if _, ok := denylist[strings.ToLower(id)]; ok {
+		found = append(found, findings.Finding{
+			Rule:     findings.RuleAssetDenylisted,
+			Severity: findings.SeverityError,
+			Message:  fmt.Sprintf("Token `%s` is in the denylist of %s.", id, chain),
+		})
+	}
+
+	// A missing or invalid info.json is reported by the token check.
+	info, _, err := readAssetInfo(ctx, head.tree, path.GetAssetInfoPath(chain, id))
+	if err != nil {
+		return found
+	}
+
+	wellKnown, _, err := readTokenlist(ctx, head.base, chain)
+	if err != nil {
+		return append(found, baseFailure(findings.RuleAssetSymbolClash, err))
+	}
+
+	for _, token := range wellKnown.Tokens {
+		if strings.EqualFold(token.Address, id) {
+			continue
+		}
+
+		var clash string
+
+		switch {
+		case info.Symbol != nil && strings.EqualFold(*info.Symbol, token.Symbol):
+			clash = fmt.Sprintf("Symbol `%s`", token.Symbol)
+		case info.Name != nil && strings.EqualFold(*info.Name, token.Name):
+			clash = fmt.Sprintf("Name `%s`", token.Name)
+		default:
+			continue
+		}
+
+		found = append(found, findings.Finding{
+			Rule:     findings.RuleAssetSymbolClash,
+			Severity: findings.SeverityWarning,
+			Message: fmt.Sprintf("%s is used by the well-known token %s `%s`, check it is not an impersonation.",
+				clash, token.Name, token.Address),
+		})
+	}
+
+	return found
+}
+
+// checkChainLists checks tokenlist.json, allowlist.json and denylist.json of a chain are consistent
+// with each other and the assets of the PR head.
+func checkChainLists(ctx context.Context, tree snapshot.Tree, chain string) []findings.Finding {
+	found := make([]findings.Finding, 0)
+
+	assets, err := readDir(ctx, tree, assetsPath(chain))
+	if err != nil {
+		return append(found, baseFailure(findings.RuleTokenlist, err))
+	}
+
+	exists := make(map[string]bool, len(assets))
+	for _, name := range assets {
+		exists[name] = true
+	}
+
+	allowlist, allowContent, err := readIDList(ctx, tree, allowlistPath(chain))
+	if err != nil {
+		return append(found, baseFailure(findings.RuleAllowlist, err))
+	}
+
+	denylist, _, err := readIDList(ctx, tree, denylistPath(chain))
+	if err != nil {
+		return append(found, baseFailure(findings.RuleAllowlist, err))
+	}
+
+	tokens, tokensContent, err := readTokenlist(ctx, tree, chain)
+	if err != nil {
+		return append(found, baseFailure(findings.RuleTokenlist, err))
+	}
+
+	tokenlistPath := path.GetTokenListPath(chain, path.TokenlistDefault)
+
+	for _, token := range tokens.Tokens {
+		if token.Type == types.Coin {
+			continue
+		}
+
+		line := textLine(tokensContent, token.Address)
+
+		if !exists[token.Address] {
+			found = append(found, findings.Finding{
+				Rule:     findings.RuleTokenlist,
+				Severity: findings.SeverityError,
+				Message:  fmt.Sprintf("Token `%s` of tokenlist.json has no asset folder.", token.Address),
+			}.At(tokenlistPath, line))
+		}
+
+		if _, ok := denylist[strings.ToLower(token.Address)]; ok {
+			found = append(found, findings.Finding{
+				Rule:     findings.RuleTokenlist,
+				Severity: findings.SeverityError,
+				Message:  fmt.Sprintf("Token `%s` of tokenlist.json is in denylist.json.", token.Address),
+			}.At(tokenlistPath, line))
+		}
+	}
+
+	for key, id := range allowlist {
+		if _, ok := denylist[key]; ok {
+			found = append(found, findings.Finding{
+				Rule:     findings.RuleAllowlist,
+				Severity: findings.SeverityError,
+				Message:  fmt.Sprintf("Token `%s` is both in allowlist.json and denylist.json.", id),
+			}.At(allowlistPath(chain), textLine(allowContent, id)))
+		}
+	}
+
+	sort.Slice(found, func(i, j int) bool {
+		return found[i].Path < found[j].Path || (found[i].Path == found[j].Path && found[i].Line < found[j].Line)
+	})
+
+	return found
+}
+
+// baseFailure is a warning of a check which could not read files it needs.
+func baseFailure(rule string, err error) findings.Finding {
+	return findings.Finding{
+		Rule:     rule,
+		Severity: findings.SeverityWarning,
+		Message:  fmt.Sprintf("Failed to check: %v", err),
+	}
+}
+
+// readDir returns names of a directory, none if it does not exist.
+func readDir(ctx context.Context, tree snapshot.Tree, name string) ([]string, error) {
+	names, err := tree.ReadDir(ctx, name)
+	if errors.Is(err, snapshot.ErrNotFound) {
+		return nil, nil
+	}
+
+	return names, err
+}
+
+// readIDList returns token IDs of an allowlist or a denylist by their lowercase, empty if there is no list.
+func readIDList(ctx context.Context, tree snapshot.Tree, name string) (map[string]string, []byte, error) {
+	ids := make(map[string]string)
+
+	content, err := tree.ReadFile(ctx, name)
+	if errors.Is(err, snapshot.ErrNotFound) {
+		return ids, nil, nil
+	}
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var list []string
+	if err = json.Unmarshal(content, &list); err != nil {
+		return nil, nil, fmt.Errorf("failed to parse %s: %w", name, err)
+	}
+
+	for _, id := range list {
+		ids[strings.ToLower(id)] = id
+	}
+
+	return ids, content, nil
+}
+
+// readTokenlist returns tokenlist.json of a chain, empty if there is none.
+func readTokenlist(ctx context.Context, tree snapshot.Tree, chain string) (*tokenlist.Model, []byte, error) {
+	name := path.GetTokenListPath(chain, path.TokenlistDefault)
+
+	content, err := tree.ReadFile(ctx, name)
+	if errors.Is(err, snapshot.ErrNotFound) {
+		return &tokenlist.Model{}, nil, nil
+	}
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	model := &tokenlist.Model{}
+	if err = json.Unmarshal(content, model); err != nil {
+		return nil, nil, fmt.Errorf("failed to parse %s: %w", name, err)
+	}
+
+	return model, content, nil
+}
+
+// textLine returns a number of the first line containing a quoted text, 1 if not found.
+func textLine(content []byte, text string) int {
+	quoted := []byte(`"` + text + `"`)
+
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for line := 1; scanner.Scan(); line++ {
+		if bytes.Contains(scanner.Bytes(), quoted) {
+			return line
+		}
+	}
+
+	return 1
+}
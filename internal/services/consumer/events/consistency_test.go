@@ -0,0 +1,122 @@
+package events
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	gh "github.com/google/go-github/v38/github"
+
+	"github.com/trustwallet/assets-manager/internal/findings"
+	"github.com/trustwallet/assets-manager/internal/services/consumer/snapshot"
+)
+
+func Test_getConsistencyCheckSummary(t *testing.T) {
+	const (
+		id       = "0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48"
+		logoPath = "blockchains/ethereum/assets/" + id + "/logo.png"
+		infoPath = "blockchains/ethereum/assets/" + id + "/info.json"
+		usdt     = "0xdAC17F958D2ee523a2206206994597C13D831ec7"
+	)
+
+	base := map[string][]byte{
+		"blockchains/ethereum/assets/" + usdt + "/info.json": []byte(`{}`),
+		"blockchains/ethereum/tokenlist.json": []byte(`{"tokens": [
+			{"asset": "c60_t` + usdt + `", "type": "ERC20", "address": "` + usdt + `", "name": "Tether", "symbol": "USDT"}
+		]}`),
+	}
+
+	tests := []struct {
+		name      string
+		files     []string
+		base      map[string][]byte
+		head      map[string][]byte
+		wantRules []string
+	}{
+		{
+			name:  "Valid",
+			files: []string{logoPath},
+			base:  base,
+			head:  map[string][]byte{infoPath: []byte(`{"name": "USD Coin", "symbol": "USDC"}`)},
+		},
+		{
+			name:  "Duplicate",
+			files: []string{logoPath},
+			base: map[string][]byte{
+				"blockchains/ethereum/assets/" + strings.ToLower(id) + "/info.json": []byte(`{}`),
+			},
+			head: map[string][]byte{
+				"blockchains/ethereum/assets/" + strings.ToLower(id) + "/info.json": []byte(`{}`),
+				infoPath: []byte(`{"symbol": "USDC"}`),
+			},
+			wantRules: []string{findings.RuleAssetDuplicate},
+		},
+		{
+			name:  "Renamed to checksum address",
+			files: []string{logoPath, "blockchains/ethereum/assets/" + strings.ToLower(id) + "/logo.png"},
+			base: map[string][]byte{
+				"blockchains/ethereum/assets/" + strings.ToLower(id) + "/info.json": []byte(`{}`),
+			},
+			head: map[string][]byte{infoPath: []byte(`{"symbol": "USDC"}`)},
+		},
+		{
+			name:      "Denylisted",
+			files:     []string{logoPath},
+			base:      map[string][]byte{"blockchains/ethereum/denylist.json": []byte(`["` + strings.ToLower(id) + `"]`)},
+			head:      map[string][]byte{infoPath: []byte(`{"symbol": "USDC"}`)},
+			wantRules: []string{findings.RuleAssetDenylisted},
+		},
+		{
+			name:      "Symbol clash",
+			files:     []string{logoPath},
+			base:      base,
+			head:      map[string][]byte{infoPath: []byte(`{"name": "Tether USD", "symbol": "usdt"}`)},
+			wantRules: []string{findings.RuleAssetSymbolClash},
+		},
+		{
+			name:  "Lists",
+			files: []string{"blockchains/ethereum/tokenlist.json", "blockchains/ethereum/allowlist.json"},
+			base:  base,
+			head: map[string][]byte{
+				"blockchains/ethereum/assets/" + usdt + "/info.json": []byte(`{}`),
+				"blockchains/ethereum/tokenlist.json": []byte(`{"tokens": [
+					{"type": "ERC20", "address": "` + usdt + `"},
+					{"type": "ERC20", "address": "` + id + `"}
+				]}`),
+				"blockchains/ethereum/allowlist.json": []byte(`["` + usdt + `"]`),
+				"blockchains/ethereum/denylist.json":  []byte(`["` + usdt + `"]`),
+			},
+			wantRules: []string{findings.RuleAllowlist, findings.RuleTokenlist, findings.RuleTokenlist},
+		},
+		{
+			name:  "No tokens or lists",
+			files: []string{"README.md"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			files := make([]*gh.CommitFile, 0)
+			for _, name := range tt.files {
+				files = append(files, &gh.CommitFile{Filename: gh.String(name)})
+			}
+
+			head := &prHead{
+				tree: snapshot.NewDir(writeFixture(t, tt.head)),
+				base: snapshot.NewDir(writeFixture(t, tt.base)),
+			}
+			report := &checkReport{}
+
+			summary := Handler{}.getConsistencyCheckSummary(context.Background(), files, head, report)
+
+			rules := make([]string, 0)
+			for _, f := range report.found {
+				rules = append(rules, f.Rule)
+			}
+
+			if strings.Join(rules, ",") != strings.Join(tt.wantRules, ",") {
+				t.Errorf("getConsistencyCheckSummary() rules = %v, want %v\n%s", rules, tt.wantRules, summary)
+			}
+		})
+	}
+}
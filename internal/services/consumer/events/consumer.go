@@ -5,34 +5,125 @@ import (
 	"encoding/json"
 	"fmt"
 
+	log "github.com/sirupsen/logrus"
+
+	"github.com/trustwallet/assets-manager/internal/config"
 	"github.com/trustwallet/assets-manager/internal/queue"
 	"github.com/trustwallet/go-libs/mq"
 )
 
-func GetEventConsumer(ctx context.Context, eh *Handler) func(mq.Message) error {
+// GetEventConsumer returns a consumer of Github events. A failed event is scheduled for a retry after a delay
+// until max attempts are reached, then it is moved to the dead-letter queue with the error.
+func GetEventConsumer(
+	ctx context.Context, handlers Handlers, retries *queue.RetryQueue, deadLetterQueue mq.Queue,
+) func(mq.Message) error {
 	return func(message mq.Message) error {
 		var event queue.GithubEventMessage
 
 		err := json.Unmarshal(message, &event)
 		if err != nil {
-			return fmt.Errorf("failed to unmarshal Github event: %w", err)
+			return publishDeadLetter(deadLetterQueue, message, 1, fmt.Errorf("failed to unmarshal Github event: %w", err))
 		}
 
-		switch event.Type {
-		case queue.PullRequestOpened:
-			err = eh.HandlePullRequestOpened(ctx, event.PullRequest)
-		case queue.PullRequestSynchronize:
-			err = eh.HandlePullRequestChangesPushed(ctx, event.PullRequest)
-		case queue.IssueCommentCreated:
-			err = eh.HandleIssueCommentCreated(ctx, event.IssueComment)
-		case queue.PullRequestReviewCommentOpened:
-			err = eh.HandlePullRequestReviewCommentCreated(ctx, event.PullRequestReviewComment)
+		err = handleEvent(ctx, handlers, &event)
+		if err == nil {
+			return nil
 		}
 
-		if err != nil {
-			return fmt.Errorf("failed to handle Github event: %w", err)
+		event.Attempts++
+
+		if event.Attempts >= config.Default.Consumer.MaxAttempts {
+			return publishDeadLetter(deadLetterQueue, message, event.Attempts, err)
+		}
+
+		body, marshalErr := json.Marshal(event)
+		if marshalErr != nil {
+			return fmt.Errorf("failed to marshal Github event: %w", marshalErr)
+		}
+
+		retry, scheduleErr := retries.Schedule(body, config.Default.Consumer.RetryDelay, err)
+		if scheduleErr != nil {
+			return fmt.Errorf("failed to schedule Github event for retry: %w", scheduleErr)
 		}
 
+		log.WithError(err).WithFields(log.Fields{
+			"type":            event.Type,
+			"attempts":        event.Attempts,
+			"next_attempt_at": retry.NextAttemptAt,
+		}).Warn("Github event failed, will be retried")
+
 		return nil
 	}
 }
+
+func handleEvent(ctx context.Context, handlers Handlers, event *queue.GithubEventMessage) error {
+	repo, installation := event.Source()
+
+	eh, ok := handlers.Get(repo.GetOwner().GetLogin(), repo.GetName())
+	if !ok {
+		log.WithFields(log.Fields{
+			"type": event.Type,
+			"repo": repo.GetFullName(),
+		}).Warn("Github event of a repository not served ignored")
+
+		return nil
+	}
+
+	eh.github.SetInstallation(repo.GetOwner().GetLogin(), repo.GetName(), installation.GetID())
+
+	var err error
+
+	switch event.Type {
+	case queue.PullRequestOpened:
+		err = eh.HandlePullRequestOpened(ctx, event.PullRequest)
+	case queue.PullRequestReopened:
+		err = eh.HandlePullRequestReopened(ctx, event.PullRequest)
+	case queue.PullRequestSynchronize:
+		err = eh.HandlePullRequestChangesPushed(ctx, event.PullRequest)
+	case queue.PullRequestClosed:
+		err = eh.HandlePullRequestClosed(ctx, event.PullRequest)
+	case queue.PullRequestLabeled:
+		err = eh.HandlePullRequestLabeled(ctx, event.PullRequest)
+	case queue.PullRequestUnlabeled:
+		err = eh.HandlePullRequestUnlabeled(ctx, event.PullRequest)
+	case queue.PullRequestReviewSubmitted:
+		err = eh.HandlePullRequestReviewSubmitted(ctx, event.PullRequestReview)
+	case queue.IssueCommentCreated:
+		err = eh.HandleIssueCommentCreated(ctx, event.IssueComment)
+	case queue.PullRequestReviewCommentCreated, queue.PullRequestReviewCommentOpened:
+		err = eh.HandlePullRequestReviewCommentCreated(ctx, event.PullRequestReviewComment)
+	case queue.CheckRunRerequested:
+		err = eh.HandleCheckRunRerequested(ctx, event.CheckRun)
+	}
+
+	if err != nil {
+		return fmt.Errorf("failed to handle Github event: %w", err)
+	}
+
+	return nil
+}
+
+// publishDeadLetter moves a failed message to the dead-letter queue. If publishing fails,
+// the error is returned, so the message is requeued by the consumer.
+func publishDeadLetter(deadLetterQueue mq.Queue, message []byte, attempts int, reason error) error {
+	letter, err := queue.NewDeadLetter(message, attempts, reason)
+	if err != nil {
+		return err
+	}
+
+	body, err := json.Marshal(letter)
+	if err != nil {
+		return fmt.Errorf("failed to marshal dead letter: %w", err)
+	}
+
+	if err = deadLetterQueue.Publish(body); err != nil {
+		return fmt.Errorf("failed to publish to queue '%s': %w", deadLetterQueue.Name(), err)
+	}
+
+	log.WithError(reason).WithFields(log.Fields{
+		"id":       letter.ID,
+		"attempts": attempts,
+	}).Error("Github event moved to the dead-letter queue")
+
+	return nil
+}
@@ -10,13 +10,15 @@ import (
 	"github.com/google/go-github/v38/github"
 
 	"github.com/trustwallet/assets-manager/internal/config"
+	"github.com/trustwallet/assets-manager/internal/services/consumer/blockchain"
+	"github.com/trustwallet/assets-manager/internal/services/consumer/burns"
+	"github.com/trustwallet/assets-manager/internal/services/consumer/refunds"
 )
 
 const (
 	qrGeneratorLink       = "https://api.qrserver.com/v1/create-qr-code/?size=200x200&data="
 	deepLinkToTrustWallet = "https://link.trustwallet.com/send?coin="
 	monthInSec            = 30 * 86400 * 1000
-	tokenID               = 714
 )
 
 type PaymentsParams struct {
@@ -28,55 +30,96 @@ type PaymentsParams struct {
 }
 
 type Payment struct {
-	Amount      float64
-	Symbol      string
-	Token       string
-	MinAmount   float64
-	Memo        string
-	CreatedTime int64
-	EndTime     int64
+	Amount  float64
+	Symbol  string
+	Token   string
+	Chain   string
+	Coin    uint
+	Address string
+	// Experimental is a payment on a chain which most wallets can't pay with a memo to.
+	Experimental bool
+	MinAmount    float64
+	Memo         string
+	CreatedTime  int64
+	EndTime      int64
 }
 
-func getPaymentParams(pr *github.PullRequest) *PaymentsParams {
+func getPaymentParams(pr *github.PullRequest, options []config.PaymentOption) *PaymentsParams {
 	if pr == nil {
 		return &PaymentsParams{}
 	}
 
 	createdTime := pr.GetCreatedAt().Unix() * 1000
 	memo := strconv.Itoa(pr.GetNumber())
-	payments := make([]Payment, len(config.Default.Payment.Options))
+	payments := make([]Payment, len(options))
 
 	for i := range payments {
-		payments[i].Amount = config.Default.Payment.Options[i].Amount
-		payments[i].Symbol = config.Default.Payment.Options[i].Symbol
-		payments[i].Token = config.Default.Payment.Options[i].Token
-		payments[i].MinAmount = getMinAmount(config.Default.Payment.TolerancePercent,
-			config.Default.Payment.Options[i].Amount)
+		option := options[i]
+		chain := getPaymentChain(option.Chain)
+
+		payments[i].Amount = option.Amount
+		payments[i].Symbol = option.Symbol
+		payments[i].Token = option.Token
+		payments[i].Chain = option.Chain
+		payments[i].Coin = chain.Coin
+		payments[i].Address = chain.Address
+		payments[i].Experimental = chain.Type == blockchain.ChainTypeEVM
+		payments[i].MinAmount = getMinAmount(config.Default.Payment.TolerancePercent, option.Amount)
 		payments[i].Memo = memo
 		payments[i].CreatedTime = createdTime
 		payments[i].EndTime = createdTime + monthInSec
 	}
 
-	qrTw, qrFull := getQR(config.Default.Payment.Options[0].Amount,
-		config.Default.Payment.Address, memo)
+	qrTw, qrFull := getQR(payments[0].Coin, payments[0].Amount, payments[0].Address, memo)
 
 	return &PaymentsParams{
 		Payments: payments,
 		User:     pr.GetUser().GetLogin(),
-		Address:  config.Default.Payment.Address,
+		Address:  payments[0].Address,
 		Phrase:   config.Default.Payment.SeedPhrase,
 		QR:       fmt.Sprintf("**QR** code: [Trust]( %s ) | [other wallet]( %s )", qrTw, qrFull),
 	}
 }
 
+// paymentExperimentalNote warns about payment options on EVM chains: the memo is read from data appended
+// to a token transfer, which most wallets can't add, so such payments have to be matched by a maintainer.
+const paymentExperimentalNote = " _(experimental: most wallets can't add the memo to this payment, " +
+	"a maintainer may have to match it to the PR)_"
+
+// getPaymentOptionsText returns a list of payment options, each with its chain and address.
+func getPaymentOptionsText(payments []Payment) string {
+	lines := make([]string, len(payments))
+
+	for i, payment := range payments {
+		lines[i] = fmt.Sprintf("* **%d %s** on %s to the address `%s` with the memo **%s**",
+			int(payment.Amount), payment.Symbol, payment.Chain, payment.Address, payment.Memo)
+
+		if payment.Experimental {
+			lines[i] += paymentExperimentalNote
+		}
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+func getPaymentChain(name string) config.PaymentChain {
+	for _, chain := range config.Default.Payment.Chains {
+		if chain.Name == name {
+			return chain
+		}
+	}
+
+	return config.PaymentChain{}
+}
+
 func getMinAmount(tolarancePercent, amount float64) float64 {
 	return 0.01 * math.Min(100.0, math.Max(95, tolarancePercent)) * amount
 }
 
-func getQR(amount float64, address, memo string) (qrTw, qrFull string) {
+func getQR(coinID uint, amount float64, address, memo string) (qrTw, qrFull string) {
 	deepLink := fmt.Sprintf("%s%d&address=%s&amount=%d&memo=%s",
 		deepLinkToTrustWallet,
-		tokenID,
+		coinID,
 		address,
 		int(amount),
 		memo,
@@ -95,6 +138,9 @@ type contentParams struct {
 	PaidExplorerLink string
 	BurnExplorerLink string
 	Moderators       string
+	Refund           *refunds.Case
+	MatchReasons     []string
+	Burn             *burns.Job
 }
 
 func substituteDynamicContent(text string, p *contentParams) string {
@@ -103,14 +149,19 @@ func substituteDynamicContent(text string, p *contentParams) string {
 	if p != nil && p.PP != nil {
 		m["$PAY1_AMOUNT"] = strconv.Itoa(int(p.PP.Payments[0].Amount))
 		m["$PAY1_SYMBOL"] = p.PP.Payments[0].Symbol
-		m["$PAY2_AMOUNT"] = strconv.Itoa(int(p.PP.Payments[1].Amount))
-		m["$PAY2_SYMBOL"] = p.PP.Payments[1].Symbol
 		m["$PAY1_MEMO"] = p.PP.Payments[0].Memo
 		m["$PAY1_ADDRESS"] = p.PP.Address
+		m["$PAY_OPTIONS"] = getPaymentOptionsText(p.PP.Payments)
 		m["$QR_CODE"] = p.PP.QR
 		m["$USER"] = p.PP.User
 	}
 
+	// A repository may have a single payment option.
+	if p != nil && p.PP != nil && len(p.PP.Payments) > 1 {
+		m["$PAY2_AMOUNT"] = strconv.Itoa(int(p.PP.Payments[1].Amount))
+		m["$PAY2_SYMBOL"] = p.PP.Payments[1].Symbol
+	}
+
 	if p != nil {
 		m["$PAID_AMOUNT"] = fmt.Sprintf("%.2f", p.PaidAmount)
 		m["$PAID_SYMBOL"] = p.PaidSymbol
@@ -119,6 +170,25 @@ func substituteDynamicContent(text string, p *contentParams) string {
 		m["$MODERATORS"] = getModerators(p.Moderators)
 	}
 
+	if p != nil && p.Refund != nil {
+		m["$REFUND_ID"] = p.Refund.ID
+		m["$REFUND_AMOUNT"] = fmt.Sprintf("%.2f", p.Refund.Amount)
+		m["$REFUND_SYMBOL"] = getSymbol(p.Refund.Token)
+		m["$REFUND_ADDRESS"] = p.Refund.FromAddress
+		m["$REFUND_LINK"] = p.Refund.RefundLink
+		m["$REFUND_MEMO"] = p.Refund.Memo
+	}
+
+	if p != nil && p.Burn != nil {
+		m["$BURN_ATTEMPTS"] = strconv.Itoa(p.Burn.Attempts)
+		m["$BURN_ERROR"] = p.Burn.LastError
+		m["$BURN_BATCH_SIZE"] = strconv.Itoa(p.Burn.BatchSize)
+	}
+
+	if p != nil && len(p.MatchReasons) > 0 {
+		m["$MATCH_REASONS"] = strings.Join(p.MatchReasons, ", ")
+	}
+
 	for k, v := range m {
 		text = strings.ReplaceAll(text, k, v)
 	}
@@ -126,6 +196,19 @@ func substituteDynamicContent(text string, p *contentParams) string {
 	return text
 }
 
+// getSymbol returns a token symbol from a token ID, e.g. TWT from TWT-8C2.
+func getSymbol(token string) string {
+	for _, repo := range config.Default.Repos() {
+		for _, option := range repo.PaymentOptions {
+			if strings.EqualFold(option.Token, token) {
+				return option.Symbol
+			}
+		}
+	}
+
+	return strings.Split(token, "-")[0]
+}
+
 func getLogoHTML(logoURL string) string {
 	return `<span style="padding: 5px; background-color: rgb(32,32,32);"><img src="` + logoURL +
 		`" style="max-width: 64px; border-radius: 48%;" width="48" height="48"/></span>`
@@ -5,6 +5,7 @@ import "testing"
 func Test_GetQR(t *testing.T) {
 	tests := []struct {
 		name       string
+		coin       uint
 		amount     float64
 		token      string
 		address    string
@@ -14,6 +15,7 @@ func Test_GetQR(t *testing.T) {
 	}{
 		{
 			name:       "QR for TWT token",
+			coin:       714,
 			amount:     2000,
 			address:    "bnb1tqq9llyr3dyjd559dha6z5r5etk3qfwk07m098",
 			memo:       "3395",
@@ -22,6 +24,7 @@ func Test_GetQR(t *testing.T) {
 		},
 		{
 			name:       "QR for BNB token",
+			coin:       714,
 			amount:     5,
 			address:    "bnb1tqq9llyr3dyjd559dha6z5r5etk3qfwk07m098",
 			memo:       "12",
@@ -32,7 +35,7 @@ func Test_GetQR(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			gotTwQR, gotFullQR := getQR(tt.amount, tt.address, tt.memo)
+			gotTwQR, gotFullQR := getQR(tt.coin, tt.amount, tt.address, tt.memo)
 			if gotTwQR != tt.wantTwQR {
 				t.Errorf("getQR() = %v, want %v", gotTwQR, tt.wantTwQR)
 			}
@@ -85,3 +88,17 @@ func Test_GetMinAmount(t *testing.T) {
 		})
 	}
 }
+
+func Test_GetPaymentOptionsText(t *testing.T) {
+	payments := []Payment{
+		{Amount: 700, Symbol: "TWT", Chain: "binance", Address: "bnb1fee", Memo: "12"},
+		{Amount: 5, Symbol: "BNB", Chain: "smartchain", Address: "0xfee", Memo: "12", Experimental: true},
+	}
+
+	want := "* **700 TWT** on binance to the address `bnb1fee` with the memo **12**\n" +
+		"* **5 BNB** on smartchain to the address `0xfee` with the memo **12**" + paymentExperimentalNote
+
+	if got := getPaymentOptionsText(payments); got != want {
+		t.Errorf("getPaymentOptionsText() = %q, want %q", got, want)
+	}
+}
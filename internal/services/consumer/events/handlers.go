@@ -1,9 +1,9 @@
 package events
 
 import (
-	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"strings"
 	"time"
@@ -13,37 +13,74 @@ import (
 
 	assetsmanager "github.com/trustwallet/assets-go-libs/client/assets-manager"
 	"github.com/trustwallet/assets-go-libs/file"
-	"github.com/trustwallet/assets-go-libs/http"
-	"github.com/trustwallet/assets-go-libs/image"
 	"github.com/trustwallet/assets-go-libs/path"
 	"github.com/trustwallet/assets-go-libs/validation"
 	"github.com/trustwallet/assets-go-libs/validation/list"
+	"github.com/trustwallet/assets-manager/internal/assetid"
 	"github.com/trustwallet/assets-manager/internal/config"
+	"github.com/trustwallet/assets-manager/internal/findings"
+	"github.com/trustwallet/assets-manager/internal/impersonation"
+	"github.com/trustwallet/assets-manager/internal/logo"
+	"github.com/trustwallet/assets-manager/internal/onchain"
+	"github.com/trustwallet/assets-manager/internal/services/consumer/assetinfo"
 	"github.com/trustwallet/assets-manager/internal/services/consumer/blockchain"
+	"github.com/trustwallet/assets-manager/internal/services/consumer/burns"
 	"github.com/trustwallet/assets-manager/internal/services/consumer/github"
+	"github.com/trustwallet/assets-manager/internal/services/consumer/ledger"
 	"github.com/trustwallet/assets-manager/internal/services/consumer/metrics"
+	"github.com/trustwallet/assets-manager/internal/services/consumer/prstate"
+	"github.com/trustwallet/assets-manager/internal/services/consumer/reconcile"
+	"github.com/trustwallet/assets-manager/internal/services/consumer/refunds"
+	"github.com/trustwallet/assets-manager/internal/services/consumer/snapshot"
 	"github.com/trustwallet/go-primitives/coin"
 	"github.com/trustwallet/go-primitives/types"
 )
 
+// Handler handles Github events and background checks of one repository.
 type Handler struct {
-	metrics       *metrics.Prometheus
-	github        *github.Client
-	blockchain    *blockchain.Client
-	assetsManager *assetsmanager.Client
+	repo      config.Repo
+	metrics   *metrics.Prometheus
+	github    *github.Client
+	chains    blockchain.Chains
+	ledger    *ledger.Ledger
+	refunds   *refunds.Registry
+	reconcile *reconcile.Store
+	states    *prstate.Machine
+	burns     *burns.Queue
+	sweeps    *sweeps
+	assetInfo *assetinfo.Client
+	metadata  *onchain.Resolver
+	catalogue *impersonation.Index
 }
 
 func NewHandler(
+	repo config.Repo,
 	metricsClient *metrics.Prometheus,
 	githubClient *github.Client,
-	blockchainClient *blockchain.Client,
-	assetsManager *assetsmanager.Client,
+	paymentChains blockchain.Chains,
+	paymentLedger *ledger.Ledger,
+	refundRegistry *refunds.Registry,
+	reconcileStore *reconcile.Store,
+	prStates *prstate.Machine,
+	burnQueue *burns.Queue,
+	assetInfo *assetinfo.Client,
+	metadata *onchain.Resolver,
+	catalogue *impersonation.Index,
 ) *Handler {
 	return &Handler{
-		metrics:       metricsClient,
-		github:        githubClient,
-		blockchain:    blockchainClient,
-		assetsManager: assetsManager,
+		repo:      repo,
+		metrics:   metricsClient,
+		github:    githubClient,
+		chains:    paymentChains,
+		ledger:    paymentLedger,
+		refunds:   refundRegistry,
+		reconcile: reconcileStore,
+		states:    prStates,
+		burns:     burnQueue,
+		sweeps:    newSweeps(),
+		assetInfo: assetInfo,
+		metadata:  metadata,
+		catalogue: catalogue,
 	}
 }
 
@@ -60,6 +97,10 @@ func (e Handler) HandlePullRequestOpened(ctx context.Context, event *gh.PullRequ
 		"creator": prCreator,
 	}).Debug("Pull request opened")
 
+	if _, err := e.transition(prNum, prstate.StateOpened, "opened", nil); err != nil {
+		return err
+	}
+
 	if err := e.HandlePullRequestChangesPushed(ctx, event); err != nil {
 		return err
 	}
@@ -68,16 +109,7 @@ func (e Handler) HandlePullRequestOpened(ctx context.Context, event *gh.PullRequ
 		return nil
 	}
 
-	if err := e.github.SetLabelOnPullRequest(ctx, owner, repo, prNum, &gh.Label{
-		Name: gh.String(config.Default.Label.Requested),
-	}); err != nil {
-		return err
-	}
-
-	pp := getPaymentParams(event.GetPullRequest())
-	commentText := substituteDynamicContent(config.Default.Message.Initial, &contentParams{PP: pp})
-
-	return e.github.CreateCommentOnPullRequest(ctx, owner, repo, commentText, prNum)
+	return e.requestPayment(ctx, owner, repo, event.GetPullRequest(), "opened")
 }
 
 func (e Handler) HandleIssueCommentCreated(ctx context.Context, event *gh.IssueCommentEvent) error {
@@ -98,6 +130,18 @@ func (e Handler) HandleIssueCommentCreated(ctx context.Context, event *gh.IssueC
 		"creator": commentCreator,
 	}).Debug("Issued comment created")
 
+	if handled, err := e.handleRefundCommand(ctx, owner, repo, prNum, commentCreator, commentBody); handled {
+		return err
+	}
+
+	if handled, err := e.handleMatchCommand(ctx, owner, repo, prNum, commentCreator, commentBody); handled {
+		return err
+	}
+
+	if handled, err := e.handleHistoryCommand(ctx, owner, repo, prNum, commentCreator, commentBody); handled {
+		return err
+	}
+
 	if e.isCollaborator(prCreator) {
 		return nil
 	}
@@ -141,7 +185,7 @@ func (e Handler) HandlePullRequestReviewCommentCreated(ctx context.Context,
 func (e Handler) deleteCommentIfNeeded(ctx context.Context, owner, repo, prCreator,
 	user string, commentID int64,
 ) error {
-	if !config.Default.UserAccess.DeleteCommentsFromExternal {
+	if !e.repo.UserAccess.DeleteCommentsFromExternal {
 		return nil
 	}
 
@@ -153,25 +197,35 @@ func (e Handler) deleteCommentIfNeeded(ctx context.Context, owner, repo, prCreat
 }
 
 func (e Handler) isUserCollaboratorOrCreator(creator, user string) bool {
-	isBot := strings.HasPrefix(user, config.Default.ServiceName)
 	isCreator := user == creator
 
-	return isBot || isCreator || e.isCollaborator(user)
+	return isServiceUser(user) || isCreator || e.isCollaborator(user)
+}
+
+func isServiceUser(user string) bool {
+	return strings.HasPrefix(user, config.Default.ServiceName)
 }
 
 func (e Handler) isCollaborator(user string) bool {
-	isCollaborator := false
+	return isUserInList(e.repo.UserAccess.Collaborators, user)
+}
 
-	if config.Default.UserAccess.Collaborators != "" {
-		collaborators := strings.Split(config.Default.UserAccess.Collaborators, ",")
-		for _, collaborator := range collaborators {
-			if user == collaborator {
-				isCollaborator = true
-			}
+func (e Handler) isModerator(user string) bool {
+	return isUserInList(e.repo.UserAccess.Moderators, user)
+}
+
+func isUserInList(rawList, user string) bool {
+	if rawList == "" {
+		return false
+	}
+
+	for _, u := range strings.Split(rawList, ",") {
+		if user == u {
+			return true
 		}
 	}
 
-	return isCollaborator
+	return false
 }
 
 func (e Handler) checkPullStatus(ctx context.Context, owner, repo string, pr *gh.PullRequest, debug bool) error {
@@ -183,9 +237,22 @@ func (e Handler) checkPullStatus(ctx context.Context, owner, repo string, pr *gh
 		return nil
 	}
 
-	if !e.isPaymentExpected(ctx, owner, repo, pr) {
+	st, err := e.getState(ctx, owner, repo, pr)
+	if err != nil {
+		return err
+	}
+
+	// Paid label set manually by a moderator waives the fee.
+	if isPaymentExpected(st) && hasLabel(pr, e.repo.Label.Paid) {
+		_, err = e.transition(pr.GetNumber(), prstate.StatePaid, eventLabel, nil)
+
+		return err
+	}
+
+	if !isPaymentExpected(st) && !isApprovalPending(st) {
+		// The payment status is posted apart from the summary comment, which keeps the file checks.
 		if debug {
-			text := substituteDynamicContent(config.Default.Message.Reviewed, nil)
+			text := substituteDynamicContent(e.repo.Message.Reviewed, nil)
 
 			return e.github.CreateCommentOnPullRequest(ctx, owner, repo, text, pr.GetNumber())
 		}
@@ -202,6 +269,11 @@ func (e Handler) checkPullStatus(ctx context.Context, owner, repo string, pr *gh
 		return e.approvePullRequest(ctx, owner, repo, pr, paymentStatus)
 	}
 
+	// A paid pull request is not closed or reminded even if its payment is no longer found.
+	if isApprovalPending(st) {
+		return nil
+	}
+
 	now := time.Now()
 	prAgeHours := now.Sub(pr.GetCreatedAt())
 	prUpdateAgeHours := now.Sub(pr.GetUpdatedAt())
@@ -209,70 +281,171 @@ func (e Handler) checkPullStatus(ctx context.Context, owner, repo string, pr *gh
 
 	// Check for too old -> close pr.
 	if prAgeHours >= config.Default.Timeout.MaxAgeClose && prUpdateAgeHours > halfHour {
-		return e.closePullRequest(ctx, owner, repo, pr)
+		_, err = e.transition(pr.GetNumber(), prstate.StateClosed, "timeout", func() (string, error) {
+			return "", e.closePullRequest(ctx, owner, repo, pr)
+		})
+
+		return err
 	}
 
 	if debug {
-		text := substituteDynamicContent(config.Default.Message.NotReceived, nil)
+		text := substituteDynamicContent(e.repo.Message.NotReceived, nil)
 
 		return e.github.CreateCommentOnPullRequest(ctx, owner, repo, text, pr.GetNumber())
 	}
 
 	// Check if it's time for reminder.
-	if prUpdateAgeHours >= config.Default.Timeout.MaxIdleRemind {
-		return e.remindToPay(ctx, owner, repo, pr)
+	stateAge := now.Sub(st.UpdatedAt)
+	if prUpdateAgeHours >= config.Default.Timeout.MaxIdleRemind && stateAge >= config.Default.Timeout.MaxIdleRemind {
+		_, err = e.transition(pr.GetNumber(), prstate.StateReminded, "timeout", func() (string, error) {
+			return "", e.remindToPay(ctx, owner, repo, pr)
+		})
+
+		return err
 	}
 
 	return nil
 }
 
-func (e Handler) isPaymentExpected(ctx context.Context, owner, repo string, pr *gh.PullRequest) bool {
-	return !(e.hasReviewAlready(ctx, owner, repo, pr) || e.hasLabelAlready(ctx, owner, repo, pr))
-}
+// Steps of the approval of a paid pull request. The approval is started with the paid state,
+// then its steps are done and recorded one by one, so a retried approval doesn't repeat them.
+const (
+	stepApproval    = "approval"
+	stepReview      = "review"
+	stepLabel       = "label"
+	stepAssignees   = "assignees"
+	stepOverpayment = "overpayment"
+)
 
 func (e Handler) approvePullRequest(ctx context.Context, owner, repo string,
 	pr *gh.PullRequest, ps *blockchain.PaymentStatus,
 ) error {
-	text := substituteDynamicContent(config.Default.Message.Received, &contentParams{
-		PaidAmount:       ps.Amount,
-		PaidSymbol:       strings.Split(ps.Token, "-")[0],
-		PaidExplorerLink: ps.Transactions[0].ExplorerLink,
-		Moderators:       config.Default.UserAccess.Moderators,
-	})
-
-	if _, err := e.github.CreateReview(ctx, owner, repo, text, "APPROVE", pr.GetNumber()); err != nil {
+	burnAmount, overpayment, err := e.detectOverpayment(pr, ps)
+	if err != nil {
 		return err
 	}
 
-	if err := e.github.SetLabelOnPullRequest(ctx, owner, repo, pr.GetNumber(), &gh.Label{
-		Name: gh.String(config.Default.Label.Paid),
-	}); err != nil {
+	// The burn job is enqueued before the state is saved, so a paid pull request always has a burn job.
+	paid, err := e.transition(pr.GetNumber(), prstate.StatePaid, eventPayment, func() (string, error) {
+		_, err := e.burns.Enqueue(&burns.Job{
+			PRNum:  pr.GetNumber(),
+			Chain:  ps.Chain,
+			Token:  ps.Token,
+			Amount: burnAmount,
+			TxHash: ps.Transactions[0].Hash,
+		})
+		if err != nil {
+			return "", err
+		}
+
+		return ps.Transactions[0].ExplorerLink, e.states.RecordStep(pr.GetNumber(), stepApproval)
+	})
+	if err != nil {
 		return err
 	}
 
-	assignedUsers := strings.Split(config.Default.UserAccess.Moderators, ",")
-	if _, err := e.github.AddAssignees(ctx, owner, repo, pr.GetNumber(), assignedUsers); err != nil {
+	if paid {
+		e.metrics.IncCounterPaymentsDetected()
+	}
+
+	if err = e.completeApproval(ctx, owner, repo, pr, ps, overpayment); err != nil {
 		return err
 	}
 
-	e.metrics.IncCounterPaymentsDetected()
+	if !paid || e.burns.IsBatched() {
+		return nil
+	}
+
+	return e.runBurn(ctx, owner, repo, burns.JobID(pr.GetNumber(), ps.Transactions[0].Hash))
+}
+
+// completeApproval does the steps of a started approval which are not recorded yet.
+func (e Handler) completeApproval(ctx context.Context, owner, repo string,
+	pr *gh.PullRequest, ps *blockchain.PaymentStatus, overpayment *refunds.Case,
+) error {
+	unlock := e.states.Lock(pr.GetNumber())
+	defer unlock()
 
-	explorerLink, err := e.blockchain.BurnToken(ps.Token, int64(ps.Amount*blockchain.AmountPrecision))
-	if err != nil || explorerLink == "" {
+	st, err := e.states.Get(pr.GetNumber())
+	if err != nil || !isApprovalPending(st) {
 		return err
 	}
 
-	text = substituteDynamicContent(config.Default.Message.Burned, &contentParams{
-		PaidAmount:       ps.Amount,
-		PaidSymbol:       strings.Split(ps.Token, "-")[0],
-		BurnExplorerLink: explorerLink,
-	})
+	steps := []struct {
+		name string
+		run  func() error
+	}{
+		{name: stepReview, run: func() error {
+			// A review posted before its step was recorded is not posted again.
+			if e.hasReviewAlready(ctx, owner, repo, pr) {
+				return nil
+			}
 
-	return e.github.CreateCommentOnPullRequest(ctx, owner, repo, text, pr.GetNumber())
+			text := substituteDynamicContent(e.repo.Message.Received, &contentParams{
+				PaidAmount:       ps.Amount,
+				PaidSymbol:       strings.Split(ps.Token, "-")[0],
+				PaidExplorerLink: ps.Transactions[0].ExplorerLink,
+				Moderators:       e.repo.UserAccess.Moderators,
+			})
+
+			_, err := e.github.CreateReview(ctx, owner, repo, text, "APPROVE", pr.GetNumber())
+
+			return err
+		}},
+		{name: stepLabel, run: func() error {
+			return e.github.SetLabelOnPullRequest(ctx, owner, repo, pr.GetNumber(), &gh.Label{
+				Name: gh.String(e.repo.Label.Paid),
+			})
+		}},
+		{name: stepAssignees, run: func() error {
+			assignedUsers := strings.Split(e.repo.UserAccess.Moderators, ",")
+			_, err := e.github.AddAssignees(ctx, owner, repo, pr.GetNumber(), assignedUsers)
+
+			return err
+		}},
+		{name: stepOverpayment, run: func() error {
+			if overpayment == nil {
+				return nil
+			}
+
+			return e.commentOverpayment(ctx, owner, repo, overpayment, ps.Transactions[len(ps.Transactions)-1].ExplorerLink)
+		}},
+	}
+
+	for _, step := range steps {
+		if st.HasStep(step.name) {
+			continue
+		}
+
+		if err = step.run(); err != nil {
+			return fmt.Errorf("approval step %s failed: %w", step.name, err)
+		}
+
+		if err = e.states.RecordStep(pr.GetNumber(), step.name); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// isApprovalPending reports if a pull request is paid and its approval has steps not done yet.
+func isApprovalPending(st *prstate.PR) bool {
+	if !st.Is(prstate.StatePaid) || !st.HasStep(stepApproval) {
+		return false
+	}
+
+	for _, step := range []string{stepReview, stepLabel, stepAssignees, stepOverpayment} {
+		if !st.HasStep(step) {
+			return true
+		}
+	}
+
+	return false
 }
 
 func (e Handler) closePullRequest(ctx context.Context, owner, repo string, pr *gh.PullRequest) error {
-	text := substituteDynamicContent(config.Default.Message.ClosingOldPR, nil)
+	text := substituteDynamicContent(e.repo.Message.ClosingOldPR, nil)
 	if err := e.github.CreateCommentOnPullRequest(ctx, owner, repo, text, pr.GetNumber()); err != nil {
 		return err
 	}
@@ -281,15 +454,15 @@ func (e Handler) closePullRequest(ctx context.Context, owner, repo string, pr *g
 }
 
 func (e Handler) remindToPay(ctx context.Context, owner, repo string, pr *gh.PullRequest) error {
-	pp := getPaymentParams(pr)
-	text := substituteDynamicContent(config.Default.Message.Reminder, &contentParams{PP: pp})
+	pp := getPaymentParams(pr, e.repo.PaymentOptions)
+	text := substituteDynamicContent(e.repo.Message.Reminder, &contentParams{PP: pp})
 
 	return e.github.CreateCommentOnPullRequest(ctx, owner, repo, text, pr.GetNumber())
 }
 
 func (e Handler) hasReviewAlready(ctx context.Context, owner, repo string, pr *gh.PullRequest) bool {
 	list, err := e.github.GetPullRequestReviewList(ctx, owner, repo, pr.GetNumber())
-	if err != nil {
+	if allowTruncated(err, pr.GetNumber()) != nil {
 		return false
 	}
 
@@ -306,12 +479,12 @@ func (e Handler) hasReviewAlready(ctx context.Context, owner, repo string, pr *g
 
 func (e Handler) hasLabelAlready(ctx context.Context, owner, repo string, pr *gh.PullRequest) bool {
 	labels, err := e.github.GetIssueListLabels(ctx, owner, repo, pr.GetNumber())
-	if err != nil {
+	if allowTruncated(err, pr.GetNumber()) != nil {
 		return false
 	}
 
 	for _, label := range labels {
-		if label.GetName() == config.Default.Label.Paid {
+		if label.GetName() == e.repo.Label.Paid {
 			return true
 		}
 	}
@@ -320,28 +493,51 @@ func (e Handler) hasLabelAlready(ctx context.Context, owner, repo string, pr *gh
 }
 
 func (e Handler) checkPaymentForPullRequest(pr *gh.PullRequest) (*blockchain.PaymentStatus, error) {
-	params := getPaymentParams(pr)
+	params := getPaymentParams(pr, e.repo.PaymentOptions)
+
+	// Payments are checked by the transfers already ingested of chains failed to sync.
+	_, syncErr := e.ledger.Sync()
+
+	var failed *ledger.SyncError
+	if syncErr != nil && !errors.As(syncErr, &failed) {
+		return nil, syncErr
+	}
 
 	for _, p := range params.Payments {
-		txs, err := e.blockchain.GetTransactionsForAddress(params.Address)
+		chain, err := e.chains.Get(p.Chain)
 		if err != nil {
 			return nil, err
 		}
 
-		ps := blockchain.GetPaymentStatus(
-			txs, params.Address, p.Memo, p.Token, p.CreatedTime, p.EndTime, p.MinAmount)
+		matched, err := e.reconcile.GetMatchedTransfers(chain.Name(), pr.GetNumber(), p.Memo)
+		if err != nil {
+			return nil, err
+		}
+
+		ps, err := e.ledger.GetPaymentStatus(chain, p.Memo, p.Token, p.CreatedTime, p.EndTime, p.MinAmount, matched...)
+		if err != nil {
+			return nil, err
+		}
 
 		if ps.Paid {
 			return ps, nil
 		}
 	}
 
+	if failed != nil {
+		for _, p := range params.Payments {
+			if err, ok := failed.Chains[p.Chain]; ok {
+				return nil, err
+			}
+		}
+	}
+
 	return &blockchain.PaymentStatus{}, nil
 }
 
 func (e Handler) CheckOpenPullRequests(ctx context.Context, owner, repo string, pr *gh.PullRequest) error {
-	prs, err := e.github.GetPullRequestsList(ctx, owner, repo, "open", 100)
-	if err != nil {
+	prs, err := e.github.GetPullRequestsList(ctx, owner, repo, "open")
+	if err = allowTruncated(err, 0); err != nil {
 		return fmt.Errorf("failed to get open pull requests: %w", err)
 	}
 
@@ -358,7 +554,7 @@ func (e Handler) CheckOpenPullRequests(ctx context.Context, owner, repo string,
 		prs = append(prs, pr)
 	}
 
-	e.metrics.SetPullRequestsOpen(len(prs))
+	e.metrics.SetPullRequestsOpen(e.repo.FullName(), len(prs))
 
 	prCountToPay := 0
 	for _, p := range prs {
@@ -367,82 +563,114 @@ func (e Handler) CheckOpenPullRequests(ctx context.Context, owner, repo string,
 			return err
 		}
 
-		if e.isPaymentExpected(ctx, owner, repo, pr) {
+		st, err := e.states.Get(p.GetNumber())
+		if err != nil {
+			return err
+		}
+
+		if isPaymentExpected(st) {
 			prCountToPay++
 		}
 	}
 
-	e.metrics.SetPullRequestsToPay(prCountToPay)
+	e.metrics.SetPullRequestsToPay(e.repo.FullName(), prCountToPay)
 
-	return nil
+	return e.scanMisdirectedPayments(ctx, owner, repo)
 }
 
 func (e Handler) HandlePullRequestChangesPushed(ctx context.Context, event *gh.PullRequestEvent) error {
 	owner := event.GetRepo().GetOwner().GetLogin()
 	repo := event.GetRepo().GetName()
 	pr := event.GetPullRequest()
-	branch := event.GetPullRequest().GetHead().GetRef()
-	headOwner := event.GetPullRequest().GetHead().GetRepo().GetOwner().GetLogin()
-	headRepo := event.GetPullRequest().GetHead().GetRepo().GetName()
 
 	log.WithFields(log.Fields{
 		"pr_num":  pr.GetNumber(),
-		"creator": headOwner,
+		"creator": pr.GetHead().GetRepo().GetOwner().GetLogin(),
 	}).Debug("Pull request changes are pushed")
 
-	files, err := e.github.GetPullRequestFileList(ctx, owner, repo, pr.GetNumber(), 100)
-	if err != nil {
+	return e.checkPullRequestChanges(ctx, owner, repo, pr)
+}
+
+// checkPullRequestChanges checks files of a pull request head, reports results in the summary comment
+// and the check run, then checks the payment status.
+func (e Handler) checkPullRequestChanges(ctx context.Context, owner, repo string, pr *gh.PullRequest) error {
+	files, err := e.github.GetPullRequestFileList(ctx, owner, repo, pr.GetNumber())
+	truncated := github.IsTruncated(err)
+
+	if err = allowTruncated(err, pr.GetNumber()); err != nil {
 		return err
 	}
 
-	filesCheckSummary := e.getFilesCheckSummary(files, owner)
-	tokenCheckSummary := e.getTokensCheckSummary(files, headOwner, headRepo, branch)
-	validatorsCheckSummary := e.getValidatorsCheckSummary(files, headOwner, headRepo, branch)
+	report := &checkReport{}
+	head := e.newPRHead(owner, repo, pr)
+
+	filesCheckSummary := e.getFilesCheckSummary(files, owner, report)
+	tokenCheckSummary := e.getTokensCheckSummary(ctx, files, head, report)
+	validatorsCheckSummary := e.getValidatorsCheckSummary(ctx, files, head, report)
+	consistencyCheckSummary := e.getConsistencyCheckSummary(ctx, files, head, report)
 
-	summary := fmt.Sprintf("%s%s\n%s", filesCheckSummary, tokenCheckSummary, validatorsCheckSummary)
-	if tokenCheckSummary == "" && validatorsCheckSummary == "" {
+	summary := fmt.Sprintf("%s%s\n%s\n%s", filesCheckSummary, tokenCheckSummary, validatorsCheckSummary,
+		consistencyCheckSummary)
+	if tokenCheckSummary == "" && validatorsCheckSummary == "" && consistencyCheckSummary == "" {
 		summary = "No token files found. If you try to add/modify a token, " +
 			"check the name and location of your files! Logo file must be named exactly 'logo.png'. " +
 			"If you are not adding a token, ignore this message."
 	}
 
-	err = e.github.CreateCommentOnPullRequest(ctx, owner, repo, summary, pr.GetNumber())
+	if truncated {
+		summary += fmt.Sprintf("\n\n⚠️ The PR has too many files, only the first %d are checked.", len(files))
+	}
+
+	err = e.updateSummary(ctx, owner, repo, pr.GetNumber(), summary)
+	if err != nil {
+		return err
+	}
+
+	err = e.publishCheckRun(ctx, owner, repo, pr, summary, report)
 	if err != nil {
 		return err
 	}
 
-	err = e.checkPullStatus(ctx, owner, repo, pr, false)
+	err = e.labelImpersonation(ctx, owner, repo, pr, report)
 	if err != nil {
 		return err
 	}
 
+	return e.checkPullStatus(ctx, owner, repo, pr, false)
+}
+
+// allowTruncated logs a list truncated at the page limit and returns nil, so the received part is used.
+// Other errors are returned.
+func allowTruncated(err error, prNum int) error {
+	if !github.IsTruncated(err) {
+		return err
+	}
+
+	log.WithError(err).WithField("pr_num", prNum).Warn("Github list truncated at the page limit")
+
 	return nil
 }
 
-func (e Handler) getFilesCheckSummary(files []*gh.CommitFile, repoOwner string) string {
+func (e Handler) getFilesCheckSummary(files []*gh.CommitFile, repoOwner string, report *checkReport) string {
 	text := "### PR Summary\n"
 
-	checkSummary := e.checkPullRequestFiles(files, config.Default.Limitation.PrFilesNumMax, repoOwner)
-	if checkSummary != "" {
-		return fmt.Sprintf("%s%s", text, checkSummary)
+	found := e.checkPullRequestFiles(files, config.Default.Limitation.PrFilesNumMax, repoOwner)
+	found = findings.Override(found, findings.Overrides(""))
+
+	if len(found) > 0 {
+		report.add(found...)
+
+		return fmt.Sprintf("%s%s", text, findings.Markdown(found))
 	}
 
 	return fmt.Sprintf("%sFiles OK: %d\n", text, len(files))
 }
 
 // nolint: gosec
-func (e Handler) getTokensCheckSummary(files []*gh.CommitFile, repoOwner, repoName, branch string) string {
-	// Check tokens.
-	tokenIDs := make(map[string]string)
-
-	for _, file := range files {
-		id, tokenType := path.GetTokenFromAssetLogoPath(file.GetFilename())
-
-		if id != "" && tokenType != "" {
-			tokenIDs[id] = tokenType
-		}
-	}
-
+func (e Handler) getTokensCheckSummary(
+	ctx context.Context, files []*gh.CommitFile, head *prHead, report *checkReport,
+) string {
+	tokenIDs := prTokens(files)
 	if len(tokenIDs) == 0 {
 		return ""
 	}
@@ -464,14 +692,31 @@ func (e Handler) getTokensCheckSummary(files []*gh.CommitFile, repoOwner, repoNa
 			text += fmt.Sprintf("\n-----\n**Token %s - %s**:", tokenType, id)
 		}
 
-		msg := e.checkToken(id, tokenType, repoOwner, repoName, branch)
+		msg := e.checkToken(ctx, head, id, tokenType, report)
 		text += fmt.Sprintf("\n%s\n", msg)
 	}
 
 	return text
 }
 
-func (e Handler) getValidatorsCheckSummary(files []*gh.CommitFile, repoOwner, repoName, branch string) string {
+// prTokens returns types of tokens by ID, which logos are changed by a PR.
+func prTokens(files []*gh.CommitFile) map[string]string {
+	tokenIDs := make(map[string]string)
+
+	for _, file := range files {
+		id, tokenType := path.GetTokenFromAssetLogoPath(file.GetFilename())
+
+		if id != "" && tokenType != "" {
+			tokenIDs[id] = tokenType
+		}
+	}
+
+	return tokenIDs
+}
+
+func (e Handler) getValidatorsCheckSummary(
+	ctx context.Context, files []*gh.CommitFile, head *prHead, report *checkReport,
+) string {
 	validatorLists := make([]*file.Path, 0)
 	validatorAssetLogos := make([]*file.Path, 0)
 
@@ -490,14 +735,21 @@ func (e Handler) getValidatorsCheckSummary(files []*gh.CommitFile, repoOwner, re
 	text := "**Validators check**\n"
 	if (len(validatorAssetLogos) == 0 && len(validatorLists) > 0) ||
 		(len(validatorAssetLogos) > 0 && len(validatorLists) == 0) {
-		return fmt.Sprintf("%s❌ For adding asset validators, you need to add validator logo and update list.json", text)
+		found := findings.Finding{
+			Rule:     findings.RuleValidatorsIncomplete,
+			Severity: findings.SeverityError,
+			Message:  "For adding asset validators, you need to add validator logo and update list.json",
+		}
+		report.add(found)
+
+		return text + findings.Markdown([]findings.Finding{found})
 	}
 
 	if len(validatorAssetLogos) == 0 && len(validatorLists) == 0 {
 		return ""
 	}
 
-	errorsMsg := e.checkValidators(validatorLists, validatorAssetLogos, repoOwner, repoName, branch)
+	errorsMsg := e.checkValidators(ctx, validatorLists, validatorAssetLogos, head, report)
 	if errorsMsg != "" {
 		return fmt.Sprintf("%s%s", text, errorsMsg)
 	}
@@ -506,20 +758,26 @@ func (e Handler) getValidatorsCheckSummary(files []*gh.CommitFile, repoOwner, re
 }
 
 func (e Handler) checkValidators(
-	validatorLists, validatorAssetLogos []*file.Path, repoOwner, repoName, branch string,
+	ctx context.Context, validatorLists, validatorAssetLogos []*file.Path, head *prHead, report *checkReport,
 ) string {
 	var errorsMsg string
 
 	for _, vlist := range validatorLists {
-		listURL := path.GetValidatorListGithubURL(repoOwner, repoName, branch, vlist.Chain().Handle)
-		bytes, err := http.GetHTTPResponseBytes(listURL)
+		listURL := path.GetValidatorListGithubURL(head.owner, head.repo, head.sha, vlist.Chain().Handle)
+		bytes, err := head.tree.ReadFile(ctx, vlist.String())
 		if err != nil {
+			report.add(findings.New(findings.RuleValidatorsList, findings.SeverityError, "",
+				fmt.Errorf("failed to get file content: %w", err)).At(vlist.String(), 1))
+
 			return fmt.Sprintf("Failed to get file content of [list.json](%s)", listURL)
 		}
 
 		var validatorList []list.Model
 		err = json.Unmarshal(bytes, &validatorList)
 		if err != nil {
+			report.add(findings.New(findings.RuleValidatorsList, findings.SeverityError, "",
+				fmt.Errorf("failed to parse content: %w", err)).At(vlist.String(), 1))
+
 			return fmt.Sprintf("Failed to parse content of [list.json](%s)", listURL)
 		}
 
@@ -534,12 +792,22 @@ func (e Handler) checkValidators(
 			}
 
 			if _, exists := validatorMap[vlogo.Asset()]; !exists {
-				errorsMsg += fmt.Sprintf("❌ Asset '%s' (%s) not found in [list.json](%s)\n",
-					vlogo.Asset(), vlogo.Chain().Handle, listURL)
+				found := findings.Finding{
+					Rule:     findings.RuleValidatorsList,
+					Severity: findings.SeverityError,
+					Message:  fmt.Sprintf("Asset '%s' (%s) not found in list.json", vlogo.Asset(), vlogo.Chain().Handle),
+					Fix:      "add the validator to list.json",
+				}.At(vlist.String(), 1)
+				report.add(found)
+				errorsMsg += findings.Markdown([]findings.Finding{found})
 			}
 
-			logoURL := path.GetValidatorAssetLogoGithubURL(repoOwner, repoName, branch, vlogo.Chain().Handle, vlogo.Asset())
-			errorsMsg += fmt.Sprintf("%s\n\n%s", e.checkLogo(logoURL), getLogoHTML(logoURL))
+			logoURL := path.GetValidatorAssetLogoGithubURL(head.owner, head.repo, head.sha,
+				vlogo.Chain().Handle, vlogo.Asset())
+			logoFound := findings.Override(e.checkLogo(ctx, head.tree, vlogo.String()),
+				findings.Overrides(vlogo.Chain().Handle))
+			report.add(logoFound...)
+			errorsMsg += fmt.Sprintf("%s\n\n%s", findings.Markdown(logoFound), getLogoHTML(logoURL))
 
 			errorsMsg += "\n-----\n"
 		}
@@ -548,43 +816,77 @@ func (e Handler) checkValidators(
 	return errorsMsg
 }
 
-func (e Handler) checkPullRequestFiles(files []*gh.CommitFile, limit int, repoOwner string) string {
+func (e Handler) checkPullRequestFiles(files []*gh.CommitFile, limit int, repoOwner string) []findings.Finding {
 	if len(files) == 0 {
-		return "No changed files found."
+		return []findings.Finding{{
+			Rule:     findings.RulePRFiles,
+			Severity: findings.SeverityError,
+			Message:  "No changed files found.",
+		}}
 	}
 
 	if len(files) > limit && !e.isCollaborator(repoOwner) {
-		return fmt.Sprintf("Too many changed files: %d (max %d).", len(files), limit)
+		return []findings.Finding{{
+			Rule:     findings.RulePRFiles,
+			Severity: findings.SeverityError,
+			Message:  fmt.Sprintf("Too many changed files: %d (max %d).", len(files), limit),
+			Fix:      "split the PR",
+		}}
 	}
 
-	var msg string
+	found := make([]findings.Finding, 0)
 
 	for _, file := range files {
 		if err := validation.ValidateFileInPR(file.GetFilename()); err != nil {
-			msg += fmt.Sprintf("%s: Please revert it.\n", err.Error())
+			found = append(found, findings.New(findings.RulePRFileNotAllowed, findings.SeverityError, "",
+				findings.WithFix(err, "revert it")).At(file.GetFilename(), 1))
 		}
 
+		// Deleted files are not annotated, they don't exist in the head commit.
 		if file.GetStatus() == "removed" {
-			msg += fmt.Sprintf("File `%s` is being deleted. Files should not be deleted in a PR. "+
-				"(Deprecated tokens should be deactivated only.)\n", file.GetFilename())
+			found = append(found, findings.Finding{
+				Rule:     findings.RulePRFileDeleted,
+				Severity: findings.SeverityError,
+				Message: fmt.Sprintf("File `%s` is being deleted. Files should not be deleted in a PR.",
+					file.GetFilename()),
+				Fix: "deprecated tokens should be deactivated only",
+			})
 		}
 	}
 
-	return msg
+	return found
 }
 
-func (e Handler) checkToken(tokenID, tokenType, repoOwner, repoName, branch string) string {
+func (e Handler) checkToken(ctx context.Context, head *prHead, tokenID, tokenType string, report *checkReport) string {
 	chain, err := types.GetChainFromAssetType(tokenType)
 	if err != nil {
+		report.add(findings.New(findings.RuleAssetType, findings.SeverityError, "", err))
+
 		return "failed to get chain from asset type"
 	}
 
-	logoURL := path.GetAssetLogoGithubURL(repoOwner, repoName, branch, chain.Handle, tokenID)
-	infoURL := path.GetAssetInfoGithubURL(repoOwner, repoName, branch, chain.Handle, tokenID)
+	logoURL := path.GetAssetLogoGithubURL(head.owner, head.repo, head.sha, chain.Handle, tokenID)
+	infoURL := path.GetAssetInfoGithubURL(head.owner, head.repo, head.sha, chain.Handle, tokenID)
+	logoPath := path.GetAssetLogoPath(chain.Handle, tokenID)
+	infoPath := path.GetAssetInfoPath(chain.Handle, tokenID)
+
+	tokenInfo, content, err := readAssetInfo(ctx, head.tree, infoPath)
+
+	// Files deleted or moved away in the PR are not in the head.
+	if errors.Is(err, snapshot.ErrNotFound) {
+		report.add(findings.Finding{
+			Rule:     findings.RuleAssetInfo,
+			Severity: findings.SeverityError,
+			Message:  fmt.Sprintf("info.json of %s is not found in the PR head, deleted or moved", tokenID),
+		})
+
+		return fmt.Sprintf("info.json is not found in the PR head (%s)", infoURL)
+	}
 
-	tokenInfo := &assetsmanager.AssetValidationReq{}
-	err = http.GetHTTPResponse(infoURL, tokenInfo)
 	if err != nil {
+		report.add(findings.New(findings.RuleAssetInfo, findings.SeverityError, "",
+			fmt.Errorf("failed to get info.json content: %w", err)).At(infoPath, 1))
+
 		return fmt.Sprintf("Failed to get info.json content: %s (%s)", err.Error(), infoURL)
 	}
 
@@ -614,6 +916,9 @@ func (e Handler) checkToken(tokenID, tokenType, repoOwner, repoName, branch stri
 
 	explorerFromID, err := coin.GetCoinExploreURL(chain, tokenID, tokenType)
 	if err != nil {
+		report.add(findings.New(findings.RuleAssetExplorer, findings.SeverityError, "explorer",
+			fmt.Errorf("failed to retrieve explore url: %w", err)).At(infoPath, fieldLine(content, "explorer")))
+
 		return fmt.Sprintf("Failed to retrieve explore url: %v", err)
 	}
 
@@ -627,13 +932,23 @@ func (e Handler) checkToken(tokenID, tokenType, repoOwner, repoName, branch stri
 		text += fmt.Sprintf("\nTags: %s", strings.Join(tokenInfo.Tags, ", "))
 	}
 
-	msg := e.checkLogo(logoURL)
+	found := checkTokenID(tokenID, tokenType, infoPath, content)
+	found = append(found, e.checkLogo(ctx, head.tree, logoPath)...)
+	found = append(found, e.checkAssetInfo(tokenInfo, infoPath, content)...)
+	found = append(found, e.checkMetadata(ctx, tokenID, tokenType, tokenInfo, infoPath, content)...)
+	found = append(found, e.checkImpersonation(ctx, head, chain.Handle, tokenID, tokenInfo)...)
+	// Findings of the API have the severities overridden already, so they are overridden before
+	// the repeats are removed.
+	found = findings.Unique(findings.Override(found, findings.Overrides(chain.Handle)))
+	report.add(found...)
 
-	msg += e.checkAssetInfo(tokenInfo)
-	if msg == "" {
+	if !findings.HasErrors(found) {
 		text += "\n✅ Check OK"
+		if len(found) > 0 {
+			text += "\n" + findings.Markdown(found)
+		}
 	} else {
-		text += fmt.Sprintf("\nToken check error: \n%s\n", msg)
+		text += fmt.Sprintf("\nToken check error: \n%s\n", findings.Markdown(found))
 	}
 
 	text += fmt.Sprintf("\n\n%s\n\n", getLogoHTML(logoURL))
@@ -641,48 +956,86 @@ func (e Handler) checkToken(tokenID, tokenType, repoOwner, repoName, branch stri
 	return text
 }
 
-func (e Handler) checkAssetInfo(tokenInfo *assetsmanager.AssetValidationReq) string {
-	result, err := e.assetsManager.ValidateAssetInfo(tokenInfo)
+// readAssetInfo returns info.json of a token parsed and its content to locate findings.
+func readAssetInfo(
+	ctx context.Context, tree snapshot.Tree, infoPath string,
+) (*assetsmanager.AssetValidationReq, []byte, error) {
+	content, err := tree.ReadFile(ctx, infoPath)
 	if err != nil {
-		log.Debugf(err.Error())
-
-		return "failed to check asset info.json"
+		return nil, nil, err
 	}
 
-	if result.Status == "ok" {
-		return ""
+	tokenInfo := &assetsmanager.AssetValidationReq{}
+	if err = json.Unmarshal(content, tokenInfo); err != nil {
+		return nil, nil, fmt.Errorf("failed to unmarshal json: %w", err)
 	}
 
-	var text string
-	for _, assetError := range result.Errors {
-		text += fmt.Sprintf("❌ %s\n", assetError.Message)
+	return tokenInfo, content, nil
+}
+
+// checkTokenID validates an ID of a token folder by the format of its chain.
+func checkTokenID(tokenID, tokenType, infoPath string, content []byte) []findings.Finding {
+	if err := assetid.Validate(tokenID, tokenType); err != nil {
+		return []findings.Finding{
+			findings.New(findings.RuleAssetID, findings.SeverityError, "id", err).At(infoPath, fieldLine(content, "id")),
+		}
 	}
 
-	return text
+	return nil
 }
 
-func (e Handler) checkLogo(url string) string {
-	data, err := http.GetHTTPResponseBytes(url)
+// checkAssetInfo validates info.json, findings are located on the lines of the fields they are about.
+func (e Handler) checkAssetInfo(
+	tokenInfo *assetsmanager.AssetValidationReq, infoPath string, content []byte,
+) []findings.Finding {
+	found, err := e.assetInfo.Validate(tokenInfo)
 	if err != nil {
-		return fmt.Sprintf("failed to get logo: %s\n", err.Error())
+		log.Debugf(err.Error())
+
+		return []findings.Finding{{
+			Rule:     findings.RuleAssetInfo,
+			Severity: findings.SeverityError,
+			Message:  "failed to check asset info.json",
+		}}
 	}
 
-	var text string
+	for i := range found {
+		found[i] = found[i].At(infoPath, fieldLine(content, found[i].Field))
+	}
 
-	w, h, err := image.GetPNGImageDimensionsFromReader(bytes.NewReader(data))
+	return found
+}
+
+// checkMetadata checks info.json of a token against the token metadata the providers agree on.
+func (e Handler) checkMetadata(ctx context.Context, tokenID, tokenType string,
+	tokenInfo *assetsmanager.AssetValidationReq, infoPath string, content []byte,
+) []findings.Finding {
+	metadata, err := e.metadata.Resolve(ctx, tokenID, tokenType)
 	if err != nil {
-		return fmt.Sprintf("failed to get logo dimensions: %s\n", err.Error())
+		log.WithError(err).WithField("token", tokenID).Debug("Failed to get token metadata")
 	}
 
-	err = validation.ValidateImageDimension(w, h)
-	if err != nil {
-		text += fmt.Sprintf("❌ %s\n", err.Error())
+	found := onchain.Check(metadata, tokenInfo.Decimals, tokenInfo.Symbol,
+		config.Default.Validation.Asset.HoldersMinRequired)
+
+	for i := range found {
+		found[i] = found[i].At(infoPath, fieldLine(content, found[i].Field))
 	}
 
-	err = validation.ValidateLogoStreamSize(data)
+	return found
+}
+
+func (e Handler) checkLogo(ctx context.Context, tree snapshot.Tree, logoPath string) []findings.Finding {
+	data, err := tree.ReadFile(ctx, logoPath)
 	if err != nil {
-		text += fmt.Sprintf("❌ %s\n", err.Error())
+		return []findings.Finding{findings.New(findings.RuleLogo, findings.SeverityError, "",
+			fmt.Errorf("failed to get logo: %w", err)).At(logoPath, 1)}
 	}
 
-	return text
+	_, found := logo.Check(data)
+	for i := range found {
+		found[i] = found[i].At(logoPath, 1)
+	}
+
+	return found
 }
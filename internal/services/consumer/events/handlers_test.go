@@ -0,0 +1,33 @@
+package events
+
+import (
+	"testing"
+
+	"github.com/trustwallet/assets-manager/internal/services/consumer/prstate"
+)
+
+func Test_IsApprovalPending(t *testing.T) {
+	allSteps := []string{stepApproval, stepReview, stepLabel, stepAssignees, stepOverpayment}
+
+	tests := []struct {
+		name  string
+		state prstate.State
+		steps []string
+		want  bool
+	}{
+		{name: "Approval started", state: prstate.StatePaid, steps: []string{stepApproval}, want: true},
+		{name: "Review posted", state: prstate.StatePaid, steps: []string{stepApproval, stepReview}, want: true},
+		{name: "Approval done", state: prstate.StatePaid, steps: allSteps, want: false},
+		{name: "Paid by label", state: prstate.StatePaid, want: false},
+		{name: "Merged before done", state: prstate.StateMerged, steps: []string{stepApproval}, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			st := &prstate.PR{State: tt.state, Steps: tt.steps}
+			if got := isApprovalPending(st); got != tt.want {
+				t.Errorf("isApprovalPending() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,29 @@
+package events
+
+import (
+	gh "github.com/google/go-github/v38/github"
+
+	"github.com/trustwallet/assets-manager/internal/services/consumer/snapshot"
+)
+
+// prHead is a snapshot of a PR head the checks run against, with the base branch it is compared to.
+// Links to its files in the summary are pinned to the checked commit.
+type prHead struct {
+	tree  snapshot.Tree
+	base  snapshot.Tree
+	owner string
+	repo  string
+	sha   string
+}
+
+// newPRHead returns a head of a PR read by the Git Data API of the base repository.
+// The base is read at the current state of the branch, not at the commit the PR started from.
+func (e Handler) newPRHead(owner, repo string, pr *gh.PullRequest) *prHead {
+	return &prHead{
+		tree:  snapshot.NewGit(e.github, owner, repo, pr.GetHead().GetSHA()),
+		base:  snapshot.NewGit(e.github, owner, repo, pr.GetBase().GetRef()),
+		owner: pr.GetHead().GetRepo().GetOwner().GetLogin(),
+		repo:  pr.GetHead().GetRepo().GetName(),
+		sha:   pr.GetHead().GetSHA(),
+	}
+}
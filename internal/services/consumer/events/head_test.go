@@ -0,0 +1,108 @@
+package events
+
+import (
+	"bytes"
+	"context"
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	gh "github.com/google/go-github/v38/github"
+
+	"github.com/trustwallet/assets-manager/internal/findings"
+	"github.com/trustwallet/assets-manager/internal/services/consumer/snapshot"
+)
+
+// writeFixture writes files of a fixture repository to a directory.
+func writeFixture(t *testing.T, files map[string][]byte) string {
+	t.Helper()
+
+	root := t.TempDir()
+	for name, data := range files {
+		file := filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(file), 0o755); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := os.WriteFile(file, data, 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return root
+}
+
+func Test_getValidatorsCheckSummary(t *testing.T) {
+	img := image.NewNRGBA(image.Rect(0, 0, 256, 256))
+	for i := 0; i < len(img.Pix); i += 4 {
+		img.Pix[i], img.Pix[i+3] = 200, 255
+	}
+
+	img.Set(0, 0, color.NRGBA{})
+
+	var logo bytes.Buffer
+	if err := png.Encode(&logo, img); err != nil {
+		t.Fatal(err)
+	}
+
+	const (
+		listPath = "blockchains/cosmos/validators/list.json"
+		logoPath = "blockchains/cosmos/validators/assets/cosmosvaloper1abc/logo.png"
+	)
+
+	list := []byte(`[{"id": "cosmosvaloper1abc", "name": "Validator", "description": "", "website": ""}]`)
+
+	tests := []struct {
+		name      string
+		files     map[string][]byte
+		wantRules []string
+	}{
+		{
+			name:  "Valid",
+			files: map[string][]byte{listPath: list, logoPath: logo.Bytes()},
+		},
+		{
+			name:      "Not in list",
+			files:     map[string][]byte{listPath: []byte(`[]`), logoPath: logo.Bytes()},
+			wantRules: []string{findings.RuleValidatorsList},
+		},
+		{
+			name:      "Logo deleted",
+			files:     map[string][]byte{listPath: list},
+			wantRules: []string{findings.RuleLogo},
+		},
+		{
+			name:      "List invalid",
+			files:     map[string][]byte{listPath: []byte(`{`), logoPath: logo.Bytes()},
+			wantRules: []string{findings.RuleValidatorsList},
+		},
+	}
+
+	files := []*gh.CommitFile{{Filename: gh.String(listPath)}, {Filename: gh.String(logoPath)}}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			head := &prHead{tree: snapshot.NewDir(writeFixture(t, tt.files)), owner: "owner", repo: "assets", sha: "head"}
+			report := &checkReport{}
+
+			summary := Handler{}.getValidatorsCheckSummary(context.Background(), files, head, report)
+
+			rules := make([]string, 0)
+			for _, f := range report.found {
+				rules = append(rules, f.Rule)
+			}
+
+			if strings.Join(rules, ",") != strings.Join(tt.wantRules, ",") {
+				t.Errorf("getValidatorsCheckSummary() rules = %v, want %v\n%s", rules, tt.wantRules, summary)
+			}
+
+			if !strings.Contains(summary, "/owner/assets/head/") && len(tt.wantRules) == 0 {
+				t.Errorf("getValidatorsCheckSummary() links not pinned to the head:\n%s", summary)
+			}
+		})
+	}
+}
@@ -0,0 +1,102 @@
+package events
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"image"
+
+	gh "github.com/google/go-github/v38/github"
+
+	assetsmanager "github.com/trustwallet/assets-go-libs/client/assets-manager"
+	"github.com/trustwallet/assets-go-libs/path"
+	"github.com/trustwallet/assets-go-libs/validation"
+	"github.com/trustwallet/assets-manager/internal/config"
+	"github.com/trustwallet/assets-manager/internal/findings"
+	"github.com/trustwallet/assets-manager/internal/impersonation"
+)
+
+// checkImpersonation compares a token of a PR to the catalogue and warns of tokens it is likely to impersonate.
+func (e Handler) checkImpersonation(
+	ctx context.Context, head *prHead, chain, tokenID string, info *assetsmanager.AssetValidationReq,
+) []findings.Finding {
+	if e.catalogue == nil {
+		return nil
+	}
+
+	candidate := impersonation.Candidate{Chain: chain, ID: tokenID}
+	if info.Name != nil {
+		candidate.Name = *info.Name
+	}
+
+	if info.Symbol != nil {
+		candidate.Symbol = *info.Symbol
+	}
+
+	// An invalid logo is reported by the logo check, the token is compared by name and symbol then.
+	if data, err := head.tree.ReadFile(ctx, path.GetAssetLogoPath(chain, tokenID)); err == nil {
+		candidate.Logo = decodeLogo(data)
+	}
+
+	options := config.Default.Validation.Impersonation
+	matches := e.catalogue.Match(candidate, impersonation.Options{
+		Threshold:    options.Threshold,
+		LogoDistance: options.LogoDistance,
+		MaxMatches:   options.MaxMatches,
+	})
+
+	found := make([]findings.Finding, 0, len(matches))
+	for i := range matches {
+		m := &matches[i]
+		found = append(found, findings.Finding{
+			Rule:     findings.RuleAssetImpersonation,
+			Severity: findings.SeverityWarning,
+			Message: fmt.Sprintf("Possible impersonation of %s (%s) `%s` on %s, score %.2f: %s.",
+				m.Name, m.Symbol, m.ID, m.Chain, m.Score, m.Reasons()),
+		})
+	}
+
+	return found
+}
+
+// decodeLogo decodes a logo within the max logo dimensions, nil otherwise. Dimensions are checked
+// before decoding, so a small file declaring huge dimensions is never decoded.
+func decodeLogo(data []byte) image.Image {
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil || cfg.Width > validation.MaxW || cfg.Height > validation.MaxH {
+		return nil
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil
+	}
+
+	return img
+}
+
+// labelImpersonation sets the impersonation label on a PR with tokens likely impersonating existing ones,
+// and removes it when they are not reported anymore.
+func (e Handler) labelImpersonation(
+	ctx context.Context, owner, repo string, pr *gh.PullRequest, report *checkReport,
+) error {
+	label := e.repo.Label.Impersonation
+	if label == "" || e.catalogue == nil {
+		return nil
+	}
+
+	flagged := false
+
+	for _, f := range report.found {
+		flagged = flagged || f.Rule == findings.RuleAssetImpersonation
+	}
+
+	switch {
+	case flagged && !hasLabel(pr, label):
+		return e.github.SetLabelOnPullRequest(ctx, owner, repo, pr.GetNumber(), &gh.Label{Name: gh.String(label)})
+	case !flagged && hasLabel(pr, label):
+		return e.github.RemoveLabelFromPullRequest(ctx, owner, repo, pr.GetNumber(), label)
+	}
+
+	return nil
+}
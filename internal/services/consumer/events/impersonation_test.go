@@ -0,0 +1,97 @@
+package events
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"hash/crc32"
+	"image"
+	"image/png"
+	"testing"
+
+	assetsmanager "github.com/trustwallet/assets-go-libs/client/assets-manager"
+	"github.com/trustwallet/assets-manager/internal/config"
+	"github.com/trustwallet/assets-manager/internal/findings"
+	"github.com/trustwallet/assets-manager/internal/impersonation"
+	"github.com/trustwallet/assets-manager/internal/services/consumer/snapshot"
+)
+
+func Test_checkImpersonation(t *testing.T) {
+	config.Default.Validation.Impersonation.Threshold = 0.85
+	config.Default.Validation.Impersonation.LogoDistance = 8
+	config.Default.Validation.Impersonation.MaxMatches = 3
+
+	catalogue := &impersonation.Index{Entries: []impersonation.Entry{
+		{Chain: "ethereum", ID: "0xdAC17F958D2ee523a2206206994597C13D831ec7", Name: "Tether", Symbol: "USDT"},
+	}}
+
+	head := &prHead{tree: snapshot.NewDir(t.TempDir())}
+
+	tests := []struct {
+		name      string
+		handler   Handler
+		info      *assetsmanager.AssetValidationReq
+		wantFound int
+	}{
+		{
+			name:      "Impersonation",
+			handler:   Handler{catalogue: catalogue},
+			info:      &assetsmanager.AssetValidationReq{Name: strPtr("Tether USD"), Symbol: strPtr("USDT")},
+			wantFound: 1,
+		},
+		{
+			name:    "Unrelated",
+			handler: Handler{catalogue: catalogue},
+			info:    &assetsmanager.AssetValidationReq{Name: strPtr("Some Token"), Symbol: strPtr("SOME")},
+		},
+		{
+			name:    "No catalogue",
+			handler: Handler{},
+			info:    &assetsmanager.AssetValidationReq{Name: strPtr("Tether"), Symbol: strPtr("USDT")},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			found := tt.handler.checkImpersonation(context.Background(), head, "smartchain", "0x1", tt.info)
+			if len(found) != tt.wantFound {
+				t.Fatalf("checkImpersonation() = %+v, want %d findings", found, tt.wantFound)
+			}
+
+			for _, f := range found {
+				if f.Rule != findings.RuleAssetImpersonation || f.Severity != findings.SeverityWarning {
+					t.Errorf("checkImpersonation() finding = %+v, want an impersonation warning", f)
+				}
+			}
+		})
+	}
+}
+
+func Test_decodeLogo(t *testing.T) {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, image.NewNRGBA(image.Rect(0, 0, 256, 256))); err != nil {
+		t.Fatal(err)
+	}
+
+	if decodeLogo(buf.Bytes()) == nil {
+		t.Error("decodeLogo() of a valid logo = nil")
+	}
+
+	// Declare huge dimensions in the IHDR chunk, which follows the 8 bytes signature.
+	data := buf.Bytes()
+	binary.BigEndian.PutUint32(data[16:], 100000)
+	binary.BigEndian.PutUint32(data[20:], 100000)
+	binary.BigEndian.PutUint32(data[29:], crc32.ChecksumIEEE(data[12:29]))
+
+	if _, _, err := image.DecodeConfig(bytes.NewReader(data)); err != nil {
+		t.Fatalf("DecodeConfig() of a patched logo error = %v", err)
+	}
+
+	if decodeLogo(data) != nil {
+		t.Error("decodeLogo() of a logo with huge dimensions != nil")
+	}
+}
+
+func strPtr(s string) *string {
+	return &s
+}
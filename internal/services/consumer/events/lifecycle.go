@@ -0,0 +1,211 @@
+package events
+
+import (
+	"context"
+
+	gh "github.com/google/go-github/v38/github"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/trustwallet/assets-manager/internal/services/consumer/prstate"
+)
+
+const (
+	eventLabel   = "label"
+	eventPayment = "payment"
+	eventMerged  = "merged"
+	eventClosed  = "closed"
+)
+
+// HandlePullRequestReopened moves a reopened pull request back to the state it was closed in:
+// burned or paid if the fee had been paid, otherwise payment is requested again.
+func (e Handler) HandlePullRequestReopened(ctx context.Context, event *gh.PullRequestEvent) error {
+	pr := event.GetPullRequest()
+
+	log.WithFields(log.Fields{
+		"pr_num":  pr.GetNumber(),
+		"creator": pr.GetUser().GetLogin(),
+	}).Debug("Pull request reopened")
+
+	st, err := e.states.Get(pr.GetNumber())
+	if err != nil {
+		return err
+	}
+
+	if _, err = e.transition(pr.GetNumber(), prstate.StateOpened, "reopened", nil); err != nil {
+		return err
+	}
+
+	if wasPaid(st) {
+		if _, err = e.transition(pr.GetNumber(), prstate.StatePaid, "reopened", nil); err != nil || !wasBurned(st) {
+			return err
+		}
+
+		// A burned fee is not burned and reported again.
+		_, err = e.transition(pr.GetNumber(), prstate.StateBurned, "reopened", nil)
+
+		return err
+	}
+
+	if e.isCollaborator(pr.GetUser().GetLogin()) {
+		return nil
+	}
+
+	return e.requestPayment(ctx, event.GetRepo().GetOwner().GetLogin(), event.GetRepo().GetName(), pr, "reopened")
+}
+
+// HandlePullRequestClosed records the final state of a closed or merged pull request,
+// which stops reminders, and removes the payment requested label from an unpaid one.
+func (e Handler) HandlePullRequestClosed(ctx context.Context, event *gh.PullRequestEvent) error {
+	owner := event.GetRepo().GetOwner().GetLogin()
+	repo := event.GetRepo().GetName()
+	pr := event.GetPullRequest()
+
+	log.WithFields(log.Fields{
+		"pr_num": pr.GetNumber(),
+		"merged": pr.GetMerged(),
+	}).Debug("Pull request closed")
+
+	st, err := e.states.Get(pr.GetNumber())
+	if err != nil {
+		return err
+	}
+
+	to, name := prstate.StateClosed, eventClosed
+	if pr.GetMerged() {
+		to, name = prstate.StateMerged, eventMerged
+	}
+
+	if _, err = e.transition(pr.GetNumber(), to, name, func() (string, error) {
+		return event.GetSender().GetLogin(), nil
+	}); err != nil {
+		return err
+	}
+
+	if wasPaid(st) || !hasLabel(pr, e.repo.Label.Requested) {
+		return nil
+	}
+
+	return e.github.RemoveLabelFromPullRequest(ctx, owner, repo, pr.GetNumber(), e.repo.Label.Requested)
+}
+
+// HandlePullRequestLabeled moves a pull request waiting for payment to paid when a maintainer
+// sets the paid label, which waives the fee.
+func (e Handler) HandlePullRequestLabeled(ctx context.Context, event *gh.PullRequestEvent) error {
+	pr := event.GetPullRequest()
+
+	if !e.isManualPaidLabel(event) || e.isCollaborator(pr.GetUser().GetLogin()) {
+		return nil
+	}
+
+	st, err := e.getState(ctx, event.GetRepo().GetOwner().GetLogin(), event.GetRepo().GetName(), pr)
+	if err != nil || !isPaymentExpected(st) {
+		return err
+	}
+
+	log.WithFields(log.Fields{
+		"pr_num": pr.GetNumber(),
+		"sender": event.GetSender().GetLogin(),
+	}).Info("Paid label set manually")
+
+	_, err = e.transition(pr.GetNumber(), prstate.StatePaid, eventLabel, func() (string, error) {
+		return event.GetSender().GetLogin(), nil
+	})
+
+	return err
+}
+
+// HandlePullRequestUnlabeled requests payment again when a maintainer removes the paid label
+// set manually. A pull request paid on-chain keeps its state.
+func (e Handler) HandlePullRequestUnlabeled(ctx context.Context, event *gh.PullRequestEvent) error {
+	pr := event.GetPullRequest()
+
+	if !e.isManualPaidLabel(event) {
+		return nil
+	}
+
+	st, err := e.states.Get(pr.GetNumber())
+	if err != nil {
+		return err
+	}
+
+	if !st.Is(prstate.StatePaid) || st.History[len(st.History)-1].Event != eventLabel {
+		return nil
+	}
+
+	log.WithFields(log.Fields{
+		"pr_num": pr.GetNumber(),
+		"sender": event.GetSender().GetLogin(),
+	}).Info("Paid label removed manually")
+
+	_, err = e.transition(pr.GetNumber(), prstate.StatePaymentRequested, eventLabel, func() (string, error) {
+		return event.GetSender().GetLogin(), nil
+	})
+
+	return err
+}
+
+// HandlePullRequestReviewSubmitted checks the status of a pull request reviewed by a maintainer.
+func (e Handler) HandlePullRequestReviewSubmitted(ctx context.Context, event *gh.PullRequestReviewEvent) error {
+	if e.isBot(event.GetSender()) {
+		return nil
+	}
+
+	log.WithFields(log.Fields{
+		"pr_num":   event.GetPullRequest().GetNumber(),
+		"reviewer": event.GetReview().GetUser().GetLogin(),
+		"state":    event.GetReview().GetState(),
+	}).Debug("Pull request review submitted")
+
+	owner := event.GetRepo().GetOwner().GetLogin()
+	repo := event.GetRepo().GetName()
+
+	return e.checkPullStatus(ctx, owner, repo, event.GetPullRequest(), false)
+}
+
+// requestPayment sets the payment requested label and posts the payment instructions once per request.
+func (e Handler) requestPayment(ctx context.Context, owner, repo string, pr *gh.PullRequest, event string) error {
+	_, err := e.transition(pr.GetNumber(), prstate.StatePaymentRequested, event, func() (string, error) {
+		if err := e.github.SetLabelOnPullRequest(ctx, owner, repo, pr.GetNumber(), &gh.Label{
+			Name: gh.String(e.repo.Label.Requested),
+		}); err != nil {
+			return "", err
+		}
+
+		pp := getPaymentParams(pr, e.repo.PaymentOptions)
+		commentText := substituteDynamicContent(e.repo.Message.Initial, &contentParams{PP: pp})
+
+		return "", e.github.CreateCommentOnPullRequest(ctx, owner, repo, commentText, pr.GetNumber())
+	})
+
+	return err
+}
+
+// isManualPaidLabel reports if the paid label is changed by a user, not by the bot itself.
+func (e Handler) isManualPaidLabel(event *gh.PullRequestEvent) bool {
+	return event.GetLabel().GetName() == e.repo.Label.Paid && !e.isBot(event.GetSender())
+}
+
+func (e Handler) isBot(user *gh.User) bool {
+	return user.GetType() == "Bot" || isServiceUser(user.GetLogin())
+}
+
+// wasPaid reports if a pull request has ever been paid.
+func wasPaid(st *prstate.PR) bool {
+	for _, t := range st.History {
+		if t.To == prstate.StatePaid {
+			return true
+		}
+	}
+
+	return false
+}
+
+func wasBurned(st *prstate.PR) bool {
+	for _, t := range st.History {
+		if t.To == prstate.StateBurned {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,52 @@
+package events
+
+import (
+	"context"
+	"testing"
+
+	gh "github.com/google/go-github/v38/github"
+
+	"github.com/trustwallet/assets-manager/internal/services/consumer/prstate"
+	"github.com/trustwallet/assets-manager/internal/storage"
+)
+
+func Test_HandlePullRequestReopened_Paid(t *testing.T) {
+	tests := []struct {
+		name    string
+		history []prstate.State
+		want    prstate.State
+	}{
+		{
+			name:    "Paid",
+			history: []prstate.State{prstate.StateOpened, prstate.StatePaid, prstate.StateClosed},
+			want:    prstate.StatePaid,
+		},
+		{
+			name:    "Burned",
+			history: []prstate.State{prstate.StateOpened, prstate.StatePaid, prstate.StateBurned, prstate.StateClosed},
+			want:    prstate.StateBurned,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := Handler{states: prstate.NewMachine(storage.NewMemoryStore())}
+
+			for _, to := range tt.history {
+				if _, err := e.states.Transition(1, to, "test", ""); err != nil {
+					t.Fatalf("Transition(%s) error = %v", to, err)
+				}
+			}
+
+			event := &gh.PullRequestEvent{PullRequest: &gh.PullRequest{Number: gh.Int(1)}}
+			if err := e.HandlePullRequestReopened(context.Background(), event); err != nil {
+				t.Fatalf("HandlePullRequestReopened() error = %v", err)
+			}
+
+			st, err := e.states.Get(1)
+			if err != nil || st.State != tt.want {
+				t.Errorf("state = %v, %v, want %s", st, err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,242 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	gh "github.com/google/go-github/v38/github"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/trustwallet/assets-manager/internal/config"
+	"github.com/trustwallet/assets-manager/internal/services/consumer/blockchain"
+	"github.com/trustwallet/assets-manager/internal/services/consumer/reconcile"
+	"github.com/trustwallet/assets-manager/internal/services/consumer/refunds"
+	"github.com/trustwallet/assets-manager/internal/storage"
+)
+
+const commandMatch = "/match"
+
+// ReconcilePayments suggests open pull requests for payments with a wrong or missing memo.
+// A suggestion is posted on a pull request once, a moderator confirms it with the match command.
+func (e Handler) ReconcilePayments(ctx context.Context, owner, repo string) error {
+	cases := make([]*refunds.Case, 0)
+
+	err := e.refunds.ForEach(func(c *refunds.Case) error {
+		if c.Kind == refunds.KindWrongMemo && c.Status == refunds.StatusOpen {
+			cases = append(cases, c)
+		}
+
+		return nil
+	})
+	if err != nil || len(cases) == 0 {
+		return err
+	}
+
+	prs, err := e.github.GetPullRequestsList(ctx, owner, repo, "open")
+	if err = allowTruncated(err, 0); err != nil {
+		return fmt.Errorf("failed to get open pull requests: %w", err)
+	}
+
+	candidates := e.getReconcileCandidates(prs)
+	if len(candidates) == 0 {
+		return nil
+	}
+
+	linkedPRs, err := e.getLinkedPullRequests()
+	if err != nil {
+		return err
+	}
+
+	options := reconcile.Options{
+		TimeWindow:     config.Default.Payment.Reconciliation.TimeWindow,
+		MinScore:       config.Default.Payment.Reconciliation.MinScore,
+		MaxSuggestions: config.Default.Payment.Reconciliation.MaxSuggestions,
+	}
+
+	for _, c := range cases {
+		tx, err := e.ledger.Get(c.Chain, c.TxHash)
+		if err != nil {
+			return err
+		}
+
+		suggestions := reconcile.Suggest(tx, candidates, linkedPRs[tx.FromAddress], getReconcileFees(e.repo.PaymentOptions), options)
+		for _, suggestion := range suggestions {
+			if err = e.suggestMatch(ctx, owner, repo, c, tx, suggestion); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func (e Handler) suggestMatch(ctx context.Context, owner, repo string,
+	refundCase *refunds.Case, tx *blockchain.Tx, suggestion reconcile.Suggestion,
+) error {
+	suggested, err := e.reconcile.IsSuggested(refundCase.ID, suggestion.PRNum)
+	if err != nil || suggested {
+		return err
+	}
+
+	log.WithFields(log.Fields{
+		"pr_num": suggestion.PRNum,
+		"case":   refundCase.ID,
+		"score":  suggestion.Score,
+	}).Info("Unmatched payment suggested for pull request")
+
+	text := substituteDynamicContent(e.repo.Message.MatchSuggest, &contentParams{
+		PaidExplorerLink: tx.ExplorerLink,
+		Moderators:       e.repo.UserAccess.Moderators,
+		Refund:           refundCase,
+		MatchReasons:     suggestion.Reasons,
+	})
+
+	if err = e.github.CreateCommentOnPullRequest(ctx, owner, repo, text, suggestion.PRNum); err != nil {
+		return err
+	}
+
+	if err = e.reconcile.AddSuggestion(refundCase.ID, suggestion); err != nil {
+		return err
+	}
+
+	return e.refunds.AddAudit(refundCase.ID, "bot", "match_suggested", fmt.Sprintf("#%d", suggestion.PRNum))
+}
+
+// getReconcileCandidates returns pull requests waiting for a payment.
+func (e Handler) getReconcileCandidates(prs []*gh.PullRequest) []reconcile.Candidate {
+	candidates := make([]reconcile.Candidate, 0)
+
+	for _, pr := range prs {
+		if e.isCollaborator(pr.GetUser().GetLogin()) {
+			continue
+		}
+
+		var requested, paid bool
+
+		for _, label := range pr.Labels {
+			requested = requested || label.GetName() == e.repo.Label.Requested
+			paid = paid || label.GetName() == e.repo.Label.Paid
+		}
+
+		if requested && !paid {
+			candidates = append(candidates, reconcile.Candidate{
+				PRNum:     pr.GetNumber(),
+				CreatedAt: pr.GetCreatedAt().UnixMilli(),
+			})
+		}
+	}
+
+	return candidates
+}
+
+// getLinkedPullRequests returns pull requests each sender has paid for, by ledger transfers with a PR memo.
+func (e Handler) getLinkedPullRequests() (map[string]map[int]bool, error) {
+	linked := make(map[string]map[int]bool)
+
+	err := e.ledger.ForEach(func(tx *blockchain.Tx) error {
+		prNum, err := strconv.Atoi(strings.TrimSpace(tx.Memo))
+		if err != nil || prNum <= 0 {
+			return nil
+		}
+
+		if linked[tx.FromAddress] == nil {
+			linked[tx.FromAddress] = make(map[int]bool)
+		}
+
+		linked[tx.FromAddress][prNum] = true
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return linked, nil
+}
+
+func getReconcileFees(options []config.PaymentOption) []reconcile.Fee {
+	fees := make([]reconcile.Fee, len(options))
+
+	for i, option := range options {
+		fees[i] = reconcile.Fee{
+			Chain:     option.Chain,
+			Token:     option.Token,
+			MinAmount: getMinAmount(config.Default.Payment.TolerancePercent, option.Amount),
+			Amount:    option.Amount * (1 + config.Default.Payment.Refund.OverpaymentPercent/100),
+		}
+	}
+
+	return fees
+}
+
+// handleMatchCommand handles "/match <case id or tx hash>" comments from moderators.
+// The payment is counted as a payment for the pull request and the pull request is checked again.
+// Returns false if the comment is not a match command.
+func (e Handler) handleMatchCommand(ctx context.Context, owner, repo string,
+	prNum int, user, body string,
+) (bool, error) {
+	args, ok := parseCommand(body, commandMatch)
+	if !ok {
+		return false, nil
+	}
+
+	if !e.isModerator(user) {
+		log.WithField("user", user).Warn("Match command from non-moderator ignored")
+
+		return true, nil
+	}
+
+	if len(args) == 0 {
+		return true, e.github.CreateCommentOnPullRequest(ctx, owner, repo,
+			fmt.Sprintf("Usage: `%s <case id or tx hash>`", commandMatch), prNum)
+	}
+
+	refundCase, err := e.refunds.Get(args[0])
+	if errors.Is(err, storage.ErrNotFound) {
+		return true, e.github.CreateCommentOnPullRequest(ctx, owner, repo,
+			fmt.Sprintf("Unmatched payment `%s` not found.", args[0]), prNum)
+	}
+
+	if err != nil {
+		return true, err
+	}
+
+	if refundCase.Kind != refunds.KindWrongMemo || refundCase.Status != refunds.StatusOpen {
+		return true, e.github.CreateCommentOnPullRequest(ctx, owner, repo,
+			fmt.Sprintf("Payment `%s` can't be matched, it is %s (%s).",
+				refundCase.ID, refundCase.Status, refundCase.Kind), prNum)
+	}
+
+	tx, err := e.ledger.Get(refundCase.Chain, refundCase.TxHash)
+	if err != nil {
+		return true, err
+	}
+
+	if err = e.reconcile.AddMatch(refundCase.ID, prNum, tx, user); err != nil {
+		return true, err
+	}
+
+	if err = e.refunds.SetStatus(refundCase.ID, refunds.StatusMatched, user, ""); err != nil {
+		return true, err
+	}
+
+	if err = e.refunds.AddAudit(refundCase.ID, user, "matched_pr", fmt.Sprintf("#%d", prNum)); err != nil {
+		return true, err
+	}
+
+	log.WithFields(log.Fields{
+		"pr_num": prNum,
+		"case":   refundCase.ID,
+		"user":   user,
+	}).Info("Unmatched payment confirmed for pull request")
+
+	pr, err := e.github.GetPullRequest(ctx, owner, repo, prNum)
+	if err != nil {
+		return true, err
+	}
+
+	return true, e.checkPullStatus(ctx, owner, repo, pr, true)
+}
@@ -0,0 +1,335 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	gh "github.com/google/go-github/v38/github"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/trustwallet/assets-manager/internal/config"
+	"github.com/trustwallet/assets-manager/internal/services/consumer/blockchain"
+	"github.com/trustwallet/assets-manager/internal/services/consumer/refunds"
+	"github.com/trustwallet/assets-manager/internal/storage"
+)
+
+const (
+	commandRefund = "/refund"
+	flagDryRun    = "dry-run"
+)
+
+// detectOverpayment records a refund case when a payment exceeds the fee. Returns the amount to keep
+// and the case of the surplus, if any, which is reported by the approval of the pull request.
+func (e Handler) detectOverpayment(pr *gh.PullRequest, ps *blockchain.PaymentStatus) (float64, *refunds.Case, error) {
+	payment := findPayment(getPaymentParams(pr, e.repo.PaymentOptions), ps.Chain, ps.Token)
+	if payment == nil || len(ps.Transactions) == 0 {
+		return ps.Amount, nil, nil
+	}
+
+	threshold := payment.Amount * (1 + config.Default.Payment.Refund.OverpaymentPercent/100)
+	if ps.Amount <= threshold {
+		return ps.Amount, nil, nil
+	}
+
+	lastTx := ps.Transactions[len(ps.Transactions)-1]
+	refundCase := refunds.NewCase(refunds.KindOverpayment, pr.GetNumber(), &lastTx, ps.Amount-payment.Amount)
+
+	recorded, err := e.refunds.Record(refundCase)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	if recorded {
+		log.WithFields(log.Fields{
+			"pr_num": pr.GetNumber(),
+			"case":   refundCase.ID,
+			"amount": refundCase.Amount,
+		}).Info("Overpayment detected")
+	}
+
+	return payment.Amount, refundCase, nil
+}
+
+// commentOverpayment reports a refund case of an overpayment to moderators.
+func (e Handler) commentOverpayment(ctx context.Context, owner, repo string,
+	refundCase *refunds.Case, explorerLink string,
+) error {
+	text := substituteDynamicContent(e.repo.Message.Overpaid, &contentParams{
+		PaidExplorerLink: explorerLink,
+		Moderators:       e.repo.UserAccess.Moderators,
+		Refund:           refundCase,
+	})
+
+	return e.github.CreateCommentOnPullRequest(ctx, owner, repo, text, refundCase.PRNum)
+}
+
+// scanMisdirectedPayments checks new ledger transfers for payments to closed PRs and payments with a wrong memo.
+func (e Handler) scanMisdirectedPayments(ctx context.Context, owner, repo string) error {
+	since, err := e.refunds.ScanSince()
+	if err != nil {
+		return err
+	}
+
+	scanned := make(map[string]bool, len(e.repo.PaymentOptions))
+
+	for _, option := range e.repo.PaymentOptions {
+		if scanned[option.Chain] {
+			continue
+		}
+
+		scanned[option.Chain] = true
+
+		if err = e.scanChainPayments(ctx, owner, repo, option.Chain, since); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// scanChainPayments checks transfers of a chain ingested after the scan cursor, which is moved
+// past every transfer checked.
+func (e Handler) scanChainPayments(ctx context.Context, owner, repo, chain string, since int64) error {
+	cursor, ok, err := e.refunds.ScanCursor(chain)
+	if err != nil {
+		return err
+	}
+
+	if !ok {
+		return e.scanLedgerPayments(ctx, owner, repo, chain, since)
+	}
+
+	type numberedTx struct {
+		seq uint64
+		tx  blockchain.Tx
+	}
+
+	txs := make([]numberedTx, 0)
+
+	err = e.ledger.ForEachSince(chain, cursor, func(seq uint64, tx *blockchain.Tx) error {
+		txs = append(txs, numberedTx{seq: seq, tx: *tx})
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	for i := range txs {
+		if txs[i].tx.Date >= since {
+			if err = e.checkMisdirectedPayment(ctx, owner, repo, &txs[i].tx); err != nil {
+				return err
+			}
+		}
+
+		if err = e.refunds.SaveScanCursor(chain, txs[i].seq); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// scanLedgerPayments checks all unchecked transfers of a chain once, including transfers ingested before
+// they were numbered, and sets the scan cursor to the last transfer.
+func (e Handler) scanLedgerPayments(ctx context.Context, owner, repo, chain string, since int64) error {
+	last, err := e.ledger.LastSeq(chain)
+	if err != nil {
+		return err
+	}
+
+	txs := make([]blockchain.Tx, 0)
+
+	err = e.ledger.ForEach(func(tx *blockchain.Tx) error {
+		if tx.Chain != chain || tx.Date < since {
+			return nil
+		}
+
+		scanned, err := e.refunds.IsScanned(tx)
+		if err != nil || scanned {
+			return err
+		}
+
+		txs = append(txs, *tx)
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	for i := range txs {
+		if err = e.checkMisdirectedPayment(ctx, owner, repo, &txs[i]); err != nil {
+			return err
+		}
+
+		if err = e.refunds.MarkScanned(&txs[i]); err != nil {
+			return err
+		}
+	}
+
+	// Transfers ingested during the scan are numbered after the last one and checked by the next scan.
+	return e.refunds.SaveScanCursor(chain, last)
+}
+
+func (e Handler) checkMisdirectedPayment(ctx context.Context, owner, repo string, tx *blockchain.Tx) error {
+	prNum, err := strconv.Atoi(strings.TrimSpace(tx.Memo))
+	if err != nil || prNum <= 0 {
+		return e.recordWrongMemo(tx)
+	}
+
+	pr, err := e.github.GetPullRequest(ctx, owner, repo, prNum)
+	if err != nil {
+		var ghErr *gh.ErrorResponse
+		if errors.As(err, &ghErr) && ghErr.Response != nil && ghErr.Response.StatusCode == http.StatusNotFound {
+			return e.recordWrongMemo(tx)
+		}
+
+		return err
+	}
+
+	if pr.GetState() != "closed" || tx.Date < pr.GetClosedAt().UnixMilli() {
+		return nil
+	}
+
+	refundCase := refunds.NewCase(refunds.KindClosedPR, prNum, tx, tx.Amount)
+
+	recorded, err := e.refunds.Record(refundCase)
+	if err != nil || !recorded {
+		return err
+	}
+
+	log.WithFields(log.Fields{
+		"pr_num": prNum,
+		"case":   refundCase.ID,
+	}).Info("Payment for closed pull request detected")
+
+	text := substituteDynamicContent(e.repo.Message.PaidClosedPR, &contentParams{
+		PaidExplorerLink: tx.ExplorerLink,
+		Moderators:       e.repo.UserAccess.Moderators,
+		Refund:           refundCase,
+	})
+
+	return e.github.CreateCommentOnPullRequest(ctx, owner, repo, text, prNum)
+}
+
+func (e Handler) recordWrongMemo(tx *blockchain.Tx) error {
+	refundCase := refunds.NewCase(refunds.KindWrongMemo, 0, tx, tx.Amount)
+
+	recorded, err := e.refunds.Record(refundCase)
+	if err != nil || !recorded {
+		return err
+	}
+
+	log.WithFields(log.Fields{
+		"case": refundCase.ID,
+		"memo": tx.Memo,
+	}).Info("Payment with a wrong memo detected")
+
+	return nil
+}
+
+// handleRefundCommand handles "/refund <case id or tx hash> [dry-run]" comments from moderators.
+// Returns false if the comment is not a refund command.
+func (e Handler) handleRefundCommand(ctx context.Context, owner, repo string,
+	prNum int, user, body string,
+) (bool, error) {
+	args, ok := parseCommand(body, commandRefund)
+	if !ok {
+		return false, nil
+	}
+
+	if !e.isModerator(user) {
+		log.WithField("user", user).Warn("Refund command from non-moderator ignored")
+
+		return true, nil
+	}
+
+	if len(args) == 0 {
+		return true, e.github.CreateCommentOnPullRequest(ctx, owner, repo,
+			fmt.Sprintf("Usage: `%s <case id or tx hash> [%s]`", commandRefund, flagDryRun), prNum)
+	}
+
+	refundCase, err := e.refunds.Get(args[0])
+	if errors.Is(err, storage.ErrNotFound) {
+		return true, e.github.CreateCommentOnPullRequest(ctx, owner, repo,
+			fmt.Sprintf("Refund case `%s` not found.", args[0]), prNum)
+	}
+
+	if err != nil {
+		return true, err
+	}
+
+	if !refundCase.Refundable() {
+		return true, e.github.CreateCommentOnPullRequest(ctx, owner, repo,
+			fmt.Sprintf("Refund case `%s` is already %s.", refundCase.ID, refundCase.Status), prNum)
+	}
+
+	dryRun := config.Default.Payment.Refund.DryRun || (len(args) > 1 && args[1] == flagDryRun)
+
+	return true, e.refund(ctx, owner, repo, prNum, user, refundCase, dryRun)
+}
+
+func (e Handler) refund(ctx context.Context, owner, repo string,
+	prNum int, user string, refundCase *refunds.Case, dryRun bool,
+) error {
+	if dryRun {
+		if err := e.refunds.AddAudit(refundCase.ID, user, "refund_dry_run", refundCase.FromAddress); err != nil {
+			return err
+		}
+
+		text := substituteDynamicContent(e.repo.Message.RefundDryRun, &contentParams{Refund: refundCase})
+
+		return e.github.CreateCommentOnPullRequest(ctx, owner, repo, text, prNum)
+	}
+
+	chain, err := e.chains.Get(refundCase.Chain)
+	if err != nil {
+		return err
+	}
+
+	refunded, err := e.refunds.Refund(refundCase.ID, user, func(c *refunds.Case) (string, error) {
+		return chain.Transfer(c.FromAddress, c.Token, c.Amount, fmt.Sprintf("refund %s", c.TxHash))
+	})
+	if errors.Is(err, refunds.ErrAlreadyProcessed) {
+		return e.github.CreateCommentOnPullRequest(ctx, owner, repo,
+			fmt.Sprintf("Refund case `%s` is already processed.", refundCase.ID), prNum)
+	}
+
+	if errors.Is(err, refunds.ErrRefundFailed) {
+		return e.github.CreateCommentOnPullRequest(ctx, owner, repo,
+			fmt.Sprintf("Refund of case `%s` failed: %s", refundCase.ID, err.Error()), prNum)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	text := substituteDynamicContent(e.repo.Message.Refunded, &contentParams{Refund: refunded})
+
+	return e.github.CreateCommentOnPullRequest(ctx, owner, repo, text, prNum)
+}
+
+func findPayment(params *PaymentsParams, chain, token string) *Payment {
+	for i := range params.Payments {
+		if params.Payments[i].Chain == chain && strings.EqualFold(params.Payments[i].Token, token) {
+			return &params.Payments[i]
+		}
+	}
+
+	return nil
+}
+
+// parseCommand returns arguments of a bot command if the comment starts with it.
+func parseCommand(body, command string) ([]string, bool) {
+	fields := strings.Fields(body)
+	if len(fields) == 0 || fields[0] != command {
+		return nil, false
+	}
+
+	return fields[1:], true
+}
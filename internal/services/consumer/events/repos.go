@@ -0,0 +1,64 @@
+package events
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// Handlers are event handlers of the repositories served by the bot.
+type Handlers map[string]*Handler
+
+// Add adds a handler of a repository.
+func (h Handlers) Add(eh *Handler) {
+	h[strings.ToLower(eh.repo.FullName())] = eh
+}
+
+// Get returns a handler of a repository. Owner and name are case-insensitive.
+func (h Handlers) Get(owner, repo string) (*Handler, bool) {
+	eh, ok := h[strings.ToLower(owner+"/"+repo)]
+
+	return eh, ok
+}
+
+// ForEach calls fn with a handler of every repository in name order. A failed repository
+// doesn't stop the others, the number of failed repositories is returned as an error.
+func (h Handlers) ForEach(fn func(eh *Handler, owner, repo string) error) error {
+	names := make([]string, 0, len(h))
+	for name := range h {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	var failed int
+
+	for _, name := range names {
+		eh := h[name]
+
+		if err := fn(eh, eh.repo.Owner, eh.repo.Name); err != nil {
+			log.WithError(err).WithField("repo", eh.repo.FullName()).Error("Repository check failed")
+
+			failed++
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d repositories failed", failed, len(h))
+	}
+
+	return nil
+}
+
+// Sweep runs a background sweep of every repository which has enough Github rate limit left.
+func (h Handlers) Sweep(name string, fn func(eh *Handler, owner, repo string) error) error {
+	return h.ForEach(func(eh *Handler, owner, repo string) error {
+		if !eh.shouldSweep(name) {
+			return nil
+		}
+
+		return fn(eh, owner, repo)
+	})
+}
@@ -0,0 +1,134 @@
+package events
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	gh "github.com/google/go-github/v38/github"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/trustwallet/assets-manager/internal/services/consumer/prstate"
+)
+
+const commandHistory = "/history"
+
+// transition performs an action once per state change of a pull request. The state is recorded
+// after the action succeeds, so a failed action is retried with the next event. Transitions of
+// a pull request are serialized, so concurrent events don't run the action twice.
+// The action returns details saved in the history. Returns false if the state can't be reached.
+func (e Handler) transition(prNum int, to prstate.State, event string, action func() (string, error)) (bool, error) {
+	unlock := e.states.Lock(prNum)
+	defer unlock()
+
+	st, err := e.states.Get(prNum)
+	if err != nil {
+		return false, err
+	}
+
+	if !st.CanTransition(to) {
+		log.WithFields(log.Fields{
+			"pr_num": prNum,
+			"state":  st.State,
+			"to":     to,
+		}).Debug("Pull request state transition skipped")
+
+		return false, nil
+	}
+
+	var details string
+	if action != nil {
+		if details, err = action(); err != nil {
+			return false, err
+		}
+	}
+
+	if _, err = e.states.Transition(prNum, to, event, details); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// getState returns a state of a pull request. A pull request opened before the state was tracked
+// gets its state derived once from reviews and labels.
+func (e Handler) getState(ctx context.Context, owner, repo string, pr *gh.PullRequest) (*prstate.PR, error) {
+	st, err := e.states.Get(pr.GetNumber())
+	if err != nil || st.State != prstate.StateUnknown {
+		return st, err
+	}
+
+	state := prstate.StatePaymentRequested
+	if e.hasReviewAlready(ctx, owner, repo, pr) || e.hasLabelAlready(ctx, owner, repo, pr) {
+		state = prstate.StatePaid
+	}
+
+	unlock := e.states.Lock(pr.GetNumber())
+	defer unlock()
+
+	// The state may have been set by a transition meanwhile, it's derived only if it's still unknown.
+	if st, err = e.states.Get(pr.GetNumber()); err != nil || st.State != prstate.StateUnknown {
+		return st, err
+	}
+
+	if _, err = e.states.Transition(pr.GetNumber(), state, "bootstrap", ""); err != nil {
+		return nil, err
+	}
+
+	return e.states.Get(pr.GetNumber())
+}
+
+func isPaymentExpected(st *prstate.PR) bool {
+	return st.Is(prstate.StatePaymentRequested, prstate.StateReminded)
+}
+
+func hasLabel(pr *gh.PullRequest, name string) bool {
+	for _, label := range pr.Labels {
+		if label.GetName() == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+// handleHistoryCommand handles "/history" comments from collaborators with the state history of the pull request.
+// Returns false if the comment is not a history command.
+func (e Handler) handleHistoryCommand(ctx context.Context, owner, repo string,
+	prNum int, user, body string,
+) (bool, error) {
+	if _, ok := parseCommand(body, commandHistory); !ok {
+		return false, nil
+	}
+
+	if !e.isCollaborator(user) && !e.isModerator(user) {
+		log.WithField("user", user).Warn("History command from non-collaborator ignored")
+
+		return true, nil
+	}
+
+	st, err := e.states.Get(prNum)
+	if err != nil {
+		return true, err
+	}
+
+	return true, e.github.CreateCommentOnPullRequest(ctx, owner, repo, formatHistory(st), prNum)
+}
+
+func formatHistory(st *prstate.PR) string {
+	if len(st.History) == 0 {
+		return "No state history for this PR."
+	}
+
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "State: **%s**\n\n| Time | From | To | Event | Details |\n|---|---|---|---|---|\n", st.State)
+
+	for _, t := range st.History {
+		fmt.Fprintf(&b, "| %s | %s | %s | %s | %s |\n",
+			t.Time.UTC().Format(time.RFC3339), t.From, t.To, t.Event, t.Details)
+	}
+
+	return b.String()
+}
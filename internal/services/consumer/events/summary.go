@@ -0,0 +1,111 @@
+package events
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/trustwallet/assets-manager/internal/config"
+)
+
+// Hidden markers of the summary comment. The bot keeps one summary comment per pull request
+// and edits it in place, previous results are kept collapsed under the current one.
+const (
+	summaryMarker      = "<!-- assets-manager:summary -->"
+	summaryHistoryMark = "<!-- assets-manager:history -->"
+	summaryEntryMarker = "<!-- assets-manager:entry -->"
+	summaryHistoryEnd  = "</details>"
+)
+
+// summaryCommentMax is the max length of a Github comment. Lengths are counted in bytes,
+// which are never less than characters counted by Github.
+const summaryCommentMax = 65536
+
+const summaryCommentTruncated = "\n\n_The summary is truncated, fix the reported problems to see the rest._"
+
+// updateSummary sets the check summary of a pull request. The summary comment of the bot is edited,
+// or created if there is none yet. An unchanged summary is not edited.
+func (e Handler) updateSummary(ctx context.Context, owner, repo string, prNum int, summary string) error {
+	comments, err := e.github.GetIssueCommentList(ctx, owner, repo, prNum)
+	if err = allowTruncated(err, prNum); err != nil {
+		return err
+	}
+
+	summary = truncateSummary(summary, summaryCommentMax-len(summaryMarker)-1, summaryCommentTruncated)
+
+	for _, comment := range comments {
+		if !e.isBot(comment.GetUser()) || !strings.HasPrefix(comment.GetBody(), summaryMarker) {
+			continue
+		}
+
+		current, history := parseSummary(comment.GetBody())
+		if current == summary {
+			return nil
+		}
+
+		history = append([]string{stampSummary(current, comment.GetUpdatedAt())}, history...)
+		text := renderSummary(summary, history, config.Default.Summary.MaxHistory, summaryCommentMax)
+
+		log.WithField("pr_num", prNum).Debug("Summary comment updated")
+
+		return e.github.EditComment(ctx, owner, repo, comment.GetID(), text)
+	}
+
+	text := renderSummary(summary, nil, config.Default.Summary.MaxHistory, summaryCommentMax)
+
+	return e.github.CreateCommentOnPullRequest(ctx, owner, repo, text, prNum)
+}
+
+// renderSummary returns a text of the summary comment with up to maxHistory previous results collapsed.
+// The oldest results are dropped until the text fits in maxLength, the summary is expected to fit alone.
+func renderSummary(summary string, history []string, maxHistory, maxLength int) string {
+	text := summaryMarker + "\n" + summary
+
+	if len(history) > maxHistory {
+		history = history[:maxHistory]
+	}
+
+	for ; len(history) > 0; history = history[:len(history)-1] {
+		var b strings.Builder
+
+		fmt.Fprintf(&b, "%s\n\n%s\n<details><summary>Previous checks (%d)</summary>\n", text, summaryHistoryMark, len(history))
+
+		for _, entry := range history {
+			fmt.Fprintf(&b, "\n%s\n%s\n", summaryEntryMarker, entry)
+		}
+
+		b.WriteString("\n" + summaryHistoryEnd)
+
+		if b.Len() <= maxLength {
+			return b.String()
+		}
+	}
+
+	return text
+}
+
+// parseSummary returns the current summary and previous results of a summary comment.
+func parseSummary(text string) (summary string, history []string) {
+	text = strings.TrimPrefix(text, summaryMarker+"\n")
+
+	parts := strings.SplitN(text, "\n\n"+summaryHistoryMark+"\n", 2)
+	if len(parts) < 2 {
+		return text, nil
+	}
+
+	body := strings.TrimSuffix(parts[1], "\n"+summaryHistoryEnd)
+
+	entries := strings.Split(body, "\n"+summaryEntryMarker+"\n")
+	for _, entry := range entries[1:] {
+		history = append(history, strings.TrimSuffix(entry, "\n"))
+	}
+
+	return parts[0], history
+}
+
+func stampSummary(summary string, at time.Time) string {
+	return fmt.Sprintf("**%s**\n\n%s", at.UTC().Format(time.RFC1123), summary)
+}
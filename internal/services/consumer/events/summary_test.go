@@ -0,0 +1,106 @@
+package events
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func Test_RenderSummary(t *testing.T) {
+	tests := []struct {
+		name       string
+		summary    string
+		history    []string
+		maxHistory int
+		want       string
+	}{
+		{
+			name:       "No history",
+			summary:    "PR Summary",
+			history:    nil,
+			maxHistory: 5,
+			want:       summaryMarker + "\nPR Summary",
+		},
+		{
+			name:       "History disabled",
+			summary:    "PR Summary",
+			history:    []string{"old"},
+			maxHistory: 0,
+			want:       summaryMarker + "\nPR Summary",
+		},
+		{
+			name:       "History capped",
+			summary:    "PR Summary",
+			history:    []string{"old 1", "old 2", "old 3"},
+			maxHistory: 2,
+			want: summaryMarker + "\nPR Summary\n\n" + summaryHistoryMark +
+				"\n<details><summary>Previous checks (2)</summary>\n" +
+				"\n" + summaryEntryMarker + "\nold 1\n" +
+				"\n" + summaryEntryMarker + "\nold 2\n" +
+				"\n</details>",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := renderSummary(tt.summary, tt.history, tt.maxHistory, summaryCommentMax); got != tt.want {
+				t.Errorf("renderSummary() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_RenderSummary_MaxLength(t *testing.T) {
+	entry := strings.Repeat("finding\n", 100)
+	history := []string{"newest\n" + entry, "older\n" + entry, "oldest\n" + entry}
+
+	full := renderSummary("PR Summary", history, 5, summaryCommentMax)
+	max := len(full) - 1
+
+	got := renderSummary("PR Summary", history, 5, max)
+	if len(got) > max {
+		t.Fatalf("renderSummary() length = %d, want up to %d", len(got), max)
+	}
+
+	summary, kept := parseSummary(got)
+	if summary != "PR Summary" || len(kept) != 2 || kept[0] != history[0] || kept[1] != history[1] {
+		t.Errorf("renderSummary() kept %q, %q, want the newest 2 results", summary, kept)
+	}
+
+	if got = renderSummary("PR Summary", history, 5, len(summaryMarker)+20); got != summaryMarker+"\nPR Summary" {
+		t.Errorf("renderSummary() with no room for history = %q", got)
+	}
+}
+
+func Test_ParseSummary(t *testing.T) {
+	tests := []struct {
+		name    string
+		summary string
+		history []string
+	}{
+		{name: "No history", summary: "PR Summary\n\n- file.json", history: nil},
+		{name: "One entry", summary: "PR Summary", history: []string{"**date**\n\nold"}},
+		{name: "Many entries", summary: "PR Summary\n\nmore", history: []string{"old 1\n\n- a", "old 2", "old 3"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			text := renderSummary(tt.summary, tt.history, len(tt.history), summaryCommentMax)
+
+			summary, history := parseSummary(text)
+			if summary != tt.summary {
+				t.Errorf("parseSummary() summary = %q, want %q", summary, tt.summary)
+			}
+			if !reflect.DeepEqual(history, tt.history) {
+				t.Errorf("parseSummary() history = %q, want %q", history, tt.history)
+			}
+		})
+	}
+}
+
+func Test_ParseSummary_NotSummary(t *testing.T) {
+	summary, history := parseSummary("Fee is PAID")
+	if !strings.HasPrefix(summary, "Fee is PAID") || history != nil {
+		t.Errorf("parseSummary() = %q, %q", summary, history)
+	}
+}
@@ -0,0 +1,303 @@
+package github
+
+import (
+	"bytes"
+	"container/list"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/trustwallet/assets-manager/internal/storage"
+)
+
+const (
+	CacheDriverMemory  = "memory"
+	CacheDriverStorage = "storage"
+
+	bucketCache = "github_cache"
+
+	// storeCachePruneEvery is the number of responses stored between prunes of the storage cache.
+	storeCachePruneEvery = 100
+)
+
+type (
+	// Cache keeps Github responses with validators for conditional requests.
+	Cache interface {
+		Get(key string) (*CachedResponse, bool)
+		Set(key string, resp *CachedResponse)
+	}
+
+	// CachedResponse is a response of a GET request with an ETag or Last-Modified validator.
+	CachedResponse struct {
+		ETag         string      `json:"etag"`
+		LastModified string      `json:"last_modified"`
+		Header       http.Header `json:"header"`
+		Body         []byte      `json:"body"`
+	}
+
+	// storedResponse is a response kept by StoreCache with the time it has been stored.
+	storedResponse struct {
+		CachedResponse
+		StoredAt time.Time `json:"stored_at"`
+	}
+)
+
+// NewCache returns a cache by driver name. An empty driver disables caching.
+// Entries of the storage cache expire after ttl.
+func NewCache(driver string, maxEntries int, ttl time.Duration, store storage.Store) (Cache, error) {
+	switch driver {
+	case "":
+		return nil, nil
+	case CacheDriverMemory:
+		return NewLRUCache(maxEntries), nil
+	case CacheDriverStorage:
+		return NewStoreCache(store, maxEntries, ttl), nil
+	default:
+		return nil, fmt.Errorf("unknown github cache driver: %s", driver)
+	}
+}
+
+// LRUCache keeps up to max entries in memory, evicting the least recently used ones.
+type LRUCache struct {
+	mu      sync.Mutex
+	max     int
+	order   *list.List
+	entries map[string]*lruEntry
+}
+
+// lruEntry is a cached response with its element in the usage order, which holds the key.
+type lruEntry struct {
+	resp    *CachedResponse
+	element *list.Element
+}
+
+func NewLRUCache(maxEntries int) *LRUCache {
+	return &LRUCache{
+		max:     maxEntries,
+		order:   list.New(),
+		entries: make(map[string]*lruEntry),
+	}
+}
+
+func (c *LRUCache) Get(key string) (*CachedResponse, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+
+	c.order.MoveToFront(entry.element)
+
+	return entry.resp, true
+}
+
+func (c *LRUCache) Set(key string, resp *CachedResponse) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if entry, ok := c.entries[key]; ok {
+		entry.resp = resp
+		c.order.MoveToFront(entry.element)
+
+		return
+	}
+
+	c.entries[key] = &lruEntry{resp: resp, element: c.order.PushFront(key)}
+
+	for c.max > 0 && c.order.Len() > c.max {
+		if oldest, ok := c.order.Remove(c.order.Back()).(string); ok {
+			delete(c.entries, oldest)
+		}
+	}
+}
+
+// StoreCache keeps entries in the storage, so they survive restarts. Entries expire after a TTL,
+// expired ones and the oldest ones over max entries are pruned every storeCachePruneEvery stored responses.
+type StoreCache struct {
+	store      storage.Store
+	maxEntries int
+	ttl        time.Duration
+
+	mu   sync.Mutex
+	sets int
+	now  func() time.Time
+}
+
+func NewStoreCache(store storage.Store, maxEntries int, ttl time.Duration) *StoreCache {
+	return &StoreCache{store: store, maxEntries: maxEntries, ttl: ttl, now: time.Now}
+}
+
+func (c *StoreCache) Get(key string) (*CachedResponse, bool) {
+	var resp storedResponse
+	if err := c.store.Get(bucketCache, key, &resp); err != nil || c.expired(&resp) {
+		return nil, false
+	}
+
+	return &resp.CachedResponse, true
+}
+
+func (c *StoreCache) Set(key string, resp *CachedResponse) {
+	if err := c.store.Put(bucketCache, key, &storedResponse{CachedResponse: *resp, StoredAt: c.now()}); err != nil {
+		log.WithError(err).Warn("Failed to cache Github response")
+	}
+
+	c.mu.Lock()
+	c.sets++
+	prune := c.sets%storeCachePruneEvery == 0
+	c.mu.Unlock()
+
+	if !prune {
+		return
+	}
+
+	if err := c.Prune(); err != nil {
+		log.WithError(err).Warn("Failed to prune Github cache")
+	}
+}
+
+// Prune deletes expired entries and the oldest entries over max entries.
+func (c *StoreCache) Prune() error {
+	type entry struct {
+		key      string
+		storedAt time.Time
+	}
+
+	entries := make([]entry, 0)
+	expired := make([]string, 0)
+
+	err := c.store.ForEach(bucketCache, func(key string, value []byte) error {
+		var resp storedResponse
+		if err := json.Unmarshal(value, &resp); err != nil || c.expired(&resp) {
+			expired = append(expired, key)
+
+			return nil
+		}
+
+		entries = append(entries, entry{key: key, storedAt: resp.StoredAt})
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if c.maxEntries > 0 && len(entries) > c.maxEntries {
+		sort.Slice(entries, func(i, j int) bool { return entries[i].storedAt.Before(entries[j].storedAt) })
+
+		for _, e := range entries[:len(entries)-c.maxEntries] {
+			expired = append(expired, e.key)
+		}
+	}
+
+	for _, key := range expired {
+		if err = c.store.Delete(bucketCache, key); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (c *StoreCache) expired(resp *storedResponse) bool {
+	return c.ttl > 0 && c.now().Sub(resp.StoredAt) > c.ttl
+}
+
+// cacheTransport makes conditional GET requests for cached responses. A 304 Not Modified response,
+// which doesn't count against the rate limit, is replaced by the cached one.
+type cacheTransport struct {
+	base           http.RoundTripper
+	installationID int64
+	cache          Cache
+}
+
+func (t *cacheTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Method != http.MethodGet || req.Header.Get("Range") != "" {
+		return t.base.RoundTrip(req)
+	}
+
+	key := t.key(req)
+
+	cached, ok := t.cache.Get(key)
+	if ok {
+		req = req.Clone(req.Context())
+
+		if cached.ETag != "" {
+			req.Header.Set("If-None-Match", cached.ETag)
+		}
+
+		if cached.LastModified != "" {
+			req.Header.Set("If-Modified-Since", cached.LastModified)
+		}
+	}
+
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if ok && resp.StatusCode == http.StatusNotModified {
+		return fromCache(resp, cached), nil
+	}
+
+	etag, lastModified := resp.Header.Get("ETag"), resp.Header.Get("Last-Modified")
+	if resp.StatusCode != http.StatusOK || (etag == "" && lastModified == "") {
+		return resp, nil
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if closeErr := resp.Body.Close(); err == nil {
+		err = closeErr
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	t.cache.Set(key, &CachedResponse{
+		ETag:         etag,
+		LastModified: lastModified,
+		Header:       resp.Header.Clone(),
+		Body:         body,
+	})
+
+	return resp, nil
+}
+
+// key is unique per installation, since installations see different data.
+func (t *cacheTransport) key(req *http.Request) string {
+	return strconv.FormatInt(t.installationID, 10) + " " + req.Header.Get("Accept") + " " + req.URL.String()
+}
+
+// fromCache returns a cached response for a 304 one. Headers of the 304 response, e.g. rate limits,
+// replace the cached ones.
+func fromCache(notModified *http.Response, cached *CachedResponse) *http.Response {
+	discard(notModified.Body)
+
+	header := cached.Header.Clone()
+	for k, v := range notModified.Header {
+		header[k] = v
+	}
+
+	return &http.Response{
+		Status:        "200 OK",
+		StatusCode:    http.StatusOK,
+		Proto:         notModified.Proto,
+		ProtoMajor:    notModified.ProtoMajor,
+		ProtoMinor:    notModified.ProtoMinor,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(cached.Body)),
+		ContentLength: int64(len(cached.Body)),
+		Request:       notModified.Request,
+	}
+}
@@ -0,0 +1,125 @@
+package github
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/trustwallet/assets-manager/internal/storage"
+)
+
+func TestCacheTransport(t *testing.T) {
+	var requests, notModified int
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+
+		w.Header().Set(headerRateRemaining, "4990")
+
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			notModified++
+
+			w.WriteHeader(http.StatusNotModified)
+
+			return
+		}
+
+		w.Header().Set("ETag", `"v1"`)
+		w.Header().Set(headerRateRemaining, "4999")
+		_, _ = w.Write([]byte(`{"number":1}`))
+	}))
+	defer server.Close()
+
+	caches := map[string]Cache{
+		CacheDriverMemory:  NewLRUCache(10),
+		CacheDriverStorage: NewStoreCache(storage.NewMemoryStore(), 10, time.Hour),
+	}
+
+	for name, cache := range caches {
+		t.Run(name, func(t *testing.T) {
+			requests, notModified = 0, 0
+			client := &http.Client{Transport: &cacheTransport{base: http.DefaultTransport, cache: cache}}
+
+			for i := 0; i < 2; i++ {
+				resp, err := client.Get(server.URL + "/repos/trustwallet/assets/pulls/1")
+				if err != nil {
+					t.Fatalf("Get() error = %v", err)
+				}
+
+				body, _ := io.ReadAll(resp.Body)
+				_ = resp.Body.Close()
+
+				if resp.StatusCode != http.StatusOK || string(body) != `{"number":1}` {
+					t.Fatalf("request %d = %d %s, want 200 with the body", i+1, resp.StatusCode, body)
+				}
+
+				if i == 1 && resp.Header.Get(headerRateRemaining) != "4990" {
+					t.Errorf("cached response rate = %s, want the fresh 4990", resp.Header.Get(headerRateRemaining))
+				}
+			}
+
+			if requests != 2 || notModified != 1 {
+				t.Errorf("requests = %d, not modified = %d, want 2 and 1", requests, notModified)
+			}
+		})
+	}
+}
+
+func TestLRUCache(t *testing.T) {
+	c := NewLRUCache(2)
+
+	c.Set("a", &CachedResponse{ETag: "a"})
+	c.Set("b", &CachedResponse{ETag: "b"})
+
+	if _, ok := c.Get("a"); !ok {
+		t.Fatal("Get(a) = not found")
+	}
+
+	c.Set("c", &CachedResponse{ETag: "c"})
+
+	if _, ok := c.Get("b"); ok {
+		t.Error("Get(b) found the least recently used entry, want evicted")
+	}
+
+	for _, key := range []string{"a", "c"} {
+		if resp, ok := c.Get(key); !ok || resp.ETag != key {
+			t.Errorf("Get(%s) = %v, %v, want cached", key, resp, ok)
+		}
+	}
+}
+
+func TestStoreCache_Prune(t *testing.T) {
+	now := time.Now()
+	c := NewStoreCache(storage.NewMemoryStore(), 2, time.Hour)
+	c.now = func() time.Time { return now }
+
+	c.Set("expired", &CachedResponse{ETag: "expired"})
+
+	now = now.Add(2 * time.Hour)
+
+	if _, ok := c.Get("expired"); ok {
+		t.Error("Get(expired) = found")
+	}
+
+	for _, key := range []string{"a", "b", "c"} {
+		now = now.Add(time.Minute)
+		c.Set(key, &CachedResponse{ETag: key})
+	}
+
+	if err := c.Prune(); err != nil {
+		t.Fatalf("Prune() error = %v", err)
+	}
+
+	keys := make([]string, 0)
+	_ = c.store.ForEach(bucketCache, func(key string, _ []byte) error {
+		keys = append(keys, key)
+
+		return nil
+	})
+
+	if len(keys) != 2 || keys[0] != "b" || keys[1] != "c" {
+		t.Errorf("Prune() left %v, want [b c]", keys)
+	}
+}
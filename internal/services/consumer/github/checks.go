@@ -0,0 +1,42 @@
+package github
+
+import (
+	"context"
+
+	"github.com/google/go-github/v38/github"
+	"github.com/pkg/errors"
+)
+
+// CreateCheckRun creates a check run on a commit and returns its ID.
+func (c *Client) CreateCheckRun(
+	ctx context.Context, owner, repo string, opts github.CreateCheckRunOptions,
+) (int64, error) {
+	client, err := c.repoClient(ctx, owner, repo)
+	if err != nil {
+		return 0, err
+	}
+
+	run, _, err := client.Checks.CreateCheckRun(ctx, owner, repo, opts)
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to create check run")
+	}
+
+	return run.GetID(), nil
+}
+
+// UpdateCheckRun updates a check run by ID.
+func (c *Client) UpdateCheckRun(
+	ctx context.Context, owner, repo string, id int64, opts github.UpdateCheckRunOptions,
+) error {
+	client, err := c.repoClient(ctx, owner, repo)
+	if err != nil {
+		return err
+	}
+
+	_, _, err = client.Checks.UpdateCheckRun(ctx, owner, repo, id, opts)
+	if err != nil {
+		return errors.Wrap(err, "failed to update check run")
+	}
+
+	return nil
+}
@@ -0,0 +1,38 @@
+package github
+
+import (
+	"context"
+
+	"github.com/google/go-github/v38/github"
+	"github.com/pkg/errors"
+)
+
+// GetTree returns entries of a tree by SHA, not recursive. A commit SHA returns its root tree.
+func (c *Client) GetTree(ctx context.Context, owner, repo, sha string) (*github.Tree, error) {
+	client, err := c.repoClient(ctx, owner, repo)
+	if err != nil {
+		return nil, err
+	}
+
+	tree, _, err := client.Git.GetTree(ctx, owner, repo, sha, false)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get tree")
+	}
+
+	return tree, nil
+}
+
+// GetBlobRaw returns content of a blob by SHA.
+func (c *Client) GetBlobRaw(ctx context.Context, owner, repo, sha string) ([]byte, error) {
+	client, err := c.repoClient(ctx, owner, repo)
+	if err != nil {
+		return nil, err
+	}
+
+	blob, _, err := client.Git.GetBlobRaw(ctx, owner, repo, sha)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get blob")
+	}
+
+	return blob, nil
+}
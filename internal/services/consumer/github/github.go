@@ -3,6 +3,8 @@ package github
 import (
 	"context"
 	"net/http"
+	"strings"
+	"sync"
 
 	ghi "github.com/bradleyfalzon/ghinstallation"
 	"github.com/google/go-github/v38/github"
@@ -11,48 +13,160 @@ import (
 	"github.com/trustwallet/assets-manager/internal/config"
 )
 
+// Client works with Github API as the Github App. Requests for a repository are made by a client
+// of the app installation the repository belongs to.
 type Client struct {
-	client *github.Client
+	app        *github.Client
+	pagination Pagination
+	retry      RetryOptions
+	rates      *rateTracker
+	cache      Cache
+
+	mu            sync.Mutex
+	installations map[int64]*github.Client
+	repos         map[string]int64
 }
 
 // NewClient return an instance of Github for working with Github API.
-func NewClient() (*Client, error) {
+// Rate limits of app installations are reported to the observer, if any.
+// Responses are revalidated with conditional requests if a cache is given.
+func NewClient(observer RateObserver, cache Cache) (*Client, error) {
+	c := &Client{
+		pagination: Pagination{
+			PerPage:  config.Default.Github.Pagination.PerPage,
+			MaxPages: config.Default.Github.Pagination.MaxPages,
+		},
+		retry: RetryOptions{
+			MaxRetries: config.Default.Github.RateLimit.MaxRetries,
+			Backoff:    config.Default.Github.RateLimit.Backoff,
+			MaxBackoff: config.Default.Github.RateLimit.MaxBackoff,
+		},
+		rates:         newRateTracker(observer),
+		cache:         cache,
+		installations: make(map[int64]*github.Client),
+		repos:         make(map[string]int64),
+	}
+
 	tr, err := ghi.NewAppsTransport(http.DefaultTransport, config.Default.Github.AppID,
 		[]byte(config.Default.Github.AppPrivateKey))
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to create a transport without installation")
 	}
 
-	client, err := github.NewEnterpriseClient(config.Default.Github.APIURL,
-		config.Default.Github.APIURL, &http.Client{Transport: tr})
+	c.app, err = github.NewEnterpriseClient(config.Default.Github.APIURL,
+		config.Default.Github.APIURL, &http.Client{Transport: c.transport(0, tr)})
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to create a github client")
 	}
 
-	insts, _, err := client.Apps.ListInstallations(context.Background(), nil)
-	if err != nil {
-		return nil, errors.Wrap(err, "failed to get list of installations")
+	return c, nil
+}
+
+// Rate returns the last known rate limit of the app installation of a repository.
+// Returns false if no request has been made for the repository yet.
+func (c *Client) Rate(owner, repo string) (Rate, bool) {
+	c.mu.Lock()
+	installationID, ok := c.repos[repoKey(owner, repo)]
+	c.mu.Unlock()
+
+	if !ok {
+		return Rate{}, false
 	}
 
-	tr2, err := ghi.New(http.DefaultTransport, config.Default.Github.AppID,
-		insts[0].GetID(), []byte(config.Default.Github.AppPrivateKey))
+	return c.rates.get(installationID)
+}
+
+// SetInstallation sets an app installation of a repository, e.g. from a webhook event.
+// Zero installation ID is ignored.
+func (c *Client) SetInstallation(owner, repo string, installationID int64) {
+	if installationID == 0 {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.repos[repoKey(owner, repo)] = installationID
+}
+
+// repoClient returns a client of the app installation of a repository. An installation not set
+// by an event is looked up once via the API.
+func (c *Client) repoClient(ctx context.Context, owner, repo string) (*github.Client, error) {
+	c.mu.Lock()
+	installationID, ok := c.repos[repoKey(owner, repo)]
+	c.mu.Unlock()
+
+	if !ok {
+		inst, _, err := c.app.Apps.FindRepositoryInstallation(ctx, owner, repo)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to get installation of %s/%s", owner, repo)
+		}
+
+		installationID = inst.GetID()
+		c.SetInstallation(owner, repo, installationID)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if client, ok := c.installations[installationID]; ok {
+		return client, nil
+	}
+
+	tr, err := ghi.New(http.DefaultTransport, config.Default.Github.AppID,
+		installationID, []byte(config.Default.Github.AppPrivateKey))
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to create a transport with installation")
 	}
 
-	client, err = github.NewEnterpriseClient(config.Default.Github.APIURL,
-		config.Default.Github.APIURL, &http.Client{Transport: tr2})
+	client, err := github.NewEnterpriseClient(config.Default.Github.APIURL,
+		config.Default.Github.APIURL, &http.Client{Transport: c.transport(installationID, tr)})
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to create a github client")
 	}
 
-	return &Client{client: client}, nil
+	c.installations[installationID] = client
+
+	return client, nil
+}
+
+// transport wraps a transport of an app installation to serve unchanged responses from the cache,
+// track the rate limit and retry requests hit by a secondary rate limit.
+// The app itself has zero installation ID.
+func (c *Client) transport(installationID int64, base http.RoundTripper) http.RoundTripper {
+	if c.cache != nil {
+		base = &cacheTransport{base: base, installationID: installationID, cache: c.cache}
+	}
+
+	return &rateLimitTransport{
+		base:           base,
+		installationID: installationID,
+		tracker:        c.rates,
+		options:        c.retry,
+		sleep:          sleepContext,
+	}
+}
+
+func repoKey(owner, repo string) string {
+	return strings.ToLower(owner + "/" + repo)
 }
 
 // SetLabelOnPullRequqest sets a new label on a pull request if label does not exist.
 func (c *Client) SetLabelOnPullRequest(ctx context.Context, owner, repo string, prNum int, label *github.Label) error {
-	allLabels, _, err := c.client.Issues.ListLabels(ctx, owner, repo, nil)
+	client, err := c.repoClient(ctx, owner, repo)
 	if err != nil {
+		return err
+	}
+
+	allLabels := make([]*github.Label, 0)
+
+	err = c.listAll("labels", func(opts *github.ListOptions) (int, *github.Response, error) {
+		page, resp, err := client.Issues.ListLabels(ctx, owner, repo, opts)
+		allLabels = append(allLabels, page...)
+
+		return len(page), resp, err
+	})
+	if err != nil && !IsTruncated(err) {
 		return errors.Wrap(err, "failed to get labels list")
 	}
 
@@ -67,13 +181,13 @@ func (c *Client) SetLabelOnPullRequest(ctx context.Context, owner, repo string,
 	}
 
 	if !labelAlreadyExist {
-		_, _, err = c.client.Issues.CreateLabel(ctx, owner, repo, label)
+		_, _, err = client.Issues.CreateLabel(ctx, owner, repo, label)
 		if err != nil {
 			return errors.Wrap(err, "failed to create label")
 		}
 	}
 
-	_, _, err = c.client.Issues.AddLabelsToIssue(ctx, owner, repo, prNum, []string{*label.Name})
+	_, _, err = client.Issues.AddLabelsToIssue(ctx, owner, repo, prNum, []string{*label.Name})
 	if err != nil {
 		return errors.Wrap(err, "failed to add label")
 	}
@@ -81,11 +195,35 @@ func (c *Client) SetLabelOnPullRequest(ctx context.Context, owner, repo string,
 	return nil
 }
 
+// RemoveLabelFromPullRequest removes a label from a pull request if it is set.
+func (c *Client) RemoveLabelFromPullRequest(ctx context.Context, owner, repo string, prNum int, label string) error {
+	client, err := c.repoClient(ctx, owner, repo)
+	if err != nil {
+		return err
+	}
+
+	resp, err := client.Issues.RemoveLabelForIssue(ctx, owner, repo, prNum, label)
+	if err != nil {
+		if resp != nil && resp.StatusCode == http.StatusNotFound {
+			return nil
+		}
+
+		return errors.Wrap(err, "failed to remove label")
+	}
+
+	return nil
+}
+
 // CreateCommentOnPullRequest created a comment on a pull request.
 func (c *Client) CreateCommentOnPullRequest(ctx context.Context, owner, repo, text string, prNum int) error {
+	client, err := c.repoClient(ctx, owner, repo)
+	if err != nil {
+		return err
+	}
+
 	newComment := &github.IssueComment{Body: github.String(text)}
 
-	_, _, err := c.client.Issues.CreateComment(ctx, owner, repo, prNum, newComment)
+	_, _, err = client.Issues.CreateComment(ctx, owner, repo, prNum, newComment)
 	if err != nil {
 		return errors.Wrap(err, "failed to create comment")
 	}
@@ -93,9 +231,56 @@ func (c *Client) CreateCommentOnPullRequest(ctx context.Context, owner, repo, te
 	return nil
 }
 
+// GetIssueCommentList returns comments of an issue/pull request by number.
+// A list truncated at the page limit is returned with ErrTruncated.
+func (c *Client) GetIssueCommentList(
+	ctx context.Context, owner, repo string, prNum int,
+) ([]*github.IssueComment, error) {
+	client, err := c.repoClient(ctx, owner, repo)
+	if err != nil {
+		return nil, err
+	}
+
+	list := make([]*github.IssueComment, 0)
+
+	err = c.listAll("issue comments", func(opts *github.ListOptions) (int, *github.Response, error) {
+		page, resp, err := client.Issues.ListComments(ctx, owner, repo, prNum, &github.IssueListCommentsOptions{
+			ListOptions: *opts,
+		})
+		list = append(list, page...)
+
+		return len(page), resp, err
+	})
+	if err != nil {
+		return list, errors.Wrap(err, "failed to get comments list")
+	}
+
+	return list, nil
+}
+
+// EditComment replaces the text of a comment in issue/pull request.
+func (c *Client) EditComment(ctx context.Context, owner, repo string, commentID int64, text string) error {
+	client, err := c.repoClient(ctx, owner, repo)
+	if err != nil {
+		return err
+	}
+
+	_, _, err = client.Issues.EditComment(ctx, owner, repo, commentID, &github.IssueComment{Body: github.String(text)})
+	if err != nil {
+		return errors.Wrap(err, "failed to edit comment")
+	}
+
+	return nil
+}
+
 // DeleteCommentInIssue deletes a comment in issue/pull request.
 func (c *Client) DeleteCommentInIssue(ctx context.Context, owner, repo string, commentID int64) error {
-	_, err := c.client.Issues.DeleteComment(ctx, owner, repo, commentID)
+	client, err := c.repoClient(ctx, owner, repo)
+	if err != nil {
+		return err
+	}
+
+	_, err = client.Issues.DeleteComment(ctx, owner, repo, commentID)
 	if err != nil {
 		return errors.Wrap(err, "failed to delete comment")
 	}
@@ -105,7 +290,12 @@ func (c *Client) DeleteCommentInIssue(ctx context.Context, owner, repo string, c
 
 // GetPullRequest return a pull request by number.
 func (c *Client) GetPullRequest(ctx context.Context, owner, repo string, prNum int) (*github.PullRequest, error) {
-	pr, _, err := c.client.PullRequests.Get(ctx, owner, repo, prNum)
+	client, err := c.repoClient(ctx, owner, repo)
+	if err != nil {
+		return nil, err
+	}
+
+	pr, _, err := client.PullRequests.Get(ctx, owner, repo, prNum)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to get a pull request")
 	}
@@ -114,22 +304,48 @@ func (c *Client) GetPullRequest(ctx context.Context, owner, repo string, prNum i
 }
 
 // GetPullRequestReviewList returns pull request reviews by number.
+// A list truncated at the page limit is returned with ErrTruncated.
 func (c *Client) GetPullRequestReviewList(
 	ctx context.Context, owner, repo string, prNum int,
 ) ([]*github.PullRequestReview, error) {
-	list, _, err := c.client.PullRequests.ListReviews(ctx, owner, repo, prNum, nil)
+	client, err := c.repoClient(ctx, owner, repo)
 	if err != nil {
-		return nil, errors.Wrap(err, "failed to get reviews list")
+		return nil, err
+	}
+
+	list := make([]*github.PullRequestReview, 0)
+
+	err = c.listAll("reviews", func(opts *github.ListOptions) (int, *github.Response, error) {
+		page, resp, err := client.PullRequests.ListReviews(ctx, owner, repo, prNum, opts)
+		list = append(list, page...)
+
+		return len(page), resp, err
+	})
+	if err != nil {
+		return list, errors.Wrap(err, "failed to get reviews list")
 	}
 
 	return list, nil
 }
 
 // GetIssueListLabels returns labels list of an issue/pull request by number.
+// A list truncated at the page limit is returned with ErrTruncated.
 func (c *Client) GetIssueListLabels(ctx context.Context, owner, repo string, prNum int) ([]*github.Label, error) {
-	list, _, err := c.client.Issues.ListLabelsByIssue(ctx, owner, repo, prNum, nil)
+	client, err := c.repoClient(ctx, owner, repo)
 	if err != nil {
-		return nil, errors.Wrap(err, "failed to get labels list by issue")
+		return nil, err
+	}
+
+	list := make([]*github.Label, 0)
+
+	err = c.listAll("issue labels", func(opts *github.ListOptions) (int, *github.Response, error) {
+		page, resp, err := client.Issues.ListLabelsByIssue(ctx, owner, repo, prNum, opts)
+		list = append(list, page...)
+
+		return len(page), resp, err
+	})
+	if err != nil {
+		return list, errors.Wrap(err, "failed to get labels list by issue")
 	}
 
 	return list, nil
@@ -139,7 +355,12 @@ func (c *Client) GetIssueListLabels(ctx context.Context, owner, repo string, prN
 func (c *Client) CreateReview(
 	ctx context.Context, owner, repo, body, event string, prNum int,
 ) (*github.PullRequestReview, error) {
-	prReview, _, err := c.client.PullRequests.CreateReview(ctx, owner, repo, prNum,
+	client, err := c.repoClient(ctx, owner, repo)
+	if err != nil {
+		return nil, err
+	}
+
+	prReview, _, err := client.PullRequests.CreateReview(ctx, owner, repo, prNum,
 		&github.PullRequestReviewRequest{
 			Body:  &body,
 			Event: &event,
@@ -155,7 +376,12 @@ func (c *Client) CreateReview(
 func (c *Client) AddAssignees(
 	ctx context.Context, owner, repo string, prNum int, assignees []string,
 ) (*github.Issue, error) {
-	issue, _, err := c.client.Issues.AddAssignees(ctx, owner, repo, prNum, assignees)
+	client, err := c.repoClient(ctx, owner, repo)
+	if err != nil {
+		return nil, err
+	}
+
+	issue, _, err := client.Issues.AddAssignees(ctx, owner, repo, prNum, assignees)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to add assignees")
 	}
@@ -165,7 +391,12 @@ func (c *Client) AddAssignees(
 
 // ClosePullRequest closes a pull request by number.
 func (c *Client) ClosePullRequest(ctx context.Context, owner, repo string, prNum int) error {
-	_, _, err := c.client.PullRequests.Edit(ctx, owner, repo, prNum, &github.PullRequest{
+	client, err := c.repoClient(ctx, owner, repo)
+	if err != nil {
+		return err
+	}
+
+	_, _, err = client.PullRequests.Edit(ctx, owner, repo, prNum, &github.PullRequest{
 		State: github.String("closed"),
 	})
 	if err != nil {
@@ -175,33 +406,52 @@ func (c *Client) ClosePullRequest(ctx context.Context, owner, repo string, prNum
 	return nil
 }
 
-// GetOpenPullRequestsList returns a pull request list of repository.
-func (c *Client) GetPullRequestsList(
-	ctx context.Context, owner, repo, state string, perpage int,
-) ([]*github.PullRequest, error) {
-	pr, _, err := c.client.PullRequests.List(ctx, owner, repo, &github.PullRequestListOptions{
-		State:       state,
-		ListOptions: github.ListOptions{PerPage: perpage},
+// GetPullRequestsList returns a pull request list of repository by state.
+// A list truncated at the page limit is returned with ErrTruncated.
+func (c *Client) GetPullRequestsList(ctx context.Context, owner, repo, state string) ([]*github.PullRequest, error) {
+	client, err := c.repoClient(ctx, owner, repo)
+	if err != nil {
+		return nil, err
+	}
+
+	list := make([]*github.PullRequest, 0)
+
+	err = c.listAll("pull requests", func(opts *github.ListOptions) (int, *github.Response, error) {
+		page, resp, err := client.PullRequests.List(ctx, owner, repo, &github.PullRequestListOptions{
+			State:       state,
+			ListOptions: *opts,
+		})
+		list = append(list, page...)
+
+		return len(page), resp, err
 	})
 	if err != nil {
-		return nil, errors.Wrap(err, "failed to get open pull requests list")
+		return list, errors.Wrap(err, "failed to get open pull requests list")
 	}
 
-	return pr, nil
+	return list, nil
 }
 
 // GetPullRequestFileList receives a pull request file list.
+// A list truncated at the page limit is returned with ErrTruncated.
 func (c *Client) GetPullRequestFileList(
-	ctx context.Context,
-	owner, repo string,
-	prNum int,
-	perpage int,
+	ctx context.Context, owner, repo string, prNum int,
 ) ([]*github.CommitFile, error) {
-	list, _, err := c.client.PullRequests.ListFiles(ctx, owner, repo, prNum, &github.ListOptions{
-		PerPage: perpage,
+	client, err := c.repoClient(ctx, owner, repo)
+	if err != nil {
+		return nil, err
+	}
+
+	list := make([]*github.CommitFile, 0)
+
+	err = c.listAll("pull request files", func(opts *github.ListOptions) (int, *github.Response, error) {
+		page, resp, err := client.PullRequests.ListFiles(ctx, owner, repo, prNum, opts)
+		list = append(list, page...)
+
+		return len(page), resp, err
 	})
 	if err != nil {
-		return nil, errors.Wrap(err, "failed to get pull request file list")
+		return list, errors.Wrap(err, "failed to get pull request file list")
 	}
 
 	return list, nil
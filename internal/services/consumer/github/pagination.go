@@ -0,0 +1,65 @@
+package github
+
+import (
+	"fmt"
+
+	"github.com/google/go-github/v38/github"
+	"github.com/pkg/errors"
+)
+
+// ErrTruncated is returned with a partial list when the page limit is reached before the last page.
+var ErrTruncated = errors.New("list truncated at the page limit")
+
+// Pagination limits list requests.
+type Pagination struct {
+	// PerPage is the number of items requested per page, 100 max.
+	PerPage int
+	// MaxPages is the max number of pages requested per list. Zero means no limit.
+	MaxPages int
+}
+
+// TruncatedError is a list which has more items than the page limit allows.
+type TruncatedError struct {
+	Resource string
+	Count    int
+}
+
+func (e *TruncatedError) Error() string {
+	return fmt.Sprintf("%s: %s after %d items", ErrTruncated, e.Resource, e.Count)
+}
+
+func (e *TruncatedError) Is(target error) bool {
+	return target == ErrTruncated
+}
+
+// IsTruncated reports if an error is a truncated list, so the received part can still be used.
+func IsTruncated(err error) bool {
+	return errors.Is(err, ErrTruncated)
+}
+
+// listAll requests pages of a list following Link headers until the last page or the page limit.
+// fetch requests a page and returns the number of items received.
+func (c *Client) listAll(resource string, fetch func(opts *github.ListOptions) (int, *github.Response, error)) error {
+	opts := &github.ListOptions{PerPage: c.pagination.PerPage}
+
+	var count int
+
+	for page := 1; ; page++ {
+		n, resp, err := fetch(opts)
+		if err != nil {
+			return err
+		}
+
+		count += n
+
+		if resp == nil || resp.NextPage == 0 {
+			return nil
+		}
+
+		if c.pagination.MaxPages > 0 && page >= c.pagination.MaxPages {
+			return &TruncatedError{Resource: resource, Count: count}
+		}
+
+		opts.Page = resp.NextPage
+	}
+}
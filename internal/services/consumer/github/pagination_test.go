@@ -0,0 +1,81 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"testing"
+
+	"github.com/google/go-github/v38/github"
+)
+
+// newTestClient returns a client of a fake API serving a list of 5 pull requests, 2 per page.
+func newTestClient(t *testing.T, maxPages int) *Client {
+	t.Helper()
+
+	const total, perPage = 5, 2
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+		if page == 0 {
+			page = 1
+		}
+
+		if (page-1)*perPage+perPage < total {
+			w.Header().Set("Link", fmt.Sprintf(`<http://%s%s?page=%d&per_page=%d>; rel="next"`,
+				r.Host, r.URL.Path, page+1, perPage))
+		}
+
+		var body string
+		for n := (page-1)*perPage + 1; n <= page*perPage && n <= total; n++ {
+			if body != "" {
+				body += ","
+			}
+
+			body += fmt.Sprintf(`{"number":%d}`, n)
+		}
+
+		fmt.Fprintf(w, "[%s]", body)
+	}))
+	t.Cleanup(server.Close)
+
+	client := github.NewClient(server.Client())
+	client.BaseURL, _ = url.Parse(server.URL + "/")
+
+	return &Client{
+		pagination:    Pagination{PerPage: perPage, MaxPages: maxPages},
+		installations: map[int64]*github.Client{1: client},
+		repos:         map[string]int64{repoKey("trustwallet", "assets"): 1},
+	}
+}
+
+func TestClient_GetPullRequestsList(t *testing.T) {
+	tests := []struct {
+		name          string
+		maxPages      int
+		wantCount     int
+		wantTruncated bool
+	}{
+		{name: "No page limit", maxPages: 0, wantCount: 5},
+		{name: "Limit above pages", maxPages: 3, wantCount: 5},
+		{name: "Limit hit", maxPages: 2, wantCount: 4, wantTruncated: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newTestClient(t, tt.maxPages)
+
+			prs, err := c.GetPullRequestsList(context.Background(), "trustwallet", "assets", "open")
+			if IsTruncated(err) != tt.wantTruncated || (err != nil && !tt.wantTruncated) {
+				t.Fatalf("GetPullRequestsList() error = %v, want truncated %v", err, tt.wantTruncated)
+			}
+
+			if len(prs) != tt.wantCount || prs[len(prs)-1].GetNumber() != tt.wantCount {
+				t.Errorf("GetPullRequestsList() returned %d pull requests, want %d", len(prs), tt.wantCount)
+			}
+		})
+	}
+}
@@ -0,0 +1,218 @@
+package github
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	headerRateLimit     = "X-RateLimit-Limit"
+	headerRateRemaining = "X-RateLimit-Remaining"
+	headerRateReset     = "X-RateLimit-Reset"
+	headerRetryAfter    = "Retry-After"
+)
+
+type (
+	// Rate is the state of the rate limit of an app installation from the last response.
+	Rate struct {
+		Limit     int
+		Remaining int
+		Reset     time.Time
+	}
+
+	// RetryOptions set how requests hit by a secondary rate limit are retried.
+	RetryOptions struct {
+		// MaxRetries is the number of retries of a request. Zero disables retries.
+		MaxRetries int
+		// Backoff is the delay before the first retry if the response has no Retry-After,
+		// doubled after every next one.
+		Backoff time.Duration
+		// MaxBackoff is the max delay before a retry, including Retry-After.
+		MaxBackoff time.Duration
+	}
+
+	// RateObserver receives rate limits of app installations, e.g. to expose them as metrics.
+	RateObserver interface {
+		SetGithubRateLimit(installationID int64, limit, remaining int)
+		IncCounterGithubRateLimited()
+	}
+)
+
+// Used returns the used part of the rate limit from 0 to 1.
+func (r Rate) Used() float64 {
+	if r.Limit <= 0 {
+		return 0
+	}
+
+	return 1 - float64(r.Remaining)/float64(r.Limit)
+}
+
+// rateTracker keeps the last rate limit of every app installation.
+type rateTracker struct {
+	mu       sync.Mutex
+	rates    map[int64]Rate
+	observer RateObserver
+}
+
+func newRateTracker(observer RateObserver) *rateTracker {
+	return &rateTracker{rates: make(map[int64]Rate), observer: observer}
+}
+
+func (t *rateTracker) get(installationID int64) (Rate, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	rate, ok := t.rates[installationID]
+
+	return rate, ok
+}
+
+// update saves a rate limit from response headers. Responses without them are ignored.
+func (t *rateTracker) update(installationID int64, header http.Header) {
+	limit, err := strconv.Atoi(header.Get(headerRateLimit))
+	if err != nil {
+		return
+	}
+
+	remaining, err := strconv.Atoi(header.Get(headerRateRemaining))
+	if err != nil {
+		return
+	}
+
+	reset, err := strconv.ParseInt(header.Get(headerRateReset), 10, 64)
+	if err != nil {
+		return
+	}
+
+	t.mu.Lock()
+	t.rates[installationID] = Rate{Limit: limit, Remaining: remaining, Reset: time.Unix(reset, 0)}
+	t.mu.Unlock()
+
+	if t.observer != nil {
+		t.observer.SetGithubRateLimit(installationID, limit, remaining)
+	}
+}
+
+// rateLimitTransport tracks rate limits of an app installation and retries requests
+// hit by a secondary rate limit.
+type rateLimitTransport struct {
+	base           http.RoundTripper
+	installationID int64
+	tracker        *rateTracker
+	options        RetryOptions
+	sleep          func(ctx context.Context, d time.Duration) error
+}
+
+func (t *rateLimitTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	for attempt := 0; ; attempt++ {
+		resp, err := t.base.RoundTrip(req)
+		if err != nil {
+			return nil, err
+		}
+
+		t.tracker.update(t.installationID, resp.Header)
+
+		delay, limited := t.retryDelay(resp, attempt)
+		if !limited {
+			return resp, nil
+		}
+
+		if t.tracker.observer != nil {
+			t.tracker.observer.IncCounterGithubRateLimited()
+		}
+
+		if attempt >= t.options.MaxRetries || (req.Body != nil && req.GetBody == nil) {
+			return resp, nil
+		}
+
+		log.WithFields(log.Fields{
+			"installation": t.installationID,
+			"url":          req.URL.Path,
+			"attempt":      attempt + 1,
+			"delay":        delay,
+		}).Warn("Github secondary rate limit hit, request will be retried")
+
+		discard(resp.Body)
+
+		if err = t.sleep(req.Context(), delay); err != nil {
+			return nil, err
+		}
+
+		if req, err = rewind(req); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// retryDelay returns a delay before retrying a response of a secondary rate limit: 429, or 403 with
+// Retry-After. Returns false for other responses, including an exhausted primary rate limit.
+func (t *rateLimitTransport) retryDelay(resp *http.Response, attempt int) (time.Duration, bool) {
+	retryAfter := resp.Header.Get(headerRetryAfter)
+
+	switch {
+	case resp.StatusCode == http.StatusTooManyRequests:
+	case resp.StatusCode == http.StatusForbidden && retryAfter != "":
+	default:
+		return 0, false
+	}
+
+	delay := t.options.Backoff
+	for i := 0; i < attempt && delay < t.options.MaxBackoff; i++ {
+		delay *= 2
+	}
+
+	if seconds, err := strconv.Atoi(retryAfter); err == nil {
+		delay = time.Duration(seconds) * time.Second
+	}
+
+	if t.options.MaxBackoff > 0 && delay > t.options.MaxBackoff {
+		delay = t.options.MaxBackoff
+	}
+
+	return delay, true
+}
+
+// rewind returns a copy of a request with the body to send it again.
+func rewind(req *http.Request) (*http.Request, error) {
+	clone := req.Clone(req.Context())
+
+	if req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return nil, err
+		}
+
+		clone.Body = body
+	}
+
+	return clone, nil
+}
+
+// discard reads and closes a body of a response which is not returned, so the connection can be reused.
+func discard(body io.ReadCloser) {
+	if _, err := io.Copy(io.Discard, body); err != nil {
+		log.WithError(err).Debug("Failed to read Github response body")
+	}
+
+	if err := body.Close(); err != nil {
+		log.WithError(err).Debug("Failed to close Github response body")
+	}
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,129 @@
+package github
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+type testObserver struct {
+	limit, remaining int
+	limited          int
+}
+
+func (o *testObserver) SetGithubRateLimit(_ int64, limit, remaining int) {
+	o.limit, o.remaining = limit, remaining
+}
+
+func (o *testObserver) IncCounterGithubRateLimited() {
+	o.limited++
+}
+
+func TestRateLimitTransport(t *testing.T) {
+	var requests int
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+
+		if body, _ := io.ReadAll(r.Body); string(body) != "comment" {
+			t.Errorf("request %d body = %q, want the original body", requests, body)
+		}
+
+		w.Header().Set(headerRateLimit, "5000")
+		w.Header().Set(headerRateRemaining, "4990")
+		w.Header().Set(headerRateReset, "1633089600")
+
+		switch requests {
+		case 1:
+			w.Header().Set(headerRetryAfter, "60")
+			w.WriteHeader(http.StatusForbidden)
+		case 2:
+			w.WriteHeader(http.StatusTooManyRequests)
+		default:
+			w.WriteHeader(http.StatusCreated)
+		}
+	}))
+	defer server.Close()
+
+	observer := &testObserver{}
+	delays := make([]time.Duration, 0)
+	tr := &rateLimitTransport{
+		base:           http.DefaultTransport,
+		installationID: 1,
+		tracker:        newRateTracker(observer),
+		options:        RetryOptions{MaxRetries: 3, Backoff: time.Second, MaxBackoff: 30 * time.Second},
+		sleep: func(_ context.Context, d time.Duration) error {
+			delays = append(delays, d)
+
+			return nil
+		},
+	}
+
+	req, err := http.NewRequest(http.MethodPost, server.URL, bytes.NewBufferString("comment"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resp, err := tr.RoundTrip(req)
+	if err != nil {
+		t.Fatalf("RoundTrip() error = %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated || requests != 3 {
+		t.Fatalf("RoundTrip() = %d after %d requests, want 201 after 3", resp.StatusCode, requests)
+	}
+
+	// Retry-After is capped by max backoff, a 429 without it waits for the backoff doubled once.
+	if len(delays) != 2 || delays[0] != 30*time.Second || delays[1] != 2*time.Second {
+		t.Errorf("RoundTrip() delays = %v, want [30s 2s]", delays)
+	}
+
+	rate, ok := tr.tracker.get(1)
+	if !ok || rate.Limit != 5000 || rate.Remaining != 4990 || !rate.Reset.Equal(time.Unix(1633089600, 0)) {
+		t.Errorf("tracked rate = %+v, want 4990 of 5000", rate)
+	}
+
+	if observer.limit != 5000 || observer.remaining != 4990 || observer.limited != 2 {
+		t.Errorf("observer = %+v, want the rate and 2 limited requests", observer)
+	}
+}
+
+func TestRateLimitTransport_PrimaryLimit(t *testing.T) {
+	var requests int
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+
+		w.Header().Set(headerRateLimit, "5000")
+		w.Header().Set(headerRateRemaining, "0")
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer server.Close()
+
+	tr := &rateLimitTransport{
+		base:    http.DefaultTransport,
+		tracker: newRateTracker(nil),
+		options: RetryOptions{MaxRetries: 3, Backoff: time.Second},
+		sleep:   func(context.Context, time.Duration) error { return nil },
+	}
+
+	req, err := http.NewRequest(http.MethodGet, server.URL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resp, err := tr.RoundTrip(req)
+	if err != nil {
+		t.Fatalf("RoundTrip() error = %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusForbidden || requests != 1 {
+		t.Errorf("RoundTrip() = %d after %d requests, want 403 without retries", resp.StatusCode, requests)
+	}
+}
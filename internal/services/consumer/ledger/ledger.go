@@ -0,0 +1,365 @@
+package ledger
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/trustwallet/assets-manager/internal/services/consumer/blockchain"
+	"github.com/trustwallet/assets-manager/internal/storage"
+)
+
+const (
+	bucketTxs    = "ledger_txs"
+	bucketByMemo = "ledger_txs_by_memo"
+	bucketCursor = "ledger_cursor"
+	bucketSeq    = "ledger_seq"
+	bucketBySeq  = "ledger_txs_by_seq"
+
+	// syncOverlap is subtracted from the cursor on every sync, so that transactions
+	// indexed by the API with a delay are not missed.
+	syncOverlap = 10 * time.Minute
+)
+
+type Options struct {
+	// History is how far back the first sync goes.
+	History time.Duration
+	// SyncWindow is the max time range requested from the API at once.
+	SyncWindow time.Duration
+	// SyncInterval is the min interval between two syncs.
+	SyncInterval time.Duration
+}
+
+// Ledger is a durable record of all incoming transfers to the payment addresses of all payment chains.
+// Every transfer is ingested once, indexed by memo and numbered in the order of ingestion per chain.
+type Ledger struct {
+	store   storage.Store
+	chains  []blockchain.PaymentChain
+	options Options
+
+	mu       sync.Mutex
+	lastSync time.Time
+	now      func() time.Time
+}
+
+// New returns a ledger of payment chains. The sync window must be positive, since a sync requests
+// the API window by window.
+func New(store storage.Store, chains []blockchain.PaymentChain, options Options) (*Ledger, error) {
+	if options.SyncWindow <= 0 {
+		return nil, fmt.Errorf("invalid ledger sync window %s, must be positive", options.SyncWindow)
+	}
+
+	sort.Slice(chains, func(i, j int) bool { return chains[i].Name() < chains[j].Name() })
+
+	return &Ledger{
+		store:   store,
+		chains:  chains,
+		options: options,
+		now:     time.Now,
+	}, nil
+}
+
+// SyncError is an error of a sync of some chains, the other chains are synced anyway.
+type SyncError struct {
+	// Chains are errors by names of the chains failed to sync.
+	Chains map[string]error
+}
+
+func (e *SyncError) Error() string {
+	names := make([]string, 0, len(e.Chains))
+	for name := range e.Chains {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	messages := make([]string, 0, len(names))
+	for _, name := range names {
+		messages = append(messages, e.Chains[name].Error())
+	}
+
+	return "failed to sync ledger: " + strings.Join(messages, "; ")
+}
+
+// Sync ingests transfers made since the last sync. Returns the number of new transfers.
+// A chain failed to sync doesn't stop the others, failures are returned as a SyncError
+// and retried on the next sync.
+func (l *Ledger) Sync() (int, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	if !l.lastSync.IsZero() && now.Sub(l.lastSync) < l.options.SyncInterval {
+		return 0, nil
+	}
+
+	var ingested int
+
+	failed := make(map[string]error)
+
+	for _, chain := range l.chains {
+		n, err := l.syncChain(chain, now)
+		if err != nil {
+			failed[chain.Name()] = err
+		}
+
+		ingested += n
+	}
+
+	l.lastSync = now
+
+	if ingested > 0 {
+		log.WithField("count", ingested).Debug("New payments ingested into the ledger")
+	}
+
+	if len(failed) > 0 {
+		return ingested, &SyncError{Chains: failed}
+	}
+
+	return ingested, nil
+}
+
+func (l *Ledger) syncChain(chain blockchain.PaymentChain, now time.Time) (int, error) {
+	cursor, err := l.getCursor(chain.Name())
+	if err != nil {
+		return 0, err
+	}
+
+	start := cursor.Add(-syncOverlap)
+	if cursor.IsZero() {
+		start = now.Add(-l.options.History)
+	}
+
+	var ingested int
+
+	for start.Before(now) {
+		end := start.Add(l.options.SyncWindow)
+		if end.After(now) {
+			end = now
+		}
+
+		txs, err := chain.GetIncomingTransfers(start.UnixMilli(), end.UnixMilli())
+		if err != nil {
+			return ingested, fmt.Errorf("failed to get %s transfers: %w", chain.Name(), err)
+		}
+
+		for i := range txs {
+			added, err := l.ingest(&txs[i])
+			if err != nil {
+				return ingested, err
+			}
+
+			if added {
+				ingested++
+			}
+		}
+
+		if err = l.store.Put(bucketCursor, chain.Name(), end.UnixMilli()); err != nil {
+			return ingested, fmt.Errorf("failed to save ledger cursor: %w", err)
+		}
+
+		start = end
+	}
+
+	return ingested, nil
+}
+
+// ingest records a transfer, indexes it by memo and numbers it in one transaction, so a recorded transfer
+// is always found.
+func (l *Ledger) ingest(tx *blockchain.Tx) (bool, error) {
+	key := txKey(tx.Chain, tx.Hash)
+	memoKey := txKey(tx.Chain, normalizeMemo(tx.Memo))
+
+	var added bool
+
+	err := l.store.Update(func(stx storage.Tx) error {
+		var existing blockchain.Tx
+
+		err := stx.Get(bucketTxs, key, &existing)
+		if err == nil {
+			return nil
+		}
+
+		if !errors.Is(err, storage.ErrNotFound) {
+			return fmt.Errorf("failed to read ledger transaction: %w", err)
+		}
+
+		var keys []string
+		if err = stx.Get(bucketByMemo, memoKey, &keys); err != nil && !errors.Is(err, storage.ErrNotFound) {
+			return fmt.Errorf("failed to read ledger memo index: %w", err)
+		}
+
+		if err = stx.Put(bucketTxs, key, tx); err != nil {
+			return fmt.Errorf("failed to save ledger transaction: %w", err)
+		}
+
+		if err = stx.Put(bucketByMemo, memoKey, append(keys, key)); err != nil {
+			return fmt.Errorf("failed to save ledger memo index: %w", err)
+		}
+
+		var seq uint64
+		if err = stx.Get(bucketSeq, tx.Chain, &seq); err != nil && !errors.Is(err, storage.ErrNotFound) {
+			return fmt.Errorf("failed to read ledger sequence: %w", err)
+		}
+
+		seq++
+
+		if err = stx.Put(bucketBySeq, seqKey(tx.Chain, seq), key); err != nil {
+			return fmt.Errorf("failed to save ledger sequence index: %w", err)
+		}
+
+		if err = stx.Put(bucketSeq, tx.Chain, seq); err != nil {
+			return fmt.Errorf("failed to save ledger sequence: %w", err)
+		}
+
+		added = true
+
+		return nil
+	})
+
+	return added, err
+}
+
+// GetTransactionsByMemo returns all ingested transfers of a chain with the given memo.
+func (l *Ledger) GetTransactionsByMemo(chain, memo string) ([]blockchain.Tx, error) {
+	keys, err := l.getKeysByMemo(txKey(chain, normalizeMemo(memo)))
+	if err != nil {
+		return nil, err
+	}
+
+	txs := make([]blockchain.Tx, 0, len(keys))
+
+	for _, key := range keys {
+		var tx blockchain.Tx
+		if err = l.store.Get(bucketTxs, key, &tx); err != nil {
+			return nil, fmt.Errorf("failed to read ledger transaction %s: %w", key, err)
+		}
+
+		txs = append(txs, tx)
+	}
+
+	return txs, nil
+}
+
+// ForEach calls fn for every ingested transfer.
+func (l *Ledger) ForEach(fn func(tx *blockchain.Tx) error) error {
+	return l.store.ForEach(bucketTxs, func(_ string, value []byte) error {
+		var tx blockchain.Tx
+		if err := json.Unmarshal(value, &tx); err != nil {
+			return fmt.Errorf("failed to decode ledger transaction: %w", err)
+		}
+
+		return fn(&tx)
+	})
+}
+
+// LastSeq returns the number of the last transfer of a chain ingested, 0 if none.
+func (l *Ledger) LastSeq(chain string) (uint64, error) {
+	var seq uint64
+
+	err := l.store.Get(bucketSeq, chain, &seq)
+	if err != nil && !errors.Is(err, storage.ErrNotFound) {
+		return 0, fmt.Errorf("failed to read ledger sequence: %w", err)
+	}
+
+	return seq, nil
+}
+
+// ForEachSince calls fn for every transfer of a chain ingested after the one numbered seq, in the order
+// of ingestion. Transfers ingested before the numbering was introduced are not numbered and never visited.
+func (l *Ledger) ForEachSince(chain string, seq uint64, fn func(seq uint64, tx *blockchain.Tx) error) error {
+	last, err := l.LastSeq(chain)
+	if err != nil {
+		return err
+	}
+
+	for seq < last {
+		seq++
+
+		var key string
+		if err = l.store.Get(bucketBySeq, seqKey(chain, seq), &key); err != nil {
+			return fmt.Errorf("failed to read ledger sequence index %d: %w", seq, err)
+		}
+
+		var tx blockchain.Tx
+		if err = l.store.Get(bucketTxs, key, &tx); err != nil {
+			return fmt.Errorf("failed to read ledger transaction %s: %w", key, err)
+		}
+
+		if err = fn(seq, &tx); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Get returns an ingested transfer by chain and hash.
+func (l *Ledger) Get(chain, hash string) (*blockchain.Tx, error) {
+	var tx blockchain.Tx
+	if err := l.store.Get(bucketTxs, txKey(chain, hash), &tx); err != nil {
+		return nil, fmt.Errorf("failed to read ledger transaction: %w", err)
+	}
+
+	return &tx, nil
+}
+
+// GetPaymentStatus returns a payment status calculated from ingested transfers of a chain
+// and extra transfers, e.g. confirmed manually.
+func (l *Ledger) GetPaymentStatus(
+	chain blockchain.PaymentChain, memo, token string, startTime, endTime int64, minAmount float64,
+	extra ...blockchain.Tx,
+) (*blockchain.PaymentStatus, error) {
+	txs, err := l.GetTransactionsByMemo(chain.Name(), memo)
+	if err != nil {
+		return nil, err
+	}
+
+	txs = append(txs, extra...)
+
+	return blockchain.GetTransfersPaymentStatus(chain, txs, memo, token, startTime, endTime, minAmount), nil
+}
+
+func (l *Ledger) getCursor(chain string) (time.Time, error) {
+	var millis int64
+
+	err := l.store.Get(bucketCursor, chain, &millis)
+	if errors.Is(err, storage.ErrNotFound) {
+		return time.Time{}, nil
+	}
+
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to read ledger cursor: %w", err)
+	}
+
+	return time.UnixMilli(millis), nil
+}
+
+func (l *Ledger) getKeysByMemo(memoKey string) ([]string, error) {
+	var keys []string
+
+	err := l.store.Get(bucketByMemo, memoKey, &keys)
+	if err != nil && !errors.Is(err, storage.ErrNotFound) {
+		return nil, fmt.Errorf("failed to read ledger memo index: %w", err)
+	}
+
+	return keys, nil
+}
+
+func txKey(chain, id string) string {
+	return chain + "/" + id
+}
+
+func seqKey(chain string, seq uint64) string {
+	return fmt.Sprintf("%s/%020d", chain, seq)
+}
+
+func normalizeMemo(memo string) string {
+	return strings.ToLower(memo)
+}
@@ -0,0 +1,326 @@
+package ledger
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/trustwallet/assets-manager/internal/services/consumer/blockchain"
+	"github.com/trustwallet/assets-manager/internal/storage"
+	"github.com/trustwallet/go-libs/blockchain/binance/api"
+)
+
+const (
+	testAddress = "bnb1address"
+	testChain   = "binance"
+)
+
+type fakeFetcher struct {
+	txs   []api.Tx
+	calls int
+	err   error
+}
+
+func (f *fakeFetcher) GetTransactions(
+	address string, startTime, endTime int64, offset, limit int,
+) (*api.TransactionsResponse, error) {
+	f.calls++
+
+	if f.err != nil {
+		return nil, f.err
+	}
+
+	matched := make([]api.Tx, 0)
+	for _, tx := range f.txs {
+		if (tx.ToAddr == address || tx.FromAddr == address) && tx.BlockTime >= startTime && tx.BlockTime <= endTime {
+			matched = append(matched, tx)
+		}
+	}
+
+	resp := &api.TransactionsResponse{Total: len(matched)}
+	if offset < len(matched) {
+		end := offset + limit
+		if end > len(matched) {
+			end = len(matched)
+		}
+
+		resp.Tx = matched[offset:end]
+	}
+
+	return resp, nil
+}
+
+func newTestChain(fetcher *fakeFetcher) *blockchain.BinanceChain {
+	return blockchain.NewBinanceChain(testChain, 714, testAddress, nil, fetcher)
+}
+
+func newTestLedger(t *testing.T, fetcher *fakeFetcher, now time.Time) *Ledger {
+	return mustNew(t, now, storage.NewMemoryStore(), []blockchain.PaymentChain{newTestChain(fetcher)}, Options{
+		History:    30 * 24 * time.Hour,
+		SyncWindow: 7 * 24 * time.Hour,
+	})
+}
+
+func mustNew(t *testing.T, now time.Time,
+	store storage.Store, chains []blockchain.PaymentChain, options Options,
+) *Ledger {
+	t.Helper()
+
+	l, err := New(store, chains, options)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	l.now = func() time.Time { return now }
+
+	return l
+}
+
+func transfer(hash, memo, asset string, amount float64, blockTime time.Time) api.Tx {
+	return api.Tx{
+		Hash:      hash,
+		Type:      "TRANSFER",
+		ToAddr:    testAddress,
+		FromAddr:  "bnb1sender",
+		Memo:      memo,
+		Asset:     asset,
+		Amount:    amount * 100000000,
+		BlockTime: blockTime.UnixMilli(),
+	}
+}
+
+func TestLedger_SyncPaginatesFullHistory(t *testing.T) {
+	now := time.Date(2021, 9, 30, 0, 0, 0, 0, time.UTC)
+
+	fetcher := &fakeFetcher{}
+	for i := 0; i < 250; i++ {
+		fetcher.txs = append(fetcher.txs,
+			transfer(fmt.Sprintf("hash%d", i), "100", "TWT-8C2", 100, now.Add(-time.Duration(i)*time.Minute)))
+	}
+
+	// Old payment, outside of the last 7 days.
+	fetcher.txs = append(fetcher.txs, transfer("old", "100", "TWT-8C2", 100, now.Add(-20*24*time.Hour)))
+	// Outgoing and non-transfer transactions must be ignored.
+	outgoing := transfer("out", "100", "TWT-8C2", 100, now.Add(-time.Hour))
+	outgoing.ToAddr, outgoing.FromAddr = "bnb1other", testAddress
+	fetcher.txs = append(fetcher.txs, outgoing)
+	burn := transfer("burn", "100", "TWT-8C2", 100, now.Add(-time.Hour))
+	burn.Type = "BURN_TOKEN"
+	fetcher.txs = append(fetcher.txs, burn)
+
+	l := newTestLedger(t, fetcher, now)
+
+	n, err := l.Sync()
+	if err != nil {
+		t.Fatalf("Sync() error = %v", err)
+	}
+
+	if n != 251 {
+		t.Errorf("Sync() ingested = %d, want 251", n)
+	}
+
+	txs, err := l.GetTransactionsByMemo(testChain, "100")
+	if err != nil {
+		t.Fatalf("GetTransactionsByMemo() error = %v", err)
+	}
+
+	if len(txs) != 251 {
+		t.Errorf("GetTransactionsByMemo() len = %d, want 251", len(txs))
+	}
+}
+
+func TestLedger_SyncIsIncremental(t *testing.T) {
+	now := time.Date(2021, 9, 30, 0, 0, 0, 0, time.UTC)
+
+	fetcher := &fakeFetcher{txs: []api.Tx{transfer("a", "1", "BNB", 5, now.Add(-time.Hour))}}
+	l := newTestLedger(t, fetcher, now)
+
+	if _, err := l.Sync(); err != nil {
+		t.Fatalf("Sync() error = %v", err)
+	}
+
+	now = now.Add(time.Hour)
+	l.now = func() time.Time { return now }
+	fetcher.txs = append(fetcher.txs, transfer("b", "1", "BNB", 5, now.Add(-time.Minute)))
+	fetcher.calls = 0
+
+	n, err := l.Sync()
+	if err != nil {
+		t.Fatalf("Sync() error = %v", err)
+	}
+
+	if n != 1 {
+		t.Errorf("Sync() ingested = %d, want 1", n)
+	}
+
+	if fetcher.calls != 1 {
+		t.Errorf("Sync() api calls = %d, want 1", fetcher.calls)
+	}
+}
+
+func TestLedger_GetPaymentStatus(t *testing.T) {
+	now := time.Date(2021, 9, 30, 0, 0, 0, 0, time.UTC)
+	created := now.Add(-10 * 24 * time.Hour)
+
+	fetcher := &fakeFetcher{txs: []api.Tx{
+		transfer("a", "42", "TWT-8C2", 400, created.Add(time.Hour)),
+		transfer("b", "42", "TWT-8C2", 300, created.Add(2*time.Hour)),
+		transfer("c", "42", "BNB", 5, created.Add(2*time.Hour)),
+		transfer("d", "43", "TWT-8C2", 700, created.Add(2*time.Hour)),
+		transfer("e", "42", "TWT-8C2", 700, created.Add(-time.Hour)),
+	}}
+
+	l := newTestLedger(t, fetcher, now)
+
+	if _, err := l.Sync(); err != nil {
+		t.Fatalf("Sync() error = %v", err)
+	}
+
+	ps, err := l.GetPaymentStatus(newTestChain(fetcher), "42", "TWT-8C2", created.UnixMilli(), now.UnixMilli(), 672)
+	if err != nil {
+		t.Fatalf("GetPaymentStatus() error = %v", err)
+	}
+
+	if !ps.Paid || ps.Amount != 700 || ps.Chain != testChain || len(ps.Transactions) != 2 {
+		t.Errorf("GetPaymentStatus() = %+v, want paid 700 in 2 transactions", ps)
+	}
+}
+
+// failingIndexStore fails writes of the memo index within transactions while fail is set.
+type failingIndexStore struct {
+	storage.Store
+	fail bool
+}
+
+func (s *failingIndexStore) Update(fn func(tx storage.Tx) error) error {
+	return s.Store.Update(func(tx storage.Tx) error {
+		return fn(&failingIndexTx{Tx: tx, store: s})
+	})
+}
+
+type failingIndexTx struct {
+	storage.Tx
+	store *failingIndexStore
+}
+
+func (t *failingIndexTx) Put(bucket, key string, v interface{}) error {
+	if t.store.fail && bucket == bucketByMemo {
+		return errors.New("disk is full")
+	}
+
+	return t.Tx.Put(bucket, key, v)
+}
+
+func TestLedger_IngestIsAtomic(t *testing.T) {
+	now := time.Date(2021, 9, 30, 0, 0, 0, 0, time.UTC)
+
+	fetcher := &fakeFetcher{txs: []api.Tx{transfer("a", "1", "BNB", 5, now.Add(-time.Hour))}}
+	store := &failingIndexStore{Store: storage.NewMemoryStore(), fail: true}
+
+	l := mustNew(t, now, store, []blockchain.PaymentChain{newTestChain(fetcher)}, Options{
+		History:    24 * time.Hour,
+		SyncWindow: 24 * time.Hour,
+	})
+
+	if _, err := l.Sync(); err == nil {
+		t.Fatal("Sync() error = nil, want the index error")
+	}
+
+	if _, err := l.Get(testChain, "a"); err == nil {
+		t.Error("Get() found a transaction recorded without its memo index")
+	}
+
+	store.fail = false
+
+	if n, err := l.Sync(); err != nil || n != 1 {
+		t.Fatalf("Sync() = %d, %v, want 1 transaction", n, err)
+	}
+
+	txs, err := l.GetTransactionsByMemo(testChain, "1")
+	if err != nil || len(txs) != 1 {
+		t.Errorf("GetTransactionsByMemo() = %v, %v, want the transaction", txs, err)
+	}
+}
+
+func TestLedger_SyncContinuesAfterFailedChain(t *testing.T) {
+	now := time.Date(2021, 9, 30, 0, 0, 0, 0, time.UTC)
+
+	broken := &fakeFetcher{err: errors.New("api is down")}
+	working := &fakeFetcher{txs: []api.Tx{transfer("a", "1", "BNB", 5, now.Add(-time.Hour))}}
+
+	// Chains are synced by name, the broken one goes first.
+	l := mustNew(t, now, storage.NewMemoryStore(), []blockchain.PaymentChain{
+		newTestChain(working),
+		blockchain.NewBinanceChain("a-broken", 714, testAddress, nil, broken),
+	}, Options{History: 24 * time.Hour, SyncWindow: 24 * time.Hour, SyncInterval: time.Minute})
+
+	n, err := l.Sync()
+
+	var syncErr *SyncError
+	if !errors.As(err, &syncErr) || len(syncErr.Chains) != 1 || syncErr.Chains["a-broken"] == nil {
+		t.Fatalf("Sync() error = %v, want a SyncError of the broken chain", err)
+	}
+
+	if n != 1 {
+		t.Errorf("Sync() ingested = %d, want 1 of the working chain", n)
+	}
+
+	// The failed chain is retried after the sync interval, not on every call.
+	broken.calls = 0
+
+	if _, err = l.Sync(); err != nil || broken.calls != 0 {
+		t.Errorf("Sync() = %v, api calls = %d, want no sync within the interval", err, broken.calls)
+	}
+}
+
+func TestNew_InvalidSyncWindow(t *testing.T) {
+	for _, window := range []time.Duration{0, -time.Hour} {
+		if _, err := New(storage.NewMemoryStore(), nil, Options{History: time.Hour, SyncWindow: window}); err == nil {
+			t.Errorf("New() with sync window %s error = nil", window)
+		}
+	}
+}
+
+func TestLedger_ForEachSince(t *testing.T) {
+	now := time.Date(2021, 9, 30, 0, 0, 0, 0, time.UTC)
+
+	fetcher := &fakeFetcher{txs: []api.Tx{
+		transfer("a", "1", "BNB", 5, now.Add(-2*time.Hour)),
+		transfer("b", "2", "BNB", 5, now.Add(-time.Hour)),
+	}}
+	l := newTestLedger(t, fetcher, now)
+
+	if _, err := l.Sync(); err != nil {
+		t.Fatalf("Sync() error = %v", err)
+	}
+
+	// A transfer indexed late is numbered after the ones ingested before, whatever its date.
+	now = now.Add(time.Hour)
+	l.now = func() time.Time { return now }
+	fetcher.txs = append(fetcher.txs, transfer("c", "3", "BNB", 5, now.Add(-65*time.Minute)))
+
+	if _, err := l.Sync(); err != nil {
+		t.Fatalf("Sync() error = %v", err)
+	}
+
+	last, err := l.LastSeq(testChain)
+	if err != nil || last != 3 {
+		t.Fatalf("LastSeq() = %d, %v, want 3", last, err)
+	}
+
+	visited := make([]string, 0)
+
+	err = l.ForEachSince(testChain, 2, func(seq uint64, tx *blockchain.Tx) error {
+		visited = append(visited, fmt.Sprintf("%d:%s", seq, tx.Hash))
+
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("ForEachSince() error = %v", err)
+	}
+
+	if len(visited) != 1 || visited[0] != "3:c" {
+		t.Errorf("ForEachSince() visited %v, want [3:c]", visited)
+	}
+}
@@ -1,6 +1,8 @@
 package metrics
 
 import (
+	"strconv"
+
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/collectors"
 
@@ -14,10 +16,14 @@ const (
 
 // Prometheus is a struct for prometheus metrics.
 type Prometheus struct {
-	PullRequestsOpen           prometheus.Gauge
-	PullRequestsToPay          prometheus.Gauge
+	PullRequestsOpen           *prometheus.GaugeVec
+	PullRequestsToPay          *prometheus.GaugeVec
 	CounterPullRequestsCreated prometheus.Counter
 	CounterPaymentsDetected    prometheus.Counter
+	CounterBurnsFailed         prometheus.Counter
+	GithubRateLimit            *prometheus.GaugeVec
+	GithubRateLimitRemaining   *prometheus.GaugeVec
+	CounterGithubRateLimited   prometheus.Counter
 }
 
 // NewPrometheus return an instance of Prometheus with registered metrics.
@@ -25,19 +31,21 @@ func NewPrometheus() *Prometheus {
 	constLabels := prometheus.Labels{"service": config.Default.ServiceName}
 
 	p := Prometheus{
-		PullRequestsOpen: prometheus.NewGauge(
+		PullRequestsOpen: prometheus.NewGaugeVec(
 			prometheus.GaugeOpts{
 				Name:        prometheus.BuildFQName(namespace, subsystem, "state_pull_requests_open"),
 				Help:        "Current number of open pull requests",
 				ConstLabels: constLabels,
 			},
+			[]string{"repo"},
 		),
-		PullRequestsToPay: prometheus.NewGauge(
+		PullRequestsToPay: prometheus.NewGaugeVec(
 			prometheus.GaugeOpts{
 				Name:        prometheus.BuildFQName(namespace, subsystem, "state_pull_requests_to_pay"),
 				Help:        "Current number of pull requests expecting a payment",
 				ConstLabels: constLabels,
 			},
+			[]string{"repo"},
 		),
 		CounterPullRequestsCreated: prometheus.NewCounter(
 			prometheus.CounterOpts{
@@ -53,6 +61,36 @@ func NewPrometheus() *Prometheus {
 				ConstLabels: constLabels,
 			},
 		),
+		CounterBurnsFailed: prometheus.NewCounter(
+			prometheus.CounterOpts{
+				Name:        prometheus.BuildFQName(namespace, subsystem, "action_burns_failed"),
+				Help:        "Number of burns failed after all attempts",
+				ConstLabels: constLabels,
+			},
+		),
+		GithubRateLimit: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name:        prometheus.BuildFQName(namespace, subsystem, "github_rate_limit"),
+				Help:        "Github API rate limit of an app installation",
+				ConstLabels: constLabels,
+			},
+			[]string{"installation"},
+		),
+		GithubRateLimitRemaining: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name:        prometheus.BuildFQName(namespace, subsystem, "github_rate_limit_remaining"),
+				Help:        "Github API requests left until the rate limit of an app installation resets",
+				ConstLabels: constLabels,
+			},
+			[]string{"installation"},
+		),
+		CounterGithubRateLimited: prometheus.NewCounter(
+			prometheus.CounterOpts{
+				Name:        prometheus.BuildFQName(namespace, subsystem, "action_github_rate_limited"),
+				Help:        "Number of Github API requests hit by a secondary rate limit",
+				ConstLabels: constLabels,
+			},
+		),
 	}
 
 	// Register metrics.
@@ -61,6 +99,10 @@ func NewPrometheus() *Prometheus {
 		p.PullRequestsToPay,
 		p.CounterPullRequestsCreated,
 		p.CounterPaymentsDetected,
+		p.CounterBurnsFailed,
+		p.GithubRateLimit,
+		p.GithubRateLimitRemaining,
+		p.CounterGithubRateLimited,
 	)
 
 	prometheus.DefaultRegisterer.Unregister(collectors.NewGoCollector())
@@ -69,12 +111,12 @@ func NewPrometheus() *Prometheus {
 	return &p
 }
 
-func (p *Prometheus) SetPullRequestsOpen(n int) {
-	p.PullRequestsOpen.Set(float64(n))
+func (p *Prometheus) SetPullRequestsOpen(repo string, n int) {
+	p.PullRequestsOpen.WithLabelValues(repo).Set(float64(n))
 }
 
-func (p *Prometheus) SetPullRequestsToPay(n int) {
-	p.PullRequestsToPay.Set(float64(n))
+func (p *Prometheus) SetPullRequestsToPay(repo string, n int) {
+	p.PullRequestsToPay.WithLabelValues(repo).Set(float64(n))
 }
 
 func (p *Prometheus) IncCounterPullRequestsCreated() {
@@ -84,3 +126,18 @@ func (p *Prometheus) IncCounterPullRequestsCreated() {
 func (p *Prometheus) IncCounterPaymentsDetected() {
 	p.CounterPaymentsDetected.Inc()
 }
+
+func (p *Prometheus) IncCounterBurnsFailed() {
+	p.CounterBurnsFailed.Inc()
+}
+
+func (p *Prometheus) SetGithubRateLimit(installationID int64, limit, remaining int) {
+	installation := strconv.FormatInt(installationID, 10)
+
+	p.GithubRateLimit.WithLabelValues(installation).Set(float64(limit))
+	p.GithubRateLimitRemaining.WithLabelValues(installation).Set(float64(remaining))
+}
+
+func (p *Prometheus) IncCounterGithubRateLimited() {
+	p.CounterGithubRateLimited.Inc()
+}
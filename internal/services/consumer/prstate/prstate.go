@@ -0,0 +1,221 @@
+package prstate
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/trustwallet/assets-manager/internal/storage"
+)
+
+const bucketStates = "pr_states"
+
+type State string
+
+const (
+	// StateUnknown is a state of a pull request not seen by the bot yet.
+	StateUnknown          State = ""
+	StateOpened           State = "opened"
+	StatePaymentRequested State = "payment_requested"
+	StateReminded         State = "reminded"
+	StatePaid             State = "paid"
+	StateBurned           State = "burned"
+	StateClosed           State = "closed"
+	StateMerged           State = "merged"
+)
+
+// transitions lists states a pull request can move to from each state.
+// Any state can be set on a pull request in an unknown state.
+var transitions = map[State][]State{ // nolint:gochecknoglobals // transition table
+	StateOpened:           {StatePaymentRequested, StatePaid, StateClosed, StateMerged},
+	StatePaymentRequested: {StateReminded, StatePaid, StateClosed, StateMerged},
+	StateReminded:         {StateReminded, StatePaid, StateClosed, StateMerged},
+	// A paid state set by the paid label goes back to payment requested if the label is removed.
+	StatePaid:   {StatePaymentRequested, StateBurned, StateClosed, StateMerged},
+	StateBurned: {StateClosed, StateMerged},
+	StateClosed: {StateOpened},
+	StateMerged: {},
+}
+
+var ErrInvalidTransition = errors.New("invalid pull request state transition")
+
+type (
+	// PR is a lifecycle state of a pull request with the history of transitions.
+	PR struct {
+		Number    int          `json:"number"`
+		State     State        `json:"state"`
+		UpdatedAt time.Time    `json:"updated_at"`
+		History   []Transition `json:"history"`
+		// Steps are names of actions done for the pull request, recorded one by one,
+		// so that an action of several side effects is resumed where it failed.
+		Steps []string `json:"steps,omitempty"`
+	}
+
+	// Transition is a change of a pull request state.
+	Transition struct {
+		From    State     `json:"from"`
+		To      State     `json:"to"`
+		Time    time.Time `json:"time"`
+		Event   string    `json:"event"`
+		Details string    `json:"details,omitempty"`
+	}
+)
+
+// Is reports if a pull request is in one of the states.
+func (pr *PR) Is(states ...State) bool {
+	for _, s := range states {
+		if pr.State == s {
+			return true
+		}
+	}
+
+	return false
+}
+
+// HasStep reports if a step has been recorded.
+func (pr *PR) HasStep(step string) bool {
+	for _, s := range pr.Steps {
+		if s == step {
+			return true
+		}
+	}
+
+	return false
+}
+
+// CanTransition reports if a pull request can move to a state. Moving to the current state
+// is allowed only for repeatable states (reminders), so that repeated events are no-ops.
+func (pr *PR) CanTransition(to State) bool {
+	if pr.State == StateUnknown {
+		return to != StateUnknown
+	}
+
+	for _, s := range transitions[pr.State] {
+		if s == to {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Machine stores lifecycle states of pull requests.
+type Machine struct {
+	store storage.Store
+	mu    sync.Mutex
+	now   func() time.Time
+
+	locksMu sync.Mutex
+	locks   map[int]*prLock
+}
+
+// prLock serializes state changes of a pull request, refs counts its holders and waiters.
+type prLock struct {
+	mu   sync.Mutex
+	refs int
+}
+
+func NewMachine(store storage.Store) *Machine {
+	return &Machine{store: store, now: time.Now, locks: make(map[int]*prLock)}
+}
+
+// Lock serializes state changes of a pull request within the process, so that an action done
+// on a transition runs once however many events of the pull request are handled concurrently.
+// Returns the unlock function.
+func (m *Machine) Lock(prNum int) func() {
+	m.locksMu.Lock()
+
+	l, ok := m.locks[prNum]
+	if !ok {
+		l = &prLock{}
+		m.locks[prNum] = l
+	}
+
+	l.refs++
+	m.locksMu.Unlock()
+
+	l.mu.Lock()
+
+	return func() {
+		l.mu.Unlock()
+
+		m.locksMu.Lock()
+		defer m.locksMu.Unlock()
+
+		if l.refs--; l.refs == 0 {
+			delete(m.locks, prNum)
+		}
+	}
+}
+
+// Get returns a state of a pull request. A pull request not seen before is in the unknown state.
+func (m *Machine) Get(prNum int) (*PR, error) {
+	var pr PR
+
+	err := m.store.Get(bucketStates, strconv.Itoa(prNum), &pr)
+	if errors.Is(err, storage.ErrNotFound) {
+		return &PR{Number: prNum, State: StateUnknown}, nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to read pull request state: %w", err)
+	}
+
+	return &pr, nil
+}
+
+// Transition moves a pull request to a state. Returns false if the pull request is already in the state,
+// and ErrInvalidTransition if the state can't be reached from the current one.
+func (m *Machine) Transition(prNum int, to State, event, details string) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	pr, err := m.Get(prNum)
+	if err != nil {
+		return false, err
+	}
+
+	if !pr.CanTransition(to) {
+		if pr.State == to {
+			return false, nil
+		}
+
+		return false, fmt.Errorf("%w: %s -> %s", ErrInvalidTransition, pr.State, to)
+	}
+
+	now := m.now()
+	pr.History = append(pr.History, Transition{From: pr.State, To: to, Time: now, Event: event, Details: details})
+	pr.State = to
+	pr.UpdatedAt = now
+
+	if err = m.store.Put(bucketStates, strconv.Itoa(prNum), pr); err != nil {
+		return false, fmt.Errorf("failed to save pull request state: %w", err)
+	}
+
+	return true, nil
+}
+
+// RecordStep records a step done for a pull request. A step recorded already is not repeated.
+func (m *Machine) RecordStep(prNum int, step string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	pr, err := m.Get(prNum)
+	if err != nil {
+		return err
+	}
+
+	if pr.HasStep(step) {
+		return nil
+	}
+
+	pr.Steps = append(pr.Steps, step)
+
+	if err = m.store.Put(bucketStates, strconv.Itoa(prNum), pr); err != nil {
+		return fmt.Errorf("failed to save pull request state: %w", err)
+	}
+
+	return nil
+}
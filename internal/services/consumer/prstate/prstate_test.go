@@ -0,0 +1,154 @@
+package prstate
+
+import (
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/trustwallet/assets-manager/internal/storage"
+)
+
+func TestMachine_Transition(t *testing.T) {
+	m := NewMachine(storage.NewMemoryStore())
+
+	steps := []struct {
+		to      State
+		changed bool
+		err     error
+	}{
+		{to: StateOpened, changed: true},
+		{to: StateOpened, changed: false},
+		{to: StatePaymentRequested, changed: true},
+		{to: StatePaymentRequested, changed: false},
+		{to: StateReminded, changed: true},
+		{to: StateReminded, changed: true},
+		{to: StateBurned, err: ErrInvalidTransition},
+		{to: StatePaid, changed: true},
+		{to: StateReminded, err: ErrInvalidTransition},
+		{to: StateBurned, changed: true},
+		{to: StateMerged, changed: true},
+		{to: StateOpened, err: ErrInvalidTransition},
+	}
+
+	for i, step := range steps {
+		changed, err := m.Transition(1, step.to, "test", "")
+		if !errors.Is(err, step.err) {
+			t.Fatalf("step %d: Transition(%s) error = %v, want %v", i, step.to, err, step.err)
+		}
+
+		if changed != step.changed {
+			t.Fatalf("step %d: Transition(%s) = %v, want %v", i, step.to, changed, step.changed)
+		}
+	}
+
+	pr, err := m.Get(1)
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+
+	if pr.State != StateMerged || len(pr.History) != 7 {
+		t.Errorf("Get() = %s with %d transitions, want %s with 7", pr.State, len(pr.History), StateMerged)
+	}
+
+	if pr.History[0].From != StateUnknown || pr.History[6].From != StateBurned {
+		t.Errorf("Get() history = %+v", pr.History)
+	}
+}
+
+func TestPR_CanTransition(t *testing.T) {
+	tests := []struct {
+		name  string
+		state State
+		to    State
+		want  bool
+	}{
+		{name: "Unknown to paid", state: StateUnknown, to: StatePaid, want: true},
+		{name: "Unknown to unknown", state: StateUnknown, to: StateUnknown, want: false},
+		{name: "Closed to opened", state: StateClosed, to: StateOpened, want: true},
+		{name: "Paid to paid", state: StatePaid, to: StatePaid, want: false},
+		{name: "Paid to payment requested", state: StatePaid, to: StatePaymentRequested, want: true},
+		{name: "Burned to payment requested", state: StateBurned, to: StatePaymentRequested, want: false},
+		{name: "Merged to closed", state: StateMerged, to: StateClosed, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pr := &PR{State: tt.state}
+			if got := pr.CanTransition(tt.to); got != tt.want {
+				t.Errorf("CanTransition() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMachine_Lock(t *testing.T) {
+	m := NewMachine(storage.NewMemoryStore())
+
+	var (
+		wg      sync.WaitGroup
+		mu      sync.Mutex
+		actions int
+	)
+
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			unlock := m.Lock(1)
+			defer unlock()
+
+			pr, err := m.Get(1)
+			if err != nil || !pr.CanTransition(StatePaid) {
+				return
+			}
+
+			mu.Lock()
+			actions++
+			mu.Unlock()
+
+			if _, err = m.Transition(1, StatePaid, "test", ""); err != nil {
+				t.Errorf("Transition() error = %v", err)
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	if actions != 1 {
+		t.Errorf("actions = %d, want 1", actions)
+	}
+
+	if len(m.locks) != 0 {
+		t.Errorf("locks = %d, want released", len(m.locks))
+	}
+}
+
+func TestMachine_RecordStep(t *testing.T) {
+	m := NewMachine(storage.NewMemoryStore())
+
+	if _, err := m.Transition(1, StatePaid, "test", ""); err != nil {
+		t.Fatalf("Transition() error = %v", err)
+	}
+
+	for _, step := range []string{"review", "label", "review"} {
+		if err := m.RecordStep(1, step); err != nil {
+			t.Fatalf("RecordStep(%s) error = %v", step, err)
+		}
+	}
+
+	pr, err := m.Get(1)
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+
+	if !reflect.DeepEqual(pr.Steps, []string{"review", "label"}) || pr.State != StatePaid {
+		t.Errorf("Get() = %s %v, want paid with steps review, label", pr.State, pr.Steps)
+	}
+
+	if !pr.HasStep("label") || pr.HasStep("assignees") {
+		t.Errorf("HasStep() of %v is wrong", pr.Steps)
+	}
+}
@@ -0,0 +1,203 @@
+package reconcile
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/trustwallet/assets-manager/internal/services/consumer/blockchain"
+	"github.com/trustwallet/assets-manager/internal/storage"
+)
+
+const (
+	bucketMatches     = "reconcile_matches"
+	bucketSuggestions = "reconcile_suggestions"
+
+	scoreLinkedSender = 0.5
+	scoreFeeAmount    = 0.3
+	scoreTimeMax      = 0.2
+)
+
+type (
+	// Candidate is an open pull request waiting for a payment.
+	Candidate struct {
+		PRNum     int
+		CreatedAt int64
+	}
+
+	// Fee is a payment option an unmatched amount is compared with.
+	Fee struct {
+		Chain     string
+		Token     string
+		MinAmount float64
+		Amount    float64
+	}
+
+	// Suggestion is a likely pull request for an unmatched payment.
+	Suggestion struct {
+		PRNum   int      `json:"pr_num"`
+		Score   float64  `json:"score"`
+		Reasons []string `json:"reasons"`
+	}
+
+	// Match is a payment confirmed by a moderator as a payment for a pull request.
+	Match struct {
+		CaseID    string        `json:"case_id"`
+		PRNum     int           `json:"pr_num"`
+		Tx        blockchain.Tx `json:"tx"`
+		Actor     string        `json:"actor"`
+		CreatedAt time.Time     `json:"created_at"`
+	}
+
+	Options struct {
+		// TimeWindow is the max time between PR creation and a payment for a time proximity score.
+		TimeWindow time.Duration
+		// MinScore is the min score of a suggestion.
+		MinScore float64
+		// MaxSuggestions is the max number of suggestions per payment.
+		MaxSuggestions int
+	}
+)
+
+// Suggest scores candidates for an unmatched payment by sender linked to the PR before,
+// amount equal to a fee and time proximity of the payment to the PR creation.
+func Suggest(tx *blockchain.Tx, candidates []Candidate, linkedPRs map[int]bool, fees []Fee,
+	options Options,
+) []Suggestion {
+	var feeMatched bool
+
+	for _, fee := range fees {
+		if fee.Chain == tx.Chain && strings.EqualFold(fee.Token, tx.Token) &&
+			tx.Amount >= fee.MinAmount && tx.Amount <= fee.Amount {
+			feeMatched = true
+		}
+	}
+
+	suggestions := make([]Suggestion, 0)
+
+	for _, c := range candidates {
+		var s Suggestion
+
+		if linkedPRs[c.PRNum] {
+			s.Score += scoreLinkedSender
+			s.Reasons = append(s.Reasons, "sender has paid for this PR before")
+		}
+
+		if feeMatched {
+			s.Score += scoreFeeAmount
+			s.Reasons = append(s.Reasons, "amount equals the fee")
+		}
+
+		dt := time.Duration(tx.Date-c.CreatedAt) * time.Millisecond
+		if options.TimeWindow > 0 && dt >= 0 && dt <= options.TimeWindow {
+			s.Score += scoreTimeMax * (1 - float64(dt)/float64(options.TimeWindow))
+			s.Reasons = append(s.Reasons, fmt.Sprintf("paid %s after PR creation", dt.Round(time.Minute)))
+		}
+
+		s.Score = math.Round(s.Score*100) / 100
+		if s.Score < options.MinScore {
+			continue
+		}
+
+		s.PRNum = c.PRNum
+		suggestions = append(suggestions, s)
+	}
+
+	sort.SliceStable(suggestions, func(i, j int) bool {
+		return suggestions[i].Score > suggestions[j].Score
+	})
+
+	if len(suggestions) > options.MaxSuggestions {
+		suggestions = suggestions[:options.MaxSuggestions]
+	}
+
+	return suggestions
+}
+
+// Store keeps suggestions made for unmatched payments and confirmed matches.
+type Store struct {
+	store storage.Store
+	now   func() time.Time
+}
+
+func NewStore(store storage.Store) *Store {
+	return &Store{store: store, now: time.Now}
+}
+
+// IsSuggested reports if a pull request has been already suggested for a payment.
+func (s *Store) IsSuggested(caseID string, prNum int) (bool, error) {
+	suggestions, err := s.GetSuggestions(caseID)
+	if err != nil {
+		return false, err
+	}
+
+	for _, suggestion := range suggestions {
+		if suggestion.PRNum == prNum {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// GetSuggestions returns suggestions made for a payment.
+func (s *Store) GetSuggestions(caseID string) ([]Suggestion, error) {
+	var suggestions []Suggestion
+
+	err := s.store.Get(bucketSuggestions, caseID, &suggestions)
+	if err != nil && !errors.Is(err, storage.ErrNotFound) {
+		return nil, fmt.Errorf("failed to read suggestions: %w", err)
+	}
+
+	return suggestions, nil
+}
+
+// AddSuggestion saves a suggestion made for a payment.
+func (s *Store) AddSuggestion(caseID string, suggestion Suggestion) error {
+	suggestions, err := s.GetSuggestions(caseID)
+	if err != nil {
+		return err
+	}
+
+	return s.store.Put(bucketSuggestions, caseID, append(suggestions, suggestion))
+}
+
+// AddMatch saves a payment confirmed for a pull request.
+func (s *Store) AddMatch(caseID string, prNum int, tx *blockchain.Tx, actor string) error {
+	return s.store.Put(bucketMatches, caseID, Match{
+		CaseID:    caseID,
+		PRNum:     prNum,
+		Tx:        *tx,
+		Actor:     actor,
+		CreatedAt: s.now(),
+	})
+}
+
+// GetMatchedTransfers returns payments of a chain confirmed for a pull request.
+// Their memo is replaced with the PR memo, so that they are counted as regular payments.
+func (s *Store) GetMatchedTransfers(chain string, prNum int, memo string) ([]blockchain.Tx, error) {
+	txs := make([]blockchain.Tx, 0)
+
+	err := s.store.ForEach(bucketMatches, func(_ string, value []byte) error {
+		var m Match
+		if err := json.Unmarshal(value, &m); err != nil {
+			return fmt.Errorf("failed to decode match: %w", err)
+		}
+
+		if m.PRNum == prNum && m.Tx.Chain == chain {
+			m.Tx.Memo = memo
+			txs = append(txs, m.Tx)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return txs, nil
+}
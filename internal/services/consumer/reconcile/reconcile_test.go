@@ -0,0 +1,95 @@
+package reconcile
+
+import (
+	"testing"
+	"time"
+
+	"github.com/trustwallet/assets-manager/internal/services/consumer/blockchain"
+	"github.com/trustwallet/assets-manager/internal/storage"
+)
+
+func TestSuggest(t *testing.T) {
+	created := time.Date(2021, 10, 1, 0, 0, 0, 0, time.UTC)
+	candidates := []Candidate{
+		{PRNum: 1, CreatedAt: created.UnixMilli()},
+		{PRNum: 2, CreatedAt: created.Add(-100 * time.Hour).UnixMilli()},
+		{PRNum: 3, CreatedAt: created.Add(2 * time.Hour).UnixMilli()},
+	}
+	fees := []Fee{{Chain: "binance", Token: "TWT-8C2", MinAmount: 672, Amount: 770}}
+	options := Options{TimeWindow: 72 * time.Hour, MinScore: 0.4, MaxSuggestions: 2}
+
+	tests := []struct {
+		name      string
+		tx        blockchain.Tx
+		linkedPRs map[int]bool
+		want      []Suggestion
+	}{
+		{
+			name: "Fee amount shortly after PR creation",
+			tx:   blockchain.Tx{Chain: "binance", Token: "TWT-8C2", Amount: 700, Date: created.Add(time.Hour).UnixMilli()},
+			want: []Suggestion{{PRNum: 1, Score: 0.5}},
+		},
+		{
+			name:      "Linked sender",
+			tx:        blockchain.Tx{Chain: "binance", Token: "TWT-8C2", Amount: 10, Date: created.Add(time.Hour).UnixMilli()},
+			linkedPRs: map[int]bool{2: true},
+			want:      []Suggestion{{PRNum: 2, Score: 0.5}},
+		},
+		{
+			name:      "Linked sender and fee amount, limited",
+			tx:        blockchain.Tx{Chain: "binance", Token: "twt-8c2", Amount: 700, Date: created.Add(3 * time.Hour).UnixMilli()},
+			linkedPRs: map[int]bool{2: true},
+			want:      []Suggestion{{PRNum: 2, Score: 0.8}, {PRNum: 3, Score: 0.5}},
+		},
+		{
+			name: "Fee amount of another chain",
+			tx:   blockchain.Tx{Chain: "smartchain", Token: "TWT-8C2", Amount: 700, Date: created.UnixMilli()},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Suggest(&tt.tx, candidates, tt.linkedPRs, fees, options)
+			if len(got) != len(tt.want) {
+				t.Fatalf("Suggest() = %+v, want %+v", got, tt.want)
+			}
+
+			for i := range got {
+				if got[i].PRNum != tt.want[i].PRNum || got[i].Score != tt.want[i].Score {
+					t.Errorf("Suggest()[%d] = %+v, want %+v", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestStore_Matches(t *testing.T) {
+	s := NewStore(storage.NewMemoryStore())
+	tx := &blockchain.Tx{Chain: "binance", Hash: "ABC", Memo: "4201", Amount: 700}
+
+	if err := s.AddSuggestion("binance/ABC", Suggestion{PRNum: 42, Score: 0.8}); err != nil {
+		t.Fatalf("AddSuggestion() error = %v", err)
+	}
+
+	suggested, err := s.IsSuggested("binance/ABC", 42)
+	if err != nil || !suggested {
+		t.Errorf("IsSuggested() = %v, %v, want true, nil", suggested, err)
+	}
+
+	if err = s.AddMatch("binance/ABC", 42, tx, "moderator"); err != nil {
+		t.Fatalf("AddMatch() error = %v", err)
+	}
+
+	txs, err := s.GetMatchedTransfers("binance", 42, "42")
+	if err != nil {
+		t.Fatalf("GetMatchedTransfers() error = %v", err)
+	}
+
+	if len(txs) != 1 || txs[0].Memo != "42" || txs[0].Hash != "ABC" {
+		t.Errorf("GetMatchedTransfers() = %+v", txs)
+	}
+
+	if txs, _ = s.GetMatchedTransfers("smartchain", 42, "42"); len(txs) != 0 {
+		t.Errorf("GetMatchedTransfers() of another chain = %+v", txs)
+	}
+}
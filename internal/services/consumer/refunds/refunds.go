@@ -0,0 +1,360 @@
+package refunds
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/trustwallet/assets-manager/internal/services/consumer/blockchain"
+	"github.com/trustwallet/assets-manager/internal/storage"
+)
+
+const (
+	bucketCases   = "refund_cases"
+	bucketScanned = "refund_scanned_txs"
+	bucketMeta    = "refund_meta"
+
+	metaScanSince  = "scan_since"
+	metaScanCursor = "scan_cursor/"
+)
+
+type Kind string
+
+const (
+	// KindOverpayment is a payment exceeding the fee, the surplus is refundable.
+	KindOverpayment Kind = "overpayment"
+	// KindClosedPR is a payment made for a pull request after it has been closed.
+	KindClosedPR Kind = "closed_pr"
+	// KindWrongMemo is a payment with a memo not matching any pull request.
+	KindWrongMemo Kind = "wrong_memo"
+)
+
+type Status string
+
+const (
+	StatusOpen Status = "open"
+	// StatusRefunding is a case claimed for a refund whose transfer may have been sent. It is never sent again.
+	StatusRefunding Status = "refunding"
+	StatusRefunded  Status = "refunded"
+	// StatusFailed is a case whose refund transfer failed, it can be refunded again.
+	StatusFailed    Status = "failed"
+	StatusDismissed Status = "dismissed"
+	// StatusMatched is a payment confirmed by a moderator as a payment for a pull request.
+	StatusMatched Status = "matched"
+)
+
+var (
+	ErrAlreadyProcessed = errors.New("refund case is already processed")
+	ErrRefundFailed     = errors.New("refund transfer failed")
+)
+
+type (
+	// Case is a payment which may have to be refunded.
+	Case struct {
+		ID          string      `json:"id"`
+		Kind        Kind        `json:"kind"`
+		Status      Status      `json:"status"`
+		PRNum       int         `json:"pr_num"`
+		Chain       string      `json:"chain"`
+		TxHash      string      `json:"tx_hash"`
+		FromAddress string      `json:"from_address"`
+		Token       string      `json:"token"`
+		Memo        string      `json:"memo"`
+		Amount      float64     `json:"amount"`
+		RefundLink  string      `json:"refund_link,omitempty"`
+		CreatedAt   time.Time   `json:"created_at"`
+		Audit       []AuditItem `json:"audit"`
+	}
+
+	// AuditItem is a record of an action performed on a refund case.
+	AuditItem struct {
+		Time    time.Time `json:"time"`
+		Actor   string    `json:"actor"`
+		Action  string    `json:"action"`
+		Details string    `json:"details,omitempty"`
+	}
+)
+
+// Registry stores refund cases with their audit trail.
+type Registry struct {
+	store storage.Store
+	mu    sync.Mutex
+	now   func() time.Time
+}
+
+func NewRegistry(store storage.Store) *Registry {
+	return &Registry{store: store, now: time.Now}
+}
+
+// CaseID returns a refund case ID of a payment transaction.
+func CaseID(tx *blockchain.Tx) string {
+	return fmt.Sprintf("%s/%s", tx.Chain, tx.Hash)
+}
+
+// NewCase returns a refund case of a payment transaction.
+func NewCase(kind Kind, prNum int, tx *blockchain.Tx, amount float64) *Case {
+	return &Case{
+		ID:          CaseID(tx),
+		Kind:        kind,
+		Status:      StatusOpen,
+		PRNum:       prNum,
+		Chain:       tx.Chain,
+		TxHash:      tx.Hash,
+		FromAddress: tx.FromAddress,
+		Token:       tx.Token,
+		Memo:        tx.Memo,
+		Amount:      amount,
+	}
+}
+
+// Record saves a new case. Returns false if the case has been recorded already.
+func (r *Registry) Record(c *Case) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var existing Case
+
+	err := r.store.Get(bucketCases, c.ID, &existing)
+	if err == nil {
+		return false, nil
+	}
+
+	if !errors.Is(err, storage.ErrNotFound) {
+		return false, fmt.Errorf("failed to read refund case: %w", err)
+	}
+
+	c.CreatedAt = r.now()
+	c.Audit = append(c.Audit, AuditItem{Time: c.CreatedAt, Actor: "bot", Action: "detected", Details: string(c.Kind)})
+
+	if err = r.store.Put(bucketCases, c.ID, c); err != nil {
+		return false, fmt.Errorf("failed to save refund case: %w", err)
+	}
+
+	return true, nil
+}
+
+// Get returns a case by ID or by transaction hash.
+func (r *Registry) Get(idOrHash string) (*Case, error) {
+	var c Case
+
+	err := r.store.Get(bucketCases, idOrHash, &c)
+	if err == nil {
+		return &c, nil
+	}
+
+	if !errors.Is(err, storage.ErrNotFound) {
+		return nil, fmt.Errorf("failed to read refund case: %w", err)
+	}
+
+	var found *Case
+
+	err = r.ForEach(func(candidate *Case) error {
+		if strings.EqualFold(candidate.TxHash, idOrHash) {
+			found = candidate
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if found == nil {
+		return nil, storage.ErrNotFound
+	}
+
+	return found, nil
+}
+
+// Refundable reports if a refund of the case can be started.
+func (c *Case) Refundable() bool {
+	return c.Status == StatusOpen || c.Status == StatusFailed
+}
+
+// ForEach calls fn for every recorded case.
+func (r *Registry) ForEach(fn func(c *Case) error) error {
+	return r.store.ForEach(bucketCases, func(_ string, value []byte) error {
+		var c Case
+		if err := json.Unmarshal(value, &c); err != nil {
+			return fmt.Errorf("failed to decode refund case: %w", err)
+		}
+
+		return fn(&c)
+	})
+}
+
+// AddAudit appends an audit item to a case.
+func (r *Registry) AddAudit(id, actor, action, details string) error {
+	return r.update(id, func(c *Case) error {
+		c.Audit = append(c.Audit, AuditItem{Time: r.now(), Actor: actor, Action: action, Details: details})
+
+		return nil
+	})
+}
+
+// SetStatus changes a status of an open case and records it in the audit trail.
+func (r *Registry) SetStatus(id string, status Status, actor, refundLink string) error {
+	return r.update(id, func(c *Case) error {
+		if c.Status != StatusOpen {
+			return ErrAlreadyProcessed
+		}
+
+		c.Status = status
+		c.RefundLink = refundLink
+		c.Audit = append(c.Audit, AuditItem{Time: r.now(), Actor: actor, Action: string(status), Details: refundLink})
+
+		return nil
+	})
+}
+
+// Refund claims a case and sends its refund with transfer. The case is moved to refunding before
+// the transfer, so a refund is never sent twice: if recording its result fails, the case stays refunding
+// and has to be resolved manually. Returns ErrRefundFailed if the transfer fails, the case is marked failed then.
+func (r *Registry) Refund(id, actor string, transfer func(c *Case) (string, error)) (*Case, error) {
+	var claimed Case
+
+	err := r.update(id, func(c *Case) error {
+		if !c.Refundable() {
+			return ErrAlreadyProcessed
+		}
+
+		c.Status = StatusRefunding
+		c.Audit = append(c.Audit, AuditItem{Time: r.now(), Actor: actor, Action: string(StatusRefunding), Details: c.FromAddress})
+		claimed = *c
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	link, err := transfer(&claimed)
+	if err != nil {
+		transferErr := fmt.Errorf("%w: %v", ErrRefundFailed, err)
+
+		if err = r.finish(id, StatusFailed, actor, err.Error()); err != nil {
+			return nil, fmt.Errorf("%v, failed to record it: %w", transferErr, err)
+		}
+
+		return nil, transferErr
+	}
+
+	if err = r.finish(id, StatusRefunded, actor, link); err != nil {
+		return nil, err
+	}
+
+	claimed.Status = StatusRefunded
+	claimed.RefundLink = link
+
+	return &claimed, nil
+}
+
+// finish records a result of a refund of a case claimed by Refund.
+func (r *Registry) finish(id string, status Status, actor, details string) error {
+	return r.update(id, func(c *Case) error {
+		if c.Status != StatusRefunding {
+			return ErrAlreadyProcessed
+		}
+
+		c.Status = status
+		if status == StatusRefunded {
+			c.RefundLink = details
+		}
+
+		c.Audit = append(c.Audit, AuditItem{Time: r.now(), Actor: actor, Action: string(status), Details: details})
+
+		return nil
+	})
+}
+
+// IsScanned reports if a ledger transaction has been already checked for refund cases.
+func (r *Registry) IsScanned(tx *blockchain.Tx) (bool, error) {
+	var scanned bool
+
+	err := r.store.Get(bucketScanned, CaseID(tx), &scanned)
+	if errors.Is(err, storage.ErrNotFound) {
+		return false, nil
+	}
+
+	if err != nil {
+		return false, fmt.Errorf("failed to read scanned transaction: %w", err)
+	}
+
+	return scanned, nil
+}
+
+// MarkScanned marks a ledger transaction as checked for refund cases.
+func (r *Registry) MarkScanned(tx *blockchain.Tx) error {
+	return r.store.Put(bucketScanned, CaseID(tx), true)
+}
+
+// ScanSince returns the time of the first scan. Payments made before it are not checked,
+// so that the history ingested by the ledger doesn't produce cases for long-settled payments.
+func (r *Registry) ScanSince() (int64, error) {
+	var since int64
+
+	err := r.store.Get(bucketMeta, metaScanSince, &since)
+	if err == nil {
+		return since, nil
+	}
+
+	if !errors.Is(err, storage.ErrNotFound) {
+		return 0, fmt.Errorf("failed to read refund scan time: %w", err)
+	}
+
+	since = r.now().UnixMilli()
+	if err = r.store.Put(bucketMeta, metaScanSince, since); err != nil {
+		return 0, fmt.Errorf("failed to save refund scan time: %w", err)
+	}
+
+	return since, nil
+}
+
+// ScanCursor returns the ledger sequence number of the last transaction of a chain checked for refund
+// cases, false if the chain has not been scanned by sequence yet.
+func (r *Registry) ScanCursor(chain string) (uint64, bool, error) {
+	var seq uint64
+
+	err := r.store.Get(bucketMeta, metaScanCursor+chain, &seq)
+	if errors.Is(err, storage.ErrNotFound) {
+		return 0, false, nil
+	}
+
+	if err != nil {
+		return 0, false, fmt.Errorf("failed to read refund scan cursor: %w", err)
+	}
+
+	return seq, true, nil
+}
+
+// SaveScanCursor saves the ledger sequence number of the last transaction of a chain checked for refund cases.
+func (r *Registry) SaveScanCursor(chain string, seq uint64) error {
+	if err := r.store.Put(bucketMeta, metaScanCursor+chain, seq); err != nil {
+		return fmt.Errorf("failed to save refund scan cursor: %w", err)
+	}
+
+	return nil
+}
+
+func (r *Registry) update(id string, fn func(c *Case) error) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var c Case
+	if err := r.store.Get(bucketCases, id, &c); err != nil {
+		return fmt.Errorf("failed to read refund case %s: %w", id, err)
+	}
+
+	if err := fn(&c); err != nil {
+		return err
+	}
+
+	if err := r.store.Put(bucketCases, id, &c); err != nil {
+		return fmt.Errorf("failed to save refund case: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,158 @@
+package refunds
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/trustwallet/assets-manager/internal/services/consumer/blockchain"
+	"github.com/trustwallet/assets-manager/internal/storage"
+)
+
+func TestRegistry_CaseLifecycle(t *testing.T) {
+	r := NewRegistry(storage.NewMemoryStore())
+	tx := &blockchain.Tx{Chain: "binance", Hash: "ABC", FromAddress: "bnb1sender", Token: "TWT-8C2", Amount: 800}
+
+	recorded, err := r.Record(NewCase(KindOverpayment, 42, tx, 100))
+	if err != nil || !recorded {
+		t.Fatalf("Record() = %v, %v, want true, nil", recorded, err)
+	}
+
+	recorded, err = r.Record(NewCase(KindOverpayment, 42, tx, 100))
+	if err != nil || recorded {
+		t.Fatalf("Record() of a duplicate = %v, %v, want false, nil", recorded, err)
+	}
+
+	c, err := r.Get("abc")
+	if err != nil {
+		t.Fatalf("Get() by tx hash error = %v", err)
+	}
+
+	if c.ID != "binance/ABC" || c.Amount != 100 || c.Status != StatusOpen {
+		t.Errorf("Get() = %+v", c)
+	}
+
+	if err = r.SetStatus(c.ID, StatusRefunded, "moderator", "link"); err != nil {
+		t.Fatalf("SetStatus() error = %v", err)
+	}
+
+	if err = r.SetStatus(c.ID, StatusRefunded, "moderator", "link"); !errors.Is(err, ErrAlreadyProcessed) {
+		t.Errorf("SetStatus() of a processed case error = %v, want %v", err, ErrAlreadyProcessed)
+	}
+
+	c, err = r.Get(c.ID)
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+
+	if len(c.Audit) != 2 || c.Audit[1].Actor != "moderator" || c.RefundLink != "link" {
+		t.Errorf("Get() audit = %+v", c.Audit)
+	}
+}
+
+func TestRegistry_Get_NotFound(t *testing.T) {
+	r := NewRegistry(storage.NewMemoryStore())
+
+	if _, err := r.Get("missing"); !errors.Is(err, storage.ErrNotFound) {
+		t.Errorf("Get() error = %v, want %v", err, storage.ErrNotFound)
+	}
+}
+
+// failingStore fails to save refund cases with a given status.
+type failingStore struct {
+	storage.Store
+	status Status
+}
+
+func (s failingStore) Put(bucket, key string, v interface{}) error {
+	if c, ok := v.(*Case); ok && c.Status == s.status {
+		return errors.New("storage is unavailable")
+	}
+
+	return s.Store.Put(bucket, key, v)
+}
+
+func TestRegistry_Refund(t *testing.T) {
+	store := failingStore{Store: storage.NewMemoryStore(), status: StatusRefunded}
+	r := NewRegistry(store)
+	tx := &blockchain.Tx{Chain: "binance", Hash: "ABC", FromAddress: "bnb1sender", Token: "TWT-8C2", Amount: 800}
+
+	if _, err := r.Record(NewCase(KindOverpayment, 42, tx, 100)); err != nil {
+		t.Fatalf("Record() error = %v", err)
+	}
+
+	transfers := 0
+	transfer := func(c *Case) (string, error) {
+		transfers++
+
+		return "link", nil
+	}
+
+	if _, err := r.Refund("binance/ABC", "moderator", transfer); err == nil {
+		t.Fatal("Refund() with a failing storage error = nil")
+	}
+
+	// A retry of the command must not send the refund again.
+	if _, err := r.Refund("binance/ABC", "moderator", transfer); !errors.Is(err, ErrAlreadyProcessed) {
+		t.Errorf("Refund() of a refunding case error = %v, want %v", err, ErrAlreadyProcessed)
+	}
+
+	if transfers != 1 {
+		t.Errorf("Refund() transfers = %d, want 1", transfers)
+	}
+
+	c, err := r.Get("binance/ABC")
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+
+	if c.Status != StatusRefunding {
+		t.Errorf("Get() status = %s, want %s", c.Status, StatusRefunding)
+	}
+}
+
+func TestRegistry_Refund_TransferFailed(t *testing.T) {
+	r := NewRegistry(storage.NewMemoryStore())
+	tx := &blockchain.Tx{Chain: "binance", Hash: "ABC", FromAddress: "bnb1sender", Token: "TWT-8C2", Amount: 800}
+
+	if _, err := r.Record(NewCase(KindOverpayment, 42, tx, 100)); err != nil {
+		t.Fatalf("Record() error = %v", err)
+	}
+
+	_, err := r.Refund("binance/ABC", "moderator", func(c *Case) (string, error) {
+		return "", errors.New("insufficient funds")
+	})
+	if !errors.Is(err, ErrRefundFailed) {
+		t.Fatalf("Refund() error = %v, want %v", err, ErrRefundFailed)
+	}
+
+	c, err := r.Refund("binance/ABC", "moderator", func(c *Case) (string, error) {
+		return "link", nil
+	})
+	if err != nil {
+		t.Fatalf("Refund() of a failed case error = %v", err)
+	}
+
+	if c.Status != StatusRefunded || c.RefundLink != "link" {
+		t.Errorf("Refund() = %+v", c)
+	}
+}
+
+func TestRegistry_ScanCursor(t *testing.T) {
+	r := NewRegistry(storage.NewMemoryStore())
+
+	if seq, ok, err := r.ScanCursor("binance"); err != nil || ok || seq != 0 {
+		t.Fatalf("ScanCursor() = %d, %v, %v, want no cursor", seq, ok, err)
+	}
+
+	if err := r.SaveScanCursor("binance", 7); err != nil {
+		t.Fatalf("SaveScanCursor() error = %v", err)
+	}
+
+	if seq, ok, err := r.ScanCursor("binance"); err != nil || !ok || seq != 7 {
+		t.Errorf("ScanCursor() = %d, %v, %v, want 7", seq, ok, err)
+	}
+
+	if _, ok, err := r.ScanCursor("bsc"); err != nil || ok {
+		t.Errorf("ScanCursor() of another chain = %v, %v, want no cursor", ok, err)
+	}
+}
@@ -0,0 +1,194 @@
+// Package snapshot reads repository files at one commit, so PR checks see the head of a PR as a whole
+// instead of files fetched one by one from raw URLs, which may be cached or changed meanwhile.
+package snapshot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"syscall"
+
+	gh "github.com/google/go-github/v38/github"
+)
+
+var ErrNotFound = errors.New("file not found")
+
+// Tree is a snapshot of repository files. Paths are slash separated and relative to the repository root.
+type Tree interface {
+	// ReadFile returns content of a file, ErrNotFound if there is no such file in the snapshot.
+	ReadFile(ctx context.Context, name string) ([]byte, error)
+	// ReadDir returns sorted names of files and directories of a directory,
+	// ErrNotFound if there is no such directory in the snapshot.
+	ReadDir(ctx context.Context, name string) ([]string, error)
+}
+
+// Dir is a snapshot of a directory, a checkout of a repository or a fixture of tests.
+type Dir struct {
+	root string
+}
+
+func NewDir(root string) *Dir {
+	return &Dir{root: root}
+}
+
+func (d *Dir) ReadFile(_ context.Context, name string) ([]byte, error) {
+	// Cleaned from the root, the name can't point out of the directory.
+	file := filepath.Join(d.root, filepath.FromSlash(path.Clean("/"+name)))
+
+	// Directories and paths through files are not found, as in Git trees.
+	info, err := os.Stat(file)
+	if err != nil || !info.Mode().IsRegular() {
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, name)
+	}
+
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", name, err)
+	}
+
+	return data, nil
+}
+
+func (d *Dir) ReadDir(_ context.Context, name string) ([]string, error) {
+	entries, err := os.ReadDir(filepath.Join(d.root, filepath.FromSlash(path.Clean("/"+name))))
+	if errors.Is(err, os.ErrNotExist) || errors.Is(err, syscall.ENOTDIR) {
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, name)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", name, err)
+	}
+
+	names := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+	}
+
+	return names, nil
+}
+
+// GitData is the part of the Git Data API the snapshot is read with.
+type GitData interface {
+	GetTree(ctx context.Context, owner, repo, sha string) (*gh.Tree, error)
+	GetBlobRaw(ctx context.Context, owner, repo, sha string) ([]byte, error)
+}
+
+// Git is a snapshot of a commit read by the Git Data API. Trees are listed lazily along the paths read,
+// so only the directories of the checked files are requested, and kept for the next reads.
+type Git struct {
+	client GitData
+	owner  string
+	repo   string
+	sha    string
+
+	mu    sync.Mutex
+	trees map[string]map[string]*gh.TreeEntry
+}
+
+// NewGit returns a snapshot of a commit. Commits of PRs from forks are read from the base repository,
+// which keeps objects of its PRs.
+func NewGit(client GitData, owner, repo, sha string) *Git {
+	return &Git{
+		client: client,
+		owner:  owner,
+		repo:   repo,
+		sha:    sha,
+		trees:  make(map[string]map[string]*gh.TreeEntry),
+	}
+}
+
+func (g *Git) ReadFile(ctx context.Context, name string) ([]byte, error) {
+	dir, base := path.Split(path.Clean("/" + name))
+
+	entries, err := g.dir(ctx, dir)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", err, name)
+	}
+
+	entry, ok := entries[base]
+	if !ok || entry.GetType() != "blob" {
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, name)
+	}
+
+	data, err := g.client.GetBlobRaw(ctx, g.owner, g.repo, entry.GetSHA())
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", name, err)
+	}
+
+	return data, nil
+}
+
+func (g *Git) ReadDir(ctx context.Context, name string) ([]string, error) {
+	entries, err := g.dir(ctx, name)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", err, name)
+	}
+
+	names := make([]string, 0, len(entries))
+	for entryName := range entries {
+		names = append(names, entryName)
+	}
+
+	sort.Strings(names)
+
+	return names, nil
+}
+
+// dir returns entries of a directory, listing the trees along its path from the root.
+func (g *Git) dir(ctx context.Context, name string) (map[string]*gh.TreeEntry, error) {
+	entries, err := g.tree(ctx, "", g.sha)
+	if err != nil {
+		return nil, err
+	}
+
+	dir := ""
+
+	for _, part := range strings.Split(strings.Trim(path.Clean("/"+name), "/"), "/") {
+		if part == "" {
+			continue
+		}
+
+		entry, ok := entries[part]
+		if !ok || entry.GetType() != "tree" {
+			return nil, ErrNotFound
+		}
+
+		dir = path.Join(dir, part)
+
+		if entries, err = g.tree(ctx, dir, entry.GetSHA()); err != nil {
+			return nil, err
+		}
+	}
+
+	return entries, nil
+}
+
+// tree returns entries of a directory by name.
+func (g *Git) tree(ctx context.Context, dir, sha string) (map[string]*gh.TreeEntry, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if entries, ok := g.trees[dir]; ok {
+		return entries, nil
+	}
+
+	tree, err := g.client.GetTree(ctx, g.owner, g.repo, sha)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list %q: %w", dir, err)
+	}
+
+	entries := make(map[string]*gh.TreeEntry, len(tree.Entries))
+	for _, entry := range tree.Entries {
+		entries[entry.GetPath()] = entry
+	}
+
+	g.trees[dir] = entries
+
+	return entries, nil
+}
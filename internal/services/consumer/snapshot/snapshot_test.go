@@ -0,0 +1,143 @@
+package snapshot
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	gh "github.com/google/go-github/v38/github"
+)
+
+type fakeGitData struct {
+	trees map[string][]*gh.TreeEntry
+	blobs map[string]string
+	calls int
+}
+
+func (f *fakeGitData) GetTree(_ context.Context, _, _, sha string) (*gh.Tree, error) {
+	f.calls++
+
+	entries, ok := f.trees[sha]
+	if !ok {
+		return nil, errors.New("not found")
+	}
+
+	return &gh.Tree{SHA: gh.String(sha), Entries: entries}, nil
+}
+
+func (f *fakeGitData) GetBlobRaw(_ context.Context, _, _, sha string) ([]byte, error) {
+	blob, ok := f.blobs[sha]
+	if !ok {
+		return nil, errors.New("not found")
+	}
+
+	return []byte(blob), nil
+}
+
+func entry(name, typ, sha string) *gh.TreeEntry {
+	return &gh.TreeEntry{Path: gh.String(name), Type: gh.String(typ), SHA: gh.String(sha)}
+}
+
+func Test_ReadFile(t *testing.T) {
+	root := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(root, "blockchains", "ethereum"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	err := os.WriteFile(filepath.Join(root, "blockchains", "ethereum", "info.json"), []byte("info"), 0o600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	git := NewGit(&fakeGitData{
+		trees: map[string][]*gh.TreeEntry{
+			"head":        {entry("blockchains", "tree", "blockchains")},
+			"blockchains": {entry("ethereum", "tree", "ethereum")},
+			"ethereum":    {entry("info.json", "blob", "info")},
+		},
+		blobs: map[string]string{"info": "info"},
+	}, "owner", "repo", "head")
+
+	tests := []struct {
+		name    string
+		path    string
+		want    string
+		wantErr error
+	}{
+		{name: "File", path: "blockchains/ethereum/info.json", want: "info"},
+		{name: "Missing file", path: "blockchains/ethereum/logo.png", wantErr: ErrNotFound},
+		{name: "Missing directory", path: "blockchains/bitcoin/info.json", wantErr: ErrNotFound},
+		{name: "Directory", path: "blockchains/ethereum", wantErr: ErrNotFound},
+		{name: "File as directory", path: "blockchains/ethereum/info.json/logo.png", wantErr: ErrNotFound},
+		{name: "Out of root", path: "../../blockchains/ethereum/info.json", want: "info"},
+	}
+
+	for _, tree := range []Tree{NewDir(root), git} {
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				data, err := tree.ReadFile(context.Background(), tt.path)
+				if tt.wantErr != nil {
+					if !errors.Is(err, tt.wantErr) {
+						t.Errorf("ReadFile() error = %v, want %v", err, tt.wantErr)
+					}
+
+					return
+				}
+
+				if err != nil || string(data) != tt.want {
+					t.Errorf("ReadFile() = %q, %v, want %q", data, err, tt.want)
+				}
+			})
+		}
+	}
+}
+
+func Test_ReadDir(t *testing.T) {
+	root := t.TempDir()
+	for _, name := range []string{"0xB", "0xA"} {
+		if err := os.MkdirAll(filepath.Join(root, "assets", name), 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	git := NewGit(&fakeGitData{
+		trees: map[string][]*gh.TreeEntry{
+			"head":   {entry("assets", "tree", "assets")},
+			"assets": {entry("0xB", "tree", "b"), entry("0xA", "tree", "a")},
+		},
+	}, "owner", "repo", "head")
+
+	for _, tree := range []Tree{NewDir(root), git} {
+		names, err := tree.ReadDir(context.Background(), "assets")
+		if err != nil || len(names) != 2 || names[0] != "0xA" || names[1] != "0xB" {
+			t.Errorf("ReadDir() = %v, %v, want [0xA 0xB]", names, err)
+		}
+
+		if _, err = tree.ReadDir(context.Background(), "assets/0xC"); !errors.Is(err, ErrNotFound) {
+			t.Errorf("ReadDir() error = %v, want %v", err, ErrNotFound)
+		}
+	}
+}
+
+func Test_Git_ListsTreesOnce(t *testing.T) {
+	client := &fakeGitData{
+		trees: map[string][]*gh.TreeEntry{
+			"head": {entry("a.json", "blob", "a"), entry("b.json", "blob", "b")},
+		},
+		blobs: map[string]string{"a": "a", "b": "b"},
+	}
+
+	git := NewGit(client, "owner", "repo", "head")
+
+	for _, name := range []string{"a.json", "b.json", "a.json"} {
+		if _, err := git.ReadFile(context.Background(), name); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if client.calls != 1 {
+		t.Errorf("GetTree() calls = %d, want 1", client.calls)
+	}
+}
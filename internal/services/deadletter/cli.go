@@ -0,0 +1,143 @@
+package deadletter
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"text/tabwriter"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/trustwallet/assets-manager/internal/config"
+	"github.com/trustwallet/assets-manager/internal/queue"
+)
+
+const usage = `Usage: deadletter <command> [arguments]
+
+Commands:
+  list               list dead-lettered Github events
+  inspect <id>       print a dead-lettered Github event
+  replay <id>...     publish dead-lettered Github events back to the process queue
+  replay --all       publish all dead-lettered Github events back to the process queue
+`
+
+var ErrUsage = errors.New("invalid arguments")
+
+// Run executes a dead-letter queue command and writes its output to out.
+func Run(args []string, out io.Writer) error {
+	if len(args) == 0 {
+		fmt.Fprint(out, usage)
+
+		return ErrUsage
+	}
+
+	config.SetConfig()
+
+	client, err := queue.NewDeadLetterClient(config.Default.Rabbitmq.URL)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if closeErr := client.Close(); closeErr != nil {
+			log.WithError(closeErr).Error("failed to close mq client")
+		}
+	}()
+
+	var letters []queue.DeadLetter
+
+	switch args[0] {
+	case "list":
+		if letters, err = client.List(); err != nil {
+			return err
+		}
+
+		return printList(out, letters)
+	case "inspect":
+		if len(args) != 2 {
+			break
+		}
+
+		if letters, err = client.List(); err != nil {
+			return err
+		}
+
+		for i := range letters {
+			if letters[i].ID == args[1] {
+				return printLetter(out, &letters[i])
+			}
+		}
+
+		return fmt.Errorf("dead letter %s not found", args[1])
+	case "replay":
+		if len(args) < 2 {
+			break
+		}
+
+		ids := args[1:]
+		if len(ids) == 1 && ids[0] == "--all" {
+			ids = nil
+		}
+
+		letters, err = client.Replay(ids)
+		for i := range letters {
+			fmt.Fprintf(out, "replayed %s\n", letters[i].ID)
+		}
+
+		return err
+	}
+
+	fmt.Fprint(out, usage)
+
+	return ErrUsage
+}
+
+func printList(out io.Writer, letters []queue.DeadLetter) error {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tFAILED AT\tATTEMPTS\tTYPE\tPR\tERROR")
+
+	for i := range letters {
+		eventType, prNum := describe(&letters[i])
+		fmt.Fprintf(w, "%s\t%s\t%d\t%s\t%s\t%s\n", letters[i].ID, letters[i].FailedAt.Format(time.RFC3339),
+			letters[i].Attempts, eventType, prNum, letters[i].Error)
+	}
+
+	return w.Flush()
+}
+
+func printLetter(out io.Writer, letter *queue.DeadLetter) error {
+	data, err := json.MarshalIndent(letter, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal dead letter: %w", err)
+	}
+
+	_, err = fmt.Fprintln(out, string(data))
+
+	return err
+}
+
+// describe returns the type and PR number of a dead-lettered event, "?" if unknown.
+func describe(letter *queue.DeadLetter) (eventType, prNum string) {
+	event, err := letter.Event()
+	if err != nil {
+		return "?", "?"
+	}
+
+	num := 0
+
+	switch {
+	case event.PullRequest != nil:
+		num = event.PullRequest.GetPullRequest().GetNumber()
+	case event.IssueComment != nil:
+		num = event.IssueComment.GetIssue().GetNumber()
+	case event.PullRequestReviewComment != nil:
+		num = event.PullRequestReviewComment.GetPullRequest().GetNumber()
+	}
+
+	if num == 0 {
+		return event.Type, "?"
+	}
+
+	return event.Type, fmt.Sprintf("#%d", num)
+}
@@ -0,0 +1,157 @@
+package storage
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// MemoryStore keeps data in memory only. It is used in tests and when persistence is not needed.
+type MemoryStore struct {
+	mu      sync.RWMutex
+	buckets map[string]map[string][]byte
+	// updating serializes transactions, which read committed data without holding mu.
+	updating sync.Mutex
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{buckets: make(map[string]map[string][]byte)}
+}
+
+func (s *MemoryStore) Get(bucket, key string, v interface{}) error {
+	s.mu.RLock()
+	data, ok := s.buckets[bucket][key]
+	s.mu.RUnlock()
+
+	if !ok {
+		return ErrNotFound
+	}
+
+	return json.Unmarshal(data, v)
+}
+
+func (s *MemoryStore) Put(bucket, key string, v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("failed to marshal value: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.buckets[bucket]; !ok {
+		s.buckets[bucket] = make(map[string][]byte)
+	}
+
+	s.buckets[bucket][key] = data
+
+	return nil
+}
+
+func (s *MemoryStore) Delete(bucket, key string) error {
+	s.mu.Lock()
+	delete(s.buckets[bucket], key)
+	s.mu.Unlock()
+
+	return nil
+}
+
+func (s *MemoryStore) ForEach(bucket string, fn func(key string, value []byte) error) error {
+	s.mu.RLock()
+	keys := make([]string, 0, len(s.buckets[bucket]))
+	values := make(map[string][]byte, len(s.buckets[bucket]))
+
+	for k, v := range s.buckets[bucket] {
+		keys = append(keys, k)
+		values[k] = v
+	}
+	s.mu.RUnlock()
+
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		if err := fn(k, values[k]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *MemoryStore) Update(fn func(tx Tx) error) error {
+	s.updating.Lock()
+	defer s.updating.Unlock()
+
+	tx := &memoryTx{store: s, changes: make(map[string]map[string][]byte)}
+	if err := fn(tx); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for bucket, changes := range tx.changes {
+		if _, ok := s.buckets[bucket]; !ok {
+			s.buckets[bucket] = make(map[string][]byte)
+		}
+
+		for key, data := range changes {
+			if data == nil {
+				delete(s.buckets[bucket], key)
+			} else {
+				s.buckets[bucket][key] = data
+			}
+		}
+	}
+
+	return nil
+}
+
+func (s *MemoryStore) Close() error {
+	return nil
+}
+
+// memoryTx keeps changes of a transaction until it's committed, nil data is a deleted key.
+type memoryTx struct {
+	store   *MemoryStore
+	changes map[string]map[string][]byte
+}
+
+func (t *memoryTx) Get(bucket, key string, v interface{}) error {
+	data, changed := t.changes[bucket][key]
+	if !changed {
+		return t.store.Get(bucket, key, v)
+	}
+
+	if data == nil {
+		return ErrNotFound
+	}
+
+	return json.Unmarshal(data, v)
+}
+
+func (t *memoryTx) Put(bucket, key string, v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("failed to marshal value: %w", err)
+	}
+
+	t.set(bucket, key, data)
+
+	return nil
+}
+
+func (t *memoryTx) Delete(bucket, key string) error {
+	t.set(bucket, key, nil)
+
+	return nil
+}
+
+func (t *memoryTx) set(bucket, key string, data []byte) {
+	if _, ok := t.changes[bucket]; !ok {
+		t.changes[bucket] = make(map[string][]byte)
+	}
+
+	t.changes[bucket][key] = data
+}
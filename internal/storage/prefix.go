@@ -0,0 +1,61 @@
+package storage
+
+// PrefixStore is a store with bucket names prefixed, so several owners can keep
+// the same buckets in one storage.
+type PrefixStore struct {
+	store  Store
+	prefix string
+}
+
+// WithPrefix returns a store which prefixes bucket names. An empty prefix returns the store itself.
+func WithPrefix(store Store, prefix string) Store {
+	if prefix == "" {
+		return store
+	}
+
+	return &PrefixStore{store: store, prefix: prefix}
+}
+
+func (s *PrefixStore) Get(bucket, key string, v interface{}) error {
+	return s.store.Get(s.prefix+bucket, key, v)
+}
+
+func (s *PrefixStore) Put(bucket, key string, v interface{}) error {
+	return s.store.Put(s.prefix+bucket, key, v)
+}
+
+func (s *PrefixStore) Delete(bucket, key string) error {
+	return s.store.Delete(s.prefix+bucket, key)
+}
+
+func (s *PrefixStore) ForEach(bucket string, fn func(key string, value []byte) error) error {
+	return s.store.ForEach(s.prefix+bucket, fn)
+}
+
+func (s *PrefixStore) Update(fn func(tx Tx) error) error {
+	return s.store.Update(func(tx Tx) error {
+		return fn(&prefixTx{tx: tx, prefix: s.prefix})
+	})
+}
+
+// Close is a no-op, the underlying store is closed by its owner.
+func (s *PrefixStore) Close() error {
+	return nil
+}
+
+type prefixTx struct {
+	tx     Tx
+	prefix string
+}
+
+func (t *prefixTx) Get(bucket, key string, v interface{}) error {
+	return t.tx.Get(t.prefix+bucket, key, v)
+}
+
+func (t *prefixTx) Put(bucket, key string, v interface{}) error {
+	return t.tx.Put(t.prefix+bucket, key, v)
+}
+
+func (t *prefixTx) Delete(bucket, key string) error {
+	return t.tx.Delete(t.prefix+bucket, key)
+}
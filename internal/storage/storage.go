@@ -0,0 +1,50 @@
+package storage
+
+import (
+	"errors"
+	"fmt"
+)
+
+const (
+	DriverBolt   = "bolt"
+	DriverMemory = "memory"
+)
+
+// ErrNotFound is returned when a key does not exist in a bucket.
+var ErrNotFound = errors.New("key not found")
+
+// Store is a key-value storage organized in buckets. Values are stored JSON-encoded.
+type Store interface {
+	// Get decodes a value stored by key into v. Returns ErrNotFound if the key does not exist.
+	Get(bucket, key string, v interface{}) error
+	// Put encodes v and stores it by key, overwriting the previous value.
+	Put(bucket, key string, v interface{}) error
+	// Delete removes a key from a bucket. Deleting a missing key is not an error.
+	Delete(bucket, key string) error
+	// ForEach calls fn for every key of a bucket in ascending key order.
+	ForEach(bucket string, fn func(key string, value []byte) error) error
+	// Update runs fn in a read-write transaction: its changes are written together, or not at all
+	// if fn returns an error. Concurrent updates are serialized.
+	Update(fn func(tx Tx) error) error
+	// Close releases resources held by the storage.
+	Close() error
+}
+
+// Tx is a read-write transaction of a store.
+type Tx interface {
+	Get(bucket, key string, v interface{}) error
+	Put(bucket, key string, v interface{}) error
+	Delete(bucket, key string) error
+}
+
+// New returns a storage by driver name. An embedded bolt database is used by default.
+func New(driver, path string) (Store, error) {
+	switch driver {
+	case DriverMemory:
+		return NewMemoryStore(), nil
+	case DriverBolt, "":
+		return NewBoltStore(path)
+	default:
+		return nil, fmt.Errorf("unknown storage driver: %s", driver)
+	}
+}